@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
 	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/internal/cloud"
+	_ "github.com/eadydb/k8s-aim/internal/cloud/aliyun"    // 注册阿里云实现
+	_ "github.com/eadydb/k8s-aim/internal/cloud/fake"      // 注册内存模拟实现
+	_ "github.com/eadydb/k8s-aim/internal/cloud/qingcloud" // 注册青云实现
+	_ "github.com/eadydb/k8s-aim/internal/cloud/tencent"   // 注册腾讯云实现
 	"github.com/eadydb/k8s-aim/pkg/k8s"
 	"github.com/eadydb/k8s-aim/pkg/zlog"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 )
 
 func main() {
 
-
 	// init configuration
 	c := &config.Config{}
 	c.Init()
@@ -23,6 +32,39 @@ func main() {
 	}
 	kClient.Init()
 
+	// 进程收到 SIGINT/SIGTERM 时停止后台任务
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// init cloud provider
+	server, err := cloud.NewNodeServer(c, kClient)
+	if err != nil {
+		zlog.Errorf("init cloud provider failed, %s", err)
+	}
+	var wg sync.WaitGroup
+	if server != nil && c.GC != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.RunGC(ctx)
+		}()
+	}
+	if server != nil && c.KeyRotation != nil && c.KeyRotation.MaxAge > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.RunKeyRotation(ctx)
+		}()
+	}
+	if server != nil && c.Spot != nil && c.Spot.Enabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.Watch(ctx, server.SpotNodes); err != nil {
+				zlog.Warnf("spot termination notices are not watched, %s", err)
+			}
+		}()
+	}
 
 	// 测试kubernetes集群
 	deployment, err := kClient.ClientSet.AppsV1().Deployments(c.Kubernetes.KubeConfig).List(kClient.Ctx, metav1.ListOptions{})
@@ -34,4 +76,6 @@ func main() {
 	for _, deploy := range deployment.Items {
 		zlog.Debugw(deploy.Name, zap.String("deployment", deploy.Name))
 	}
+
+	wg.Wait()
 }
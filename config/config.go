@@ -5,12 +5,61 @@ import (
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"os"
+	"time"
 )
 
 // Tencent 腾讯云配置
 type Tencent struct {
-	SecretId  string `yaml:"secret_id"`  // 用于标识 API 调用者身份
-	SecretKey string `yaml:"secret_key"` // 用于加密签名字符串和服务器端验证签名字符串的密钥
+	Region           string `yaml:"region"`            // 地域
+	SecretId         string `yaml:"secret_id"`         // 用于标识 API 调用者身份
+	SecretKey        string `yaml:"secret_key"`        // 用于加密签名字符串和服务器端验证签名字符串的密钥
+	Token            string `yaml:"token"`             // 临时凭证 token
+	CredentialsFile  string `yaml:"credentials_file"`  // 凭证文件路径, 默认 ~/.tencentcloud/credentials
+	Profile          string `yaml:"profile"`           // 凭证文件中的配置名称, 默认 default
+	RoleName         string `yaml:"role_name"`         // CVM 实例绑定的角色名称, 为空时自动查询
+	MetadataEndpoint string `yaml:"metadata_endpoint"` // CVM 元数据服务地址
+	RoleArn          string `yaml:"role_arn"`          // 通过 STS 扮演的角色
+	RoleSessionName  string `yaml:"role_session_name"` // STS 临时会话名称
+	StsEndpoint      string `yaml:"sts_endpoint"`      // STS 服务地址
+}
+
+// AliYun 阿里云配置
+type AliYun struct {
+	Region          string `yaml:"region"`            // 地域, 如 cn-hangzhou
+	AccessKeyId     string `yaml:"access_key_id"`     // AccessKey ID
+	AccessKeySecret string `yaml:"access_key_secret"` // AccessKey Secret
+	SecurityToken   string `yaml:"security_token"`    // STS 临时凭证 token
+	Endpoint        string `yaml:"endpoint"`          // ECS 服务地址, 默认 https://ecs.{region}.aliyuncs.com
+	VpcEndpoint     string `yaml:"vpc_endpoint"`      // 专有网络服务地址, 默认 https://vpc.{region}.aliyuncs.com
+	VpcId           string `yaml:"vpc_id"`            // 创建安全组时使用的专有网络
+}
+
+// QingCloud 青云配置
+type QingCloud struct {
+	Zone            string `yaml:"zone"`              // 区域, 如 pek3、sh1a
+	AccessKeyId     string `yaml:"access_key_id"`     // API 密钥ID
+	SecretAccessKey string `yaml:"secret_access_key"` // API 密钥私钥
+	Endpoint        string `yaml:"endpoint"`          // IaaS 服务地址, 私有云需指定, 默认 https://api.qingcloud.com/iaas/
+}
+
+// FakeQuota 模拟云厂商资源配额, 为0时不限
+type FakeQuota struct {
+	Instances      int `yaml:"instances"`       // 未销毁的实例数
+	Addresses      int `yaml:"addresses"`       // 弹性公网IP数
+	SecurityGroups int `yaml:"security_groups"` // 安全组数
+	KeyPairs       int `yaml:"key_pairs"`       // 密钥对数
+}
+
+// Fake 内存模拟云厂商配置, 用于测试、CI 及离线演示
+type Fake struct {
+	Zones            []string           `yaml:"zones"`             // 可用区, 默认 fake-zone-1、fake-zone-2
+	UnavailableZones []string           `yaml:"unavailable_zones"` // 不可用的可用区
+	Latency          time.Duration      `yaml:"latency"`           // 实例状态转换耗时, 如 30s
+	CallLatency      time.Duration      `yaml:"call_latency"`      // 每次接口调用耗时
+	Quota            FakeQuota          `yaml:"quota"`             // 资源配额
+	FailureRates     map[string]float64 `yaml:"failure_rates"`     // 按方法名称随机注入失败的概率, 如 CreateInstance: 0.1
+	SoldOut          []string           `yaml:"sold_out"`          // 售罄的机型, 格式为 zone/instanceType, 如 fake-zone-1/S1.LARGE8
+	Seed             int64              `yaml:"seed"`              // 随机数种子, 为0时使用当前时间
 }
 
 // Kubernetes kubernetes 相关配置
@@ -20,11 +69,113 @@ type Kubernetes struct {
 	Token      string `yaml:"token"`       // kubernetes Token
 }
 
+// KeyStore 私钥存储配置
+type KeyStore struct {
+	Type   string `yaml:"type"`   // 存储方式, file 或 secret, 默认 file
+	Dir    string `yaml:"dir"`    // file 方式的存储目录, 默认 ~/.k8s-aim/keys
+	Prefix string `yaml:"prefix"` // secret 方式的 Secret 名称前缀, 默认 k8s-aim-key-
+}
+
+// KeyRotation 节点密钥轮换配置
+type KeyRotation struct {
+	Interval  time.Duration `yaml:"interval"`   // 检查间隔, 默认24h
+	MaxAge    time.Duration `yaml:"max_age"`    // 密钥对创建超过该时间后轮换, 为0时不轮换
+	ForceStop bool          `yaml:"force_stop"` // 需关机绑定密钥的实例软关机失败后强制关机
+}
+
+// TokenStore 创建实例幂等令牌存储配置
+type TokenStore struct {
+	Dir string `yaml:"dir"` // 存储目录, 默认 ~/.k8s-aim/tokens
+}
+
+// Placement 新节点的可用区分布策略
+type Placement struct {
+	Policy       string         `yaml:"policy"`        // balanced、pack、weighted、pinned, 为空时使用节点模板中的可用区
+	Zones        []string       `yaml:"zones"`         // 候选可用区, 按优先级排列, pinned 策略必填
+	Weights      map[string]int `yaml:"weights"`       // weighted 策略各可用区权重
+	MaxPerZone   int            `yaml:"max_per_zone"`  // pack 策略每个可用区的节点数上限
+	NodeSelector string         `yaml:"node_selector"` // 统计已有节点可用区分布时的标签选择器, 为空时统计全部节点
+}
+
+// Spot 竞价实例配置
+type Spot struct {
+	Enabled      bool          `yaml:"enabled"`       // 是否创建竞价实例
+	MaxPrice     float64       `yaml:"max_price"`     // 最高出价, 单位元/小时, 为0时使用云厂商默认出价, 腾讯云使用按量计费单价
+	SpotType     string        `yaml:"spot_type"`     // 竞价请求类型, 如腾讯云 one-time
+	PollInterval time.Duration `yaml:"poll_interval"` // 检查回收通知的间隔, 默认5s
+	GracePeriod  time.Duration `yaml:"grace_period"`  // 驱逐 Pod 的优雅退出时间, 为0时使用 Pod 自身配置
+	DrainTimeout time.Duration `yaml:"drain_timeout"` // 驱逐节点超时时间, 默认90s
+}
+
+// Disk 磁盘配置
+type Disk struct {
+	Type   string `yaml:"type"`    // 磁盘类型, 取值与云厂商相关, 为空时使用默认类型
+	SizeGB int64  `yaml:"size_gb"` // 磁盘大小, 单位GB
+}
+
+// NodeTemplate 新节点的实例模板, 名称和主机名取自节点
+type NodeTemplate struct {
+	Zone              string            `yaml:"zone"`               // 可用区, 配置 placement 时由分布策略选择
+	InstanceType      string            `yaml:"instance_type"`      // 实例机型
+	ImageId           string            `yaml:"image_id"`           // 镜像ID
+	ImageOS           string            `yaml:"image_os"`           // 未指定镜像ID时按操作系统约束选择公共镜像, 如 ubuntu>=20.04
+	VpcId             string            `yaml:"vpc_id"`             // 私有网络ID
+	SubnetId          string            `yaml:"subnet_id"`          // 子网ID
+	SecurityGroupIds  []string          `yaml:"security_group_ids"` // 安全组ID
+	KeyPairIds        []string          `yaml:"key_pair_ids"`       // 密钥对ID
+	SystemDisk        *Disk             `yaml:"system_disk"`        // 系统盘, 为空时使用默认配置
+	DataDisks         []Disk            `yaml:"data_disks"`         // 数据盘
+	InternetBandwidth int64             `yaml:"internet_bandwidth"` // 公网出带宽上限(Mbps), 为0时不分配公网IP
+	UserData          string            `yaml:"user_data"`          // 自定义数据
+	Tags              map[string]string `yaml:"tags"`               // 实例标签
+}
+
+// Fallback 机型或可用区售罄时的备选机型配置
+type Fallback struct {
+	MinCPU      int64    `yaml:"min_cpu"`       // 最少CPU核数
+	MaxCPU      int64    `yaml:"max_cpu"`       // 最多CPU核数
+	MinMemoryGB int64    `yaml:"min_memory_gb"` // 最少内存, 单位GB
+	MaxMemoryGB int64    `yaml:"max_memory_gb"` // 最多内存, 单位GB
+	AllowGPU    bool     `yaml:"allow_gpu"`     // 是否允许GPU机型
+	Families    []string `yaml:"families"`      // 允许的机型系列, 按优先级排列
+	Zones       []string `yaml:"zones"`         // 允许的可用区, 按优先级排列
+	MaxAttempts int      `yaml:"max_attempts"`  // 最多尝试的备选机型数, 默认3个, 为负数时不切换
+}
+
+// Tags 创建云资源时添加的标签
+type Tags struct {
+	Cluster  string            `yaml:"cluster"`   // 集群名称
+	NodePool string            `yaml:"node_pool"` // 节点池
+	Owner    string            `yaml:"owner"`     // 负责人
+	Extra    map[string]string `yaml:"extra"`     // 其他标签, 如成本中心
+}
+
+// GC 孤儿资源回收配置
+type GC struct {
+	Interval    time.Duration `yaml:"interval"`     // 检查间隔, 默认10m
+	GracePeriod time.Duration `yaml:"grace_period"` // 资源持续为孤儿超过该时间才回收, 默认30m
+	JoinTimeout time.Duration `yaml:"join_timeout"` // 实例创建后等待加入集群的时间, 超过后仍未加入的实例视为孤儿, 默认30m
+	DryRun      *bool         `yaml:"dry_run"`      // 只记录不删除, 默认 true, 显式设置为 false 时才删除资源
+	AuditLog    string        `yaml:"audit_log"`    // 审计日志路径, 默认 ~/.k8s-aim/gc-audit.log
+}
+
 // Config 配置文件
 type Config struct {
-	Manufacturers string      `yaml:"manufacturers"` // 云厂商
-	Tencent       *Tencent    `yaml:"tencent"`       // 腾讯云配置
-	Kubernetes    *Kubernetes `yaml:"kubernetes"`    // Kubernetes相关配置
+	Manufacturers string        `yaml:"manufacturers"` // 云厂商
+	Tencent       *Tencent      `yaml:"tencent"`       // 腾讯云配置
+	AliYun        *AliYun       `yaml:"aliyun"`        // 阿里云配置
+	QingCloud     *QingCloud    `yaml:"qingcloud"`     // 青云配置
+	Fake          *Fake         `yaml:"fake"`          // 内存模拟云厂商配置
+	Kubernetes    *Kubernetes   `yaml:"kubernetes"`    // Kubernetes相关配置
+	KeyStore      *KeyStore     `yaml:"key_store"`     // 私钥存储配置
+	KeyRotation   *KeyRotation  `yaml:"key_rotation"`  // 节点密钥轮换配置
+	TokenStore    *TokenStore   `yaml:"token_store"`   // 幂等令牌存储配置
+	NodeTemplate  *NodeTemplate `yaml:"node_template"` // 新节点的实例模板
+	Fallback      *Fallback     `yaml:"fallback"`      // 售罄时的备选机型
+	Placement     *Placement    `yaml:"placement"`     // 新节点的可用区分布策略
+	Spot          *Spot         `yaml:"spot"`          // 竞价实例配置
+	Tags          *Tags         `yaml:"tags"`          // 资源标签
+	GC            *GC           `yaml:"gc"`            // 孤儿资源回收配置
 }
 
 // loadConfig 加载配置文件
@@ -0,0 +1,347 @@
+package aliyun
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/aliyun/ecs"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	pageSize     = 100              // 分页查询每页数量
+	waitInterval = 5 * time.Second  // 轮询实例状态间隔
+	waitTimeout  = 10 * time.Minute // 等待实例状态超时时间
+)
+
+var _ cloud.Provider = (*InstanceServer)(nil)
+
+type InstanceServer struct {
+	client   *ecs.Client         // 阿里云ECS客户端
+	vpc      *ecs.Client         // 专有网络客户端, 用于弹性公网IP
+	keyStore cloud.KeyStore      // 私钥存储
+	vpcId    string              // 创建安全组时使用的专有网络
+	tags     *cloud.ResourceTags // 资源标签, 为空时不添加标签也不按标签过滤
+}
+
+// NewInstanceServer 实例化, 私钥默认保存在 ~/.k8s-aim/keys
+func NewInstanceServer(client, vpc *ecs.Client, vpcId string) *InstanceServer {
+	return &InstanceServer{client: client, vpc: vpc, keyStore: cloud.NewFileKeyStore(""), vpcId: vpcId}
+}
+
+// SetKeyStore 设置私钥存储
+func (i *InstanceServer) SetKeyStore(store cloud.KeyStore) {
+	i.keyStore = store
+}
+
+// GetImage 获取镜像
+func (i *InstanceServer) GetImage(query *cloud.ImageQuery) (*cloud.ImageInfo, error) {
+	params := ecs.Params{"Status": ecs.ImageStatusAvailable}
+	params.Set("ImageOwnerAlias", imageOwners[query.ImageType])
+	params.Set("InstanceType", query.InstanceType)
+	var images []cloud.ImageInfo
+	err := i.pages(params, func(params ecs.Params) (int, error) {
+		var resp ecs.DescribeImagesResponse
+		if err := i.client.Do(context.Background(), "DescribeImages", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, image := range resp.Images.Image {
+			images = append(images, toImageInfo(&image))
+		}
+		return len(resp.Images.Image), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloud.SelectImage(images, query.NameRegex, query.OS)
+}
+
+// CreateImage 使用已准备好的节点制作自定义镜像, 返回镜像ID
+func (i *InstanceServer) CreateImage(instanceId, imageName, description string) (string, error) {
+	params := ecs.Params{"InstanceId": instanceId, "ImageName": imageName}
+	params.Set("Description", description)
+	var resp ecs.CreateImageResponse
+	if err := i.client.Do(context.Background(), "CreateImage", params, &resp); err != nil {
+		return "", err
+	}
+	return resp.ImageId, nil
+}
+
+// CreateInstance 创建实例, 等待实例运行后返回
+// 阿里云实例只能绑定一个密钥对, 使用 KeyPairIds 中的第一个; 机型或可用区售罄时返回的错误可按 cloud.ErrInsufficientStock 匹配
+func (i *InstanceServer) CreateInstance(spec *cloud.InstanceSpec) ([]cloud.InstanceInfo, error) {
+	tagged := *spec
+	tagged.Tags = i.withTags(spec.Tags)
+	params := runInstancesParams(&tagged)
+	if spec.ClientToken == "" {
+		// 客户端会重试超时的请求, 未指定令牌时生成一个, 由阿里云去重避免重复创建实例
+		token, err := cloud.NewClientToken()
+		if err != nil {
+			return nil, err
+		}
+		params.Set("ClientToken", token)
+	}
+	var resp ecs.RunInstancesResponse
+	if err := i.client.Do(context.Background(), "RunInstances", params, &resp); err != nil {
+		return nil, toCloudError(err)
+	}
+	ids := resp.InstanceIdSets.InstanceIdSet
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	for {
+		instances, err := i.describeInstances(ctx, ecs.Params{}.SetJSON("InstanceIds", ids))
+		if err != nil {
+			return nil, err
+		}
+		ready := len(instances) == len(ids)
+		for _, instance := range instances {
+			if instance.State != cloud.InstanceStateRunning {
+				ready = false
+			}
+		}
+		if ready {
+			return instances, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait instances %v to be running, %s", ids, ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// DescribeInstances 查询实例, 未指定实例ID时只返回带有资源标签的实例
+func (i *InstanceServer) DescribeInstances(filter *cloud.InstanceFilter) ([]cloud.InstanceInfo, error) {
+	if filter == nil {
+		filter = &cloud.InstanceFilter{}
+	}
+	params := ecs.Params{}
+	params.SetJSON("InstanceIds", filter.InstanceIds)
+	params.Set("ZoneId", filter.Zone)
+	params.Set("InstanceName", filter.Name)
+	tags := filter.Tags
+	if len(filter.InstanceIds) == 0 {
+		tags = cloud.MergeTags(filter.Tags, i.selector())
+	}
+	params.SetTags(tags)
+	return i.describeInstances(context.Background(), params)
+}
+
+// StartInstance 启动实例
+func (i *InstanceServer) StartInstance(instanceIds ...string) error {
+	return i.client.Do(context.Background(), "StartInstances", ecs.Params{}.SetList("InstanceId", instanceIds), nil)
+}
+
+// StopInstance 停止实例
+func (i *InstanceServer) StopInstance(instanceIds ...string) error {
+	return i.client.Do(context.Background(), "StopInstances", ecs.Params{}.SetList("InstanceId", instanceIds), nil)
+}
+
+// RestartInstance 重启实例
+func (i *InstanceServer) RestartInstance(instanceIds ...string) error {
+	return i.client.Do(context.Background(), "RebootInstances", ecs.Params{}.SetList("InstanceId", instanceIds), nil)
+}
+
+// TerminateInstance 释放实例, 运行中的实例强制释放
+func (i *InstanceServer) TerminateInstance(instanceIds ...string) error {
+	params := ecs.Params{"Force": "true"}.SetList("InstanceId", instanceIds)
+	return i.client.Do(context.Background(), "DeleteInstances", params, nil)
+}
+
+// DescribeInterruption 抢占式实例的回收通知只能通过实例元数据查询, 暂不支持
+func (i *InstanceServer) DescribeInterruption(instanceId string) (*cloud.Interruption, error) {
+	return nil, cloud.ErrNotSupported
+}
+
+// describeInstances 分页查询实例
+func (i *InstanceServer) describeInstances(ctx context.Context, params ecs.Params) ([]cloud.InstanceInfo, error) {
+	var infos []cloud.InstanceInfo
+	err := i.pages(params, func(params ecs.Params) (int, error) {
+		var resp ecs.DescribeInstancesResponse
+		if err := i.client.Do(ctx, "DescribeInstances", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, instance := range resp.Instances.Instance {
+			infos = append(infos, toInstanceInfo(&instance))
+		}
+		return len(resp.Instances.Instance), nil
+	})
+	return infos, err
+}
+
+// pages 按 PageNumber、PageSize 分页查询, fetch 返回本页数量
+func (i *InstanceServer) pages(params ecs.Params, fetch func(params ecs.Params) (int, error)) error {
+	for page := 1; ; page++ {
+		p := ecs.Params{"PageNumber": strconv.Itoa(page), "PageSize": strconv.Itoa(pageSize)}
+		for k, v := range params {
+			p[k] = v
+		}
+		n, err := fetch(p)
+		if err != nil {
+			return err
+		}
+		if n < pageSize {
+			return nil
+		}
+	}
+}
+
+// runInstancesParams 创建实例参数
+func runInstancesParams(spec *cloud.InstanceSpec) ecs.Params {
+	count := spec.Count
+	if count <= 0 {
+		count = 1
+	}
+	params := ecs.Params{
+		"ZoneId":       spec.Zone,
+		"InstanceType": spec.InstanceType,
+		"ImageId":      spec.ImageId,
+		"Amount":       strconv.FormatInt(count, 10),
+	}
+	params.Set("InstanceName", spec.Name)
+	params.Set("HostName", spec.HostName)
+	params.Set("VSwitchId", spec.SubnetId)
+	params.SetList("SecurityGroupIds", spec.SecurityGroupIds)
+	if len(spec.KeyPairIds) > 0 {
+		params.Set("KeyPairName", spec.KeyPairIds[0])
+	}
+	if spec.SystemDisk != nil {
+		params.SetInt("SystemDisk.Size", spec.SystemDisk.SizeGB)
+		params.Set("SystemDisk.Category", spec.SystemDisk.Type)
+	}
+	for n, disk := range spec.DataDisks {
+		prefix := "DataDisk." + strconv.Itoa(n+1) + "."
+		params.SetInt(prefix+"Size", disk.SizeGB)
+		params.Set(prefix+"Category", disk.Type)
+		params.Set(prefix+"DeleteWithInstance", "true")
+	}
+	params.SetInt("InternetMaxBandwidthOut", spec.InternetBandwidth)
+	params.SetTags(spec.Tags)
+	if spec.UserData != "" {
+		params.Set("UserData", base64.StdEncoding.EncodeToString([]byte(spec.UserData)))
+	}
+	params.Set("ClientToken", spec.ClientToken)
+	if spec.Spot != nil {
+		params.Set("InstanceChargeType", "PostPaid")
+		if spec.Spot.MaxPrice > 0 {
+			params.Set("SpotStrategy", "SpotWithPriceLimit")
+			params.Set("SpotPriceLimit", strconv.FormatFloat(spec.Spot.MaxPrice, 'f', -1, 64))
+		} else {
+			params.Set("SpotStrategy", "SpotAsPriceGo")
+		}
+	}
+	return params
+}
+
+// 售罄类错误码, 可用区内机型库存不足或不再售卖
+var stockCodes = map[string]bool{
+	"OperationDenied.NoStock":          true,
+	"Zone.NotOnSale":                   true,
+	"InvalidResourceType.NotSupported": true,
+}
+
+// stockError 售罄错误, 既可按 cloud.ErrInsufficientStock 匹配, 也保留阿里云错误供 errors.As 使用
+type stockError struct {
+	err error
+}
+
+func (e *stockError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stockError) Unwrap() error {
+	return e.err
+}
+
+func (e *stockError) Is(target error) bool {
+	return target == cloud.ErrInsufficientStock
+}
+
+// toCloudError 阿里云售罄类错误转换为 cloud.ErrInsufficientStock, 其他错误原样返回
+func toCloudError(err error) error {
+	var e *ecs.Error
+	if errors.As(err, &e) && stockCodes[e.Code] {
+		return &stockError{err: err}
+	}
+	return err
+}
+
+// instanceStates 实例状态映射
+var instanceStates = map[string]cloud.InstanceState{
+	ecs.InstanceStatusPending:  cloud.InstanceStatePending,
+	ecs.InstanceStatusRunning:  cloud.InstanceStateRunning,
+	ecs.InstanceStatusStarting: cloud.InstanceStateStarting,
+	ecs.InstanceStatusStopping: cloud.InstanceStateStopping,
+	ecs.InstanceStatusStopped:  cloud.InstanceStateStopped,
+}
+
+// imageOwners 镜像类型映射
+var imageOwners = map[cloud.ImageType]string{
+	cloud.ImageTypePublic:  ecs.ImageOwnerSystem,
+	cloud.ImageTypePrivate: ecs.ImageOwnerSelf,
+	cloud.ImageTypeShared:  ecs.ImageOwnerOthers,
+}
+
+// toInstanceInfo 实例信息转换
+func toInstanceInfo(instance *ecs.Instance) cloud.InstanceInfo {
+	info := cloud.InstanceInfo{
+		InstanceId:       instance.InstanceId,
+		Name:             instance.InstanceName,
+		InstanceType:     instance.InstanceType,
+		Zone:             instance.ZoneId,
+		ImageId:          instance.ImageId,
+		State:            cloud.InstanceStateUnknown,
+		PrivateIps:       instance.VpcAttributes.PrivateIpAddress.IpAddress,
+		PublicIps:        instance.PublicIpAddress.IpAddress,
+		SecurityGroupIds: instance.SecurityGroupIds.SecurityGroupId,
+		Tags:             fromTags(instance.Tags.Tag),
+		Spot:             instance.SpotStrategy != "" && instance.SpotStrategy != "NoSpot",
+		CreatedTime:      parseTime(instance.CreationTime),
+	}
+	if state, ok := instanceStates[instance.Status]; ok {
+		info.State = state
+	}
+	if instance.EipAddress.IpAddress != "" {
+		info.PublicIps = append(info.PublicIps, instance.EipAddress.IpAddress)
+	}
+	if instance.KeyPairName != "" {
+		info.KeyPairIds = []string{instance.KeyPairName}
+	}
+	return info
+}
+
+// toImageInfo 镜像信息转换, 使用英文操作系统名称以便匹配版本约束
+func toImageInfo(image *ecs.Image) cloud.ImageInfo {
+	info := cloud.ImageInfo{
+		ImageId:      image.ImageId,
+		Name:         image.ImageName,
+		OsName:       strings.Join(strings.Fields(image.OSNameEn), " "),
+		Platform:     image.Platform,
+		Architecture: image.Architecture,
+		SizeGB:       image.Size,
+		CreatedTime:  parseTime(image.CreationTime),
+	}
+	if info.OsName == "" {
+		info.OsName = image.OSName
+	}
+	for imageType, owner := range imageOwners {
+		if owner == image.ImageOwnerAlias {
+			info.ImageType = imageType
+		}
+	}
+	return info
+}
+
+// parseTime 解析时间, 阿里云部分接口省略秒, 失败时返回零值
+func parseTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,181 @@
+package aliyun
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/aliyun/ecs"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ecsServer 按 Action 分发的 ECS 模拟服务, 校验签名并记录请求参数
+type ecsServer struct {
+	mu       sync.Mutex
+	handlers map[string]func(query url.Values) (int, interface{})
+	requests []url.Values
+}
+
+func (s *ecsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	w.Header().Set("Content-Type", "application/json")
+	if ecs.Sign(r.Method, query, "secret") != query.Get("Signature") {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(&ecs.Error{Code: "IncompleteSignature", Message: "signature mismatch"})
+		return
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, query)
+	handler, ok := s.handlers[query.Get("Action")]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(&ecs.Error{Code: "InvalidAction.NotFound", Message: query.Get("Action")})
+		return
+	}
+	status, resp := handler(query)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handle 设置 action 的处理函数
+func (s *ecsServer) handle(action string, handler func(query url.Values) (int, interface{})) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[action] = handler
+}
+
+// sent 已记录的 action 请求参数
+func (s *ecsServer) sent(action string) []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var requests []url.Values
+	for _, query := range s.requests {
+		if query.Get("Action") == action {
+			requests = append(requests, query)
+		}
+	}
+	return requests
+}
+
+// newTestServer 访问 ECS 模拟服务的阿里云实现, 不等待重试
+func newTestServer(t *testing.T) (*InstanceServer, *ecsServer) {
+	t.Helper()
+	s := &ecsServer{handlers: make(map[string]func(url.Values) (int, interface{}))}
+	hs := httptest.NewServer(s)
+	t.Cleanup(hs.Close)
+	client := ecs.NewClient("key", "secret", "cn-hangzhou", hs.URL)
+	client.RetryDelay = time.Millisecond
+	vpc := ecs.NewVpcClient("key", "secret", "cn-hangzhou", hs.URL)
+	vpc.RetryDelay = time.Millisecond
+	return NewInstanceServer(client, vpc, "vpc-1"), s
+}
+
+// runningInstances 创建实例后查询到运行中的实例
+func runningInstances(s *ecsServer) {
+	s.handle("RunInstances", func(query url.Values) (int, interface{}) {
+		resp := ecs.RunInstancesResponse{RequestId: "req-run"}
+		resp.InstanceIdSets.InstanceIdSet = []string{"i-1"}
+		return http.StatusOK, &resp
+	})
+	s.handle("DescribeInstances", func(query url.Values) (int, interface{}) {
+		resp := ecs.DescribeInstancesResponse{TotalCount: 1}
+		resp.Instances.Instance = []ecs.Instance{{InstanceId: "i-1", InstanceName: "k8s-worker", Status: ecs.InstanceStatusRunning, ZoneId: "cn-hangzhou-h"}}
+		return http.StatusOK, &resp
+	})
+}
+
+func newSpec() *cloud.InstanceSpec {
+	return &cloud.InstanceSpec{Name: "k8s-worker", Zone: "cn-hangzhou-h", InstanceType: "ecs.g6.large", ImageId: "ubuntu_20_04_x64"}
+}
+
+func TestCreateInstanceSendsClientToken(t *testing.T) {
+	server, s := newTestServer(t)
+	runningInstances(s)
+
+	spec := newSpec()
+	spec.ClientToken = "token-1"
+	instances, err := server.CreateInstance(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].InstanceId != "i-1" || instances[0].State != cloud.InstanceStateRunning {
+		t.Fatalf("instances = %+v, want running i-1", instances)
+	}
+	// 未指定令牌时生成新令牌
+	if _, err := server.CreateInstance(newSpec()); err != nil {
+		t.Fatal(err)
+	}
+	requests := s.sent("RunInstances")
+	if len(requests) != 2 {
+		t.Fatalf("sent %d RunInstances, want 2", len(requests))
+	}
+	if token := requests[0].Get("ClientToken"); token != "token-1" {
+		t.Fatalf("ClientToken = %q, want token-1", token)
+	}
+	if token := requests[1].Get("ClientToken"); token == "" || token == "token-1" {
+		t.Fatalf("generated ClientToken = %q, want a new token", token)
+	}
+	if zone, amount := requests[0].Get("ZoneId"), requests[0].Get("Amount"); zone != "cn-hangzhou-h" || amount != "1" {
+		t.Fatalf("ZoneId = %s, Amount = %s, want cn-hangzhou-h and 1", zone, amount)
+	}
+}
+
+func TestCreateInstanceRetriesWithClientToken(t *testing.T) {
+	server, s := newTestServer(t)
+	runningInstances(s)
+	var failed bool
+	s.handle("RunInstances", func(query url.Values) (int, interface{}) {
+		if !failed {
+			failed = true
+			return http.StatusServiceUnavailable, &ecs.Error{Code: "ServiceUnavailable"}
+		}
+		resp := ecs.RunInstancesResponse{}
+		resp.InstanceIdSets.InstanceIdSet = []string{"i-1"}
+		return http.StatusOK, &resp
+	})
+	if _, err := server.CreateInstance(newSpec()); err != nil {
+		t.Fatal(err)
+	}
+	requests := s.sent("RunInstances")
+	if len(requests) != 2 || requests[0].Get("ClientToken") != requests[1].Get("ClientToken") {
+		t.Fatalf("RunInstances requests = %v, want 2 with the same token", requests)
+	}
+}
+
+func TestCreateInstanceInsufficientStock(t *testing.T) {
+	server, s := newTestServer(t)
+	for _, code := range []string{"OperationDenied.NoStock", "Zone.NotOnSale", "InvalidResourceType.NotSupported"} {
+		s.handle("RunInstances", func(query url.Values) (int, interface{}) {
+			return http.StatusForbidden, &ecs.Error{Code: code, Message: "sold out", RequestId: "req-1"}
+		})
+		_, err := server.CreateInstance(newSpec())
+		if !errors.Is(err, cloud.ErrInsufficientStock) {
+			t.Fatalf("%s: err = %v, want ErrInsufficientStock", code, err)
+		}
+		var e *ecs.Error
+		if !errors.As(err, &e) || e.Code != code || e.RequestId != "req-1" {
+			t.Fatalf("%s: err = %v, want ecs error", code, err)
+		}
+	}
+	s.handle("RunInstances", func(query url.Values) (int, interface{}) {
+		return http.StatusBadRequest, &ecs.Error{Code: "InvalidImageId.NotFound"}
+	})
+	if _, err := server.CreateInstance(newSpec()); err == nil || errors.Is(err, cloud.ErrInsufficientStock) {
+		t.Fatalf("err = %v, want error other than ErrInsufficientStock", err)
+	}
+}
+
+func TestDescribeInterruptionNotSupported(t *testing.T) {
+	server, s := newTestServer(t)
+	if _, err := server.DescribeInterruption("i-1"); !errors.Is(err, cloud.ErrNotSupported) {
+		t.Fatalf("err = %v, want ErrNotSupported", err)
+	}
+	if n := len(s.sent("DescribeInstances")); n != 0 {
+		t.Fatalf("sent %d requests, want none", n)
+	}
+}
@@ -0,0 +1,190 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/aliyun/ecs"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"strings"
+	"time"
+)
+
+// DescribeVpcs 查询专有网络
+func (i *InstanceServer) DescribeVpcs() ([]cloud.Vpc, error) {
+	var vpcs []cloud.Vpc
+	err := i.pages(ecs.Params{}, func(params ecs.Params) (int, error) {
+		var resp ecs.DescribeVpcsResponse
+		if err := i.client.Do(context.Background(), "DescribeVpcs", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, v := range resp.Vpcs.Vpc {
+			vpcs = append(vpcs, cloud.Vpc{VpcId: v.VpcId, Name: v.VpcName, CidrBlock: v.CidrBlock, IsDefault: v.IsDefault})
+		}
+		return len(resp.Vpcs.Vpc), nil
+	})
+	return vpcs, err
+}
+
+// DescribeSubnets 查询交换机
+func (i *InstanceServer) DescribeSubnets(vpcId, zone string) ([]cloud.Subnet, error) {
+	params := ecs.Params{}
+	params.Set("VpcId", vpcId)
+	params.Set("ZoneId", zone)
+	var subnets []cloud.Subnet
+	err := i.pages(params, func(params ecs.Params) (int, error) {
+		var resp ecs.DescribeVSwitchesResponse
+		if err := i.client.Do(context.Background(), "DescribeVSwitches", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, s := range resp.VSwitches.VSwitch {
+			subnets = append(subnets, cloud.Subnet{
+				SubnetId:         s.VSwitchId,
+				VpcId:            s.VpcId,
+				Name:             s.VSwitchName,
+				CidrBlock:        s.CidrBlock,
+				Zone:             s.ZoneId,
+				AvailableIpCount: s.AvailableIpAddressCount,
+				IsDefault:        s.IsDefault,
+			})
+		}
+		return len(resp.VSwitches.VSwitch), nil
+	})
+	return subnets, err
+}
+
+// DescribeAddresses 查询弹性公网IP, addressIds 为空时只返回带有资源标签的弹性公网IP
+func (i *InstanceServer) DescribeAddresses(addressIds ...string) ([]cloud.Address, error) {
+	params := ecs.Params{}
+	params.Set("AllocationId", strings.Join(addressIds, ","))
+	if len(addressIds) == 0 {
+		params.SetTags(i.selector())
+	}
+	var addresses []cloud.Address
+	err := i.pages(params, func(params ecs.Params) (int, error) {
+		var resp ecs.DescribeEipAddressesResponse
+		if err := i.vpc.Do(context.Background(), "DescribeEipAddresses", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, a := range resp.EipAddresses.EipAddress {
+			addresses = append(addresses, cloud.Address{
+				AddressId:  a.AllocationId,
+				Ip:         a.IpAddress,
+				Status:     a.Status,
+				InstanceId: a.InstanceId,
+				Tags:       fromVpcTags(a.Tags.Tag),
+			})
+		}
+		return len(resp.EipAddresses.EipAddress), nil
+	})
+	return addresses, err
+}
+
+// AllocateAddress 申请弹性公网IP并绑定资源标签, 绑定标签失败时释放, 避免遗留无法按标签回收的弹性公网IP
+func (i *InstanceServer) AllocateAddress(bandwidth int64) (*cloud.Address, error) {
+	params := ecs.Params{}
+	params.SetInt("Bandwidth", bandwidth)
+	var resp ecs.AllocateEipAddressResponse
+	if err := i.vpc.Do(context.Background(), "AllocateEipAddress", params, &resp); err != nil {
+		return nil, err
+	}
+	if err := i.tagAddress(resp.AllocationId); err != nil {
+		if releaseErr := i.ReleaseAddress(resp.AllocationId); releaseErr != nil {
+			return nil, fmt.Errorf("tag address %s, %s, release address, %s", resp.AllocationId, err, releaseErr)
+		}
+		return nil, fmt.Errorf("tag address %s, %w", resp.AllocationId, err)
+	}
+	return &cloud.Address{AddressId: resp.AllocationId, Ip: resp.EipAddress, Status: ecs.EipStatusAvailable, Tags: i.withTags(nil)}, nil
+}
+
+// AssociateAddress 绑定弹性公网IP到实例, 等待绑定完成
+func (i *InstanceServer) AssociateAddress(addressId, instanceId string) error {
+	params := ecs.Params{"AllocationId": addressId, "InstanceId": instanceId}
+	if err := i.vpc.Do(context.Background(), "AssociateEipAddress", params, nil); err != nil {
+		return err
+	}
+	return i.waitAddress(addressId, ecs.EipStatusInUse)
+}
+
+// DisassociateAddress 解绑弹性公网IP, 阿里云需要指定绑定的实例, 先查询再解绑
+func (i *InstanceServer) DisassociateAddress(addressId string) error {
+	addresses, err := i.DescribeAddresses(addressId)
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		return fmt.Errorf("address %s not found", addressId)
+	}
+	if addresses[0].InstanceId == "" {
+		return nil
+	}
+	params := ecs.Params{"AllocationId": addressId, "InstanceId": addresses[0].InstanceId}
+	if err := i.vpc.Do(context.Background(), "UnassociateEipAddress", params, nil); err != nil {
+		return err
+	}
+	return i.waitAddress(addressId, ecs.EipStatusAvailable)
+}
+
+// ReleaseAddress 释放弹性公网IP
+func (i *InstanceServer) ReleaseAddress(addressId string) error {
+	return i.vpc.Do(context.Background(), "ReleaseEipAddress", ecs.Params{"AllocationId": addressId}, nil)
+}
+
+// DescribeNetworkInterfaces 查询实例绑定的弹性网卡
+func (i *InstanceServer) DescribeNetworkInterfaces(instanceId string) ([]cloud.NetworkInterface, error) {
+	var enis []cloud.NetworkInterface
+	err := i.pages(ecs.Params{"InstanceId": instanceId}, func(params ecs.Params) (int, error) {
+		var resp ecs.DescribeNetworkInterfacesResponse
+		if err := i.client.Do(context.Background(), "DescribeNetworkInterfaces", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, n := range resp.NetworkInterfaceSets.NetworkInterfaceSet {
+			eni := cloud.NetworkInterface{
+				NetworkInterfaceId: n.NetworkInterfaceId,
+				VpcId:              n.VpcId,
+				SubnetId:           n.VSwitchId,
+				InstanceId:         n.InstanceId,
+				MacAddress:         n.MacAddress,
+				Primary:            n.Type == ecs.NetworkInterfacePrimary,
+				State:              n.Status,
+			}
+			for _, ip := range n.PrivateIpSets.PrivateIpSet {
+				eni.PrivateIps = append(eni.PrivateIps, ip.PrivateIpAddress)
+				if ip.AssociatedPublicIp.PublicIpAddress != "" {
+					eni.PublicIps = append(eni.PublicIps, ip.AssociatedPublicIp.PublicIpAddress)
+				}
+			}
+			enis = append(enis, eni)
+		}
+		return len(resp.NetworkInterfaceSets.NetworkInterfaceSet), nil
+	})
+	return enis, err
+}
+
+// PickSubnet 在可用区内选择可用IP数不少于 need 的交换机
+func (i *InstanceServer) PickSubnet(vpcId, zone string, need int64) (*cloud.Subnet, error) {
+	subnets, err := i.DescribeSubnets(vpcId, zone)
+	if err != nil {
+		return nil, err
+	}
+	return cloud.PickSubnet(subnets, zone, need)
+}
+
+// waitAddress 轮询直到弹性公网IP达到指定状态
+func (i *InstanceServer) waitAddress(addressId, status string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	for {
+		addresses, err := i.DescribeAddresses(addressId)
+		if err != nil {
+			return err
+		}
+		if len(addresses) > 0 && addresses[0].Status == status {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait address %s to be %s, %s", addressId, status, ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
+}
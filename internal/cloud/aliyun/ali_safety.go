@@ -0,0 +1,289 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/aliyun/ecs"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"strings"
+)
+
+// CreateSecurityGroup 创建安全组并添加规则, 返回安全组ID
+func (i *InstanceServer) CreateSecurityGroup(name, description string, rules []cloud.SecurityRule) (string, error) {
+	params := ecs.Params{"SecurityGroupName": name}
+	params.Set("Description", description)
+	params.Set("VpcId", i.vpcId)
+	params.SetTags(i.withTags(nil))
+	var resp ecs.CreateSecurityGroupResponse
+	if err := i.client.Do(context.Background(), "CreateSecurityGroup", params, &resp); err != nil {
+		return "", err
+	}
+	if len(rules) > 0 {
+		if err := i.AddRules(resp.SecurityGroupId, rules); err != nil {
+			return resp.SecurityGroupId, err
+		}
+	}
+	return resp.SecurityGroupId, nil
+}
+
+// DescribeSecurityGroups 查询安全组, groupIds 为空时只返回带有资源标签的安全组
+func (i *InstanceServer) DescribeSecurityGroups(groupIds ...string) ([]cloud.SecurityGroupInfo, error) {
+	params := ecs.Params{}
+	params.SetJSON("SecurityGroupIds", groupIds)
+	if len(groupIds) == 0 {
+		params.SetTags(i.selector())
+	}
+	var groups []cloud.SecurityGroupInfo
+	err := i.pages(params, func(params ecs.Params) (int, error) {
+		var resp ecs.DescribeSecurityGroupsResponse
+		if err := i.client.Do(context.Background(), "DescribeSecurityGroups", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, g := range resp.SecurityGroups.SecurityGroup {
+			groups = append(groups, cloud.SecurityGroupInfo{
+				SecurityGroupId: g.SecurityGroupId,
+				Name:            g.SecurityGroupName,
+				Description:     g.Description,
+				CreatedTime:     parseTime(g.CreationTime),
+				Tags:            fromTags(g.Tags.Tag),
+			})
+		}
+		return len(resp.SecurityGroups.SecurityGroup), nil
+	})
+	return groups, err
+}
+
+// DeleteSecurityGroup 删除安全组
+func (i *InstanceServer) DeleteSecurityGroup(groupId string) error {
+	return i.client.Do(context.Background(), "DeleteSecurityGroup", ecs.Params{"SecurityGroupId": groupId}, nil)
+}
+
+// DescribeRules 查询安全组规则, 只返回允许访问的规则
+func (i *InstanceServer) DescribeRules(groupId string) ([]cloud.SecurityRule, error) {
+	params := ecs.Params{"SecurityGroupId": groupId, "Direction": "all"}
+	var resp ecs.DescribeSecurityGroupAttributeResponse
+	if err := i.client.Do(context.Background(), "DescribeSecurityGroupAttribute", params, &resp); err != nil {
+		return nil, err
+	}
+	var rules []cloud.SecurityRule
+	for _, p := range resp.Permissions.Permission {
+		if p.Policy != "" && !strings.EqualFold(p.Policy, "accept") {
+			continue
+		}
+		rule := cloud.SecurityRule{
+			Direction:   cloud.RuleIngress,
+			Protocol:    strings.ToUpper(p.IpProtocol),
+			CidrBlock:   p.SourceCidrIp,
+			Description: p.Description,
+		}
+		if p.Direction == "egress" {
+			rule.Direction, rule.CidrBlock = cloud.RuleEgress, p.DestCidrIp
+		}
+		if p.PortRange != "-1/-1" {
+			from, to := splitPortRange(p.PortRange)
+			rule.Port = from
+			if to != from {
+				rule.Port = from + "-" + to
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// AddRules 添加安全组规则, 阿里云每次只能添加一条
+func (i *InstanceServer) AddRules(groupId string, rules []cloud.SecurityRule) error {
+	for _, rule := range rules {
+		action := "AuthorizeSecurityGroup"
+		if rule.Direction == cloud.RuleEgress {
+			action = "AuthorizeSecurityGroupEgress"
+		}
+		if err := i.client.Do(context.Background(), action, permissionParams(groupId, rule), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveRules 删除安全组规则
+func (i *InstanceServer) RemoveRules(groupId string, rules []cloud.SecurityRule) error {
+	for _, rule := range rules {
+		action := "RevokeSecurityGroup"
+		if rule.Direction == cloud.RuleEgress {
+			action = "RevokeSecurityGroupEgress"
+		}
+		if err := i.client.Do(context.Background(), action, permissionParams(groupId, rule), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bind 实例加入安全组, 阿里云每次只能操作一个实例和一个安全组
+func (i *InstanceServer) Bind(instanceIds []string, groupIds ...string) error {
+	return i.eachMembership("JoinSecurityGroup", instanceIds, groupIds)
+}
+
+// UnBind 实例移出安全组
+func (i *InstanceServer) UnBind(instanceIds []string, groupIds ...string) error {
+	return i.eachMembership("LeaveSecurityGroup", instanceIds, groupIds)
+}
+
+// DescribeKeyPairs 查询密钥对, 阿里云使用名称作为密钥对ID, keyIds 为空时只返回带有资源标签的密钥对
+func (i *InstanceServer) DescribeKeyPairs(keyIds ...string) ([]cloud.KeyPair, error) {
+	if len(keyIds) == 0 {
+		return i.describeKeyPairs("")
+	}
+	var keyPairs []cloud.KeyPair
+	for _, name := range keyIds {
+		list, err := i.describeKeyPairs(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, keyPair := range list {
+			// KeyPairName 为模糊匹配
+			if keyPair.KeyId == name {
+				keyPairs = append(keyPairs, keyPair)
+			}
+		}
+	}
+	return keyPairs, nil
+}
+
+// CreateKeyPair 创建密钥对, 私钥以密钥对名称保存到 KeyStore
+func (i *InstanceServer) CreateKeyPair(name string) (*cloud.KeyPair, error) {
+	var resp ecs.KeyPair
+	params := ecs.Params{"KeyPairName": name}.SetTags(i.withTags(nil))
+	if err := i.client.Do(context.Background(), "CreateKeyPair", params, &resp); err != nil {
+		return nil, err
+	}
+	keyPair := &cloud.KeyPair{KeyId: resp.KeyPairName, Name: resp.KeyPairName, PrivateKey: resp.PrivateKeyBody, Tags: i.withTags(nil)}
+	if err := i.keyStore.Save(keyPair.KeyId, keyPair.PrivateKey); err != nil {
+		return keyPair, fmt.Errorf("save private key of %s, %s", keyPair.KeyId, err)
+	}
+	return keyPair, nil
+}
+
+// ImportKeyPair 导入已有公钥, privateKey 不为空时一并保存到 KeyStore
+func (i *InstanceServer) ImportKeyPair(name, publicKey, privateKey string) (*cloud.KeyPair, error) {
+	params := ecs.Params{"KeyPairName": name, "PublicKeyBody": publicKey}.SetTags(i.withTags(nil))
+	var resp ecs.KeyPair
+	if err := i.client.Do(context.Background(), "ImportKeyPair", params, &resp); err != nil {
+		return nil, err
+	}
+	keyPair := &cloud.KeyPair{KeyId: name, Name: name, PublicKey: publicKey, Tags: i.withTags(nil)}
+	if privateKey != "" {
+		if err := i.keyStore.Save(keyPair.KeyId, privateKey); err != nil {
+			return keyPair, fmt.Errorf("save private key of %s, %s", keyPair.KeyId, err)
+		}
+	}
+	return keyPair, nil
+}
+
+// BindKeyPairs 绑定密钥对, 运行中的实例需重启后生效
+func (i *InstanceServer) BindKeyPairs(instanceIds []string, keyIds ...string) error {
+	for _, name := range keyIds {
+		params := ecs.Params{"KeyPairName": name}.SetJSON("InstanceIds", instanceIds)
+		if err := i.client.Do(context.Background(), "AttachKeyPair", params, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnBindKeyPairs 解绑密钥对
+func (i *InstanceServer) UnBindKeyPairs(instanceIds []string, keyIds ...string) error {
+	for _, name := range keyIds {
+		params := ecs.Params{"KeyPairName": name}.SetJSON("InstanceIds", instanceIds)
+		if err := i.client.Do(context.Background(), "DetachKeyPair", params, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteKeyPairs 删除密钥对及 KeyStore 中保存的私钥
+func (i *InstanceServer) DeleteKeyPairs(keyIds ...string) error {
+	params := ecs.Params{}.SetJSON("KeyPairNames", keyIds)
+	if err := i.client.Do(context.Background(), "DeleteKeyPairs", params, nil); err != nil {
+		return err
+	}
+	for _, id := range keyIds {
+		if err := i.keyStore.Delete(id); err != nil {
+			return fmt.Errorf("delete private key of %s, %s", id, err)
+		}
+	}
+	return nil
+}
+
+// describeKeyPairs 分页查询密钥对, name 为空时查询带有资源标签的全部密钥对
+func (i *InstanceServer) describeKeyPairs(name string) ([]cloud.KeyPair, error) {
+	params := ecs.Params{}
+	params.Set("KeyPairName", name)
+	if name == "" {
+		params.SetTags(i.selector())
+	}
+	var keyPairs []cloud.KeyPair
+	err := i.pages(params, func(params ecs.Params) (int, error) {
+		var resp ecs.DescribeKeyPairsResponse
+		if err := i.client.Do(context.Background(), "DescribeKeyPairs", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, k := range resp.KeyPairs.KeyPair {
+			keyPairs = append(keyPairs, cloud.KeyPair{KeyId: k.KeyPairName, Name: k.KeyPairName, CreatedTime: parseTime(k.CreationTime), Tags: fromTags(k.Tags.Tag)})
+		}
+		return len(resp.KeyPairs.KeyPair), nil
+	})
+	return keyPairs, err
+}
+
+// eachMembership 逐个实例、逐个安全组调用加入或移出安全组接口
+func (i *InstanceServer) eachMembership(action string, instanceIds, groupIds []string) error {
+	for _, instanceId := range instanceIds {
+		for _, groupId := range groupIds {
+			params := ecs.Params{"InstanceId": instanceId, "SecurityGroupId": groupId}
+			if err := i.client.Do(context.Background(), action, params, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// permissionParams 安全组规则参数
+func permissionParams(groupId string, rule cloud.SecurityRule) ecs.Params {
+	params := ecs.Params{
+		"SecurityGroupId": groupId,
+		"IpProtocol":      strings.ToLower(rule.Protocol),
+		"PortRange":       portRange(rule),
+		"Policy":          "accept",
+	}
+	if rule.Direction == cloud.RuleEgress {
+		params.Set("DestCidrIp", rule.CidrBlock)
+	} else {
+		params.Set("SourceCidrIp", rule.CidrBlock)
+	}
+	params.Set("Description", rule.Description)
+	return params
+}
+
+// portRange 端口转换为阿里云格式, 如 22/22、30000/32767, ICMP 和不限端口时为 -1/-1
+func portRange(rule cloud.SecurityRule) string {
+	if rule.Port == "" || strings.EqualFold(rule.Port, "ALL") ||
+		rule.Protocol == cloud.ProtocolICMP || rule.Protocol == cloud.ProtocolALL {
+		return "-1/-1"
+	}
+	from, to := rule.Port, rule.Port
+	if n := strings.Index(rule.Port, "-"); n > 0 {
+		from, to = rule.Port[:n], rule.Port[n+1:]
+	}
+	return from + "/" + to
+}
+
+// splitPortRange 拆分 22/22 形式的端口范围
+func splitPortRange(s string) (string, string) {
+	if n := strings.Index(s, "/"); n >= 0 {
+		return s[:n], s[n+1:]
+	}
+	return s, s
+}
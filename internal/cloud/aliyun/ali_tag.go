@@ -0,0 +1,59 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/eadydb/k8s-aim/internal/cloud/aliyun/ecs"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+var _ cloud.TagAware = (*InstanceServer)(nil)
+
+// SetTags 设置资源标签, 创建的实例、弹性公网IP、安全组及密钥对均带有这些标签,
+// 未指定资源ID的查询只返回满足 Selector 的资源
+func (i *InstanceServer) SetTags(tags cloud.ResourceTags) {
+	i.tags = &tags
+}
+
+// withTags 合并资源标签, 同名时以资源标签为准, 返回新的标签
+func (i *InstanceServer) withTags(tags map[string]string) map[string]string {
+	if i.tags == nil {
+		return cloud.MergeTags(tags)
+	}
+	return cloud.MergeTags(tags, i.tags.Tags())
+}
+
+// selector 查询资源时的过滤标签, 未设置资源标签时为空
+func (i *InstanceServer) selector() map[string]string {
+	if i.tags == nil {
+		return nil
+	}
+	return i.tags.Selector()
+}
+
+// tagAddress 为弹性公网IP绑定资源标签, 阿里云申请弹性公网IP时不能指定标签
+func (i *InstanceServer) tagAddress(addressId string) error {
+	tags := i.withTags(nil)
+	if len(tags) == 0 {
+		return nil
+	}
+	params := ecs.Params{"ResourceType": "EIP", "ResourceId.1": addressId}.SetTags(tags)
+	return i.vpc.Do(context.Background(), "TagResources", params, nil)
+}
+
+// fromTags 标签转换为键值标签
+func fromTags(tags []ecs.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		m[tag.TagKey] = tag.TagValue
+	}
+	return m
+}
+
+// fromVpcTags 专有网络 API 返回的标签转换为键值标签
+func fromVpcTags(tags []ecs.VpcTag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		m[tag.Key] = tag.Value
+	}
+	return m
+}
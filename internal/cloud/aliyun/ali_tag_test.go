@@ -0,0 +1,163 @@
+package aliyun
+
+import (
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/internal/cloud/aliyun/ecs"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// tagsOf 解析 Tag.N.Key、Tag.N.Value 形式的标签参数
+func tagsOf(query url.Values) map[string]string {
+	tags := make(map[string]string)
+	for n := 1; ; n++ {
+		key := query.Get("Tag." + strconv.Itoa(n) + ".Key")
+		if key == "" {
+			return tags
+		}
+		tags[key] = query.Get("Tag." + strconv.Itoa(n) + ".Value")
+	}
+}
+
+// newTaggedServer 设置了集群资源标签的阿里云实现
+func newTaggedServer(t *testing.T) (*InstanceServer, *ecsServer) {
+	t.Helper()
+	server, s := newTestServer(t)
+	server.SetTags(cloud.NewResourceTags(&config.Tags{Cluster: "test", Owner: "ops"}))
+	return server, s
+}
+
+// ok 返回空响应的处理函数
+func ok(query url.Values) (int, interface{}) {
+	return http.StatusOK, map[string]string{"RequestId": "req-1"}
+}
+
+func TestCreateResourcesWithTags(t *testing.T) {
+	server, s := newTaggedServer(t)
+	runningInstances(s)
+	s.handle("CreateSecurityGroup", func(query url.Values) (int, interface{}) {
+		return http.StatusOK, &ecs.CreateSecurityGroupResponse{SecurityGroupId: "sg-1"}
+	})
+	s.handle("CreateKeyPair", func(query url.Values) (int, interface{}) {
+		return http.StatusOK, &ecs.KeyPair{KeyPairName: query.Get("KeyPairName"), PrivateKeyBody: "private"}
+	})
+	s.handle("AllocateEipAddress", func(query url.Values) (int, interface{}) {
+		return http.StatusOK, &ecs.AllocateEipAddressResponse{AllocationId: "eip-1", EipAddress: "1.1.1.1"}
+	})
+	s.handle("TagResources", ok)
+	server.SetKeyStore(cloud.NewFileKeyStore(t.TempDir()))
+
+	spec := newSpec()
+	spec.Tags = map[string]string{"app": "web"}
+	if _, err := server.CreateInstance(spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.CreateSecurityGroup("k8s", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.CreateKeyPair("k8s"); err != nil {
+		t.Fatal(err)
+	}
+	address, err := server.AllocateAddress(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{cloud.TagCreatedBy: cloud.CreatedBy, cloud.TagCluster: "test", cloud.TagOwner: "ops"}
+	for _, action := range []string{"CreateSecurityGroup", "CreateKeyPair", "TagResources"} {
+		if got := tagsOf(s.sent(action)[0]); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s tags = %v, want %v", action, got, want)
+		}
+	}
+	run := cloud.MergeTags(want, map[string]string{"app": "web"})
+	if got := tagsOf(s.sent("RunInstances")[0]); !reflect.DeepEqual(got, run) {
+		t.Fatalf("RunInstances tags = %v, want %v", got, run)
+	}
+	tag := s.sent("TagResources")[0]
+	if tag.Get("ResourceType") != "EIP" || tag.Get("ResourceId.1") != "eip-1" || tag.Get("Version") != ecs.VpcAPIVersion {
+		t.Fatalf("TagResources = %v, want EIP eip-1 with the VPC API", tag)
+	}
+	if !server.tags.Owns(address.Tags) {
+		t.Fatalf("address tags = %v, want resource tags", address.Tags)
+	}
+}
+
+func TestAllocateAddressReleasesUntaggedAddress(t *testing.T) {
+	server, s := newTaggedServer(t)
+	s.handle("AllocateEipAddress", func(query url.Values) (int, interface{}) {
+		return http.StatusOK, &ecs.AllocateEipAddressResponse{AllocationId: "eip-1", EipAddress: "1.1.1.1"}
+	})
+	s.handle("TagResources", func(query url.Values) (int, interface{}) {
+		return http.StatusForbidden, &ecs.Error{Code: "Forbidden.RAM"}
+	})
+	s.handle("ReleaseEipAddress", ok)
+	if _, err := server.AllocateAddress(10); err == nil {
+		t.Fatal("expected tag error")
+	}
+	if released := s.sent("ReleaseEipAddress"); len(released) != 1 || released[0].Get("AllocationId") != "eip-1" {
+		t.Fatalf("ReleaseEipAddress = %v, want eip-1", released)
+	}
+}
+
+func TestDescribeFiltersByTags(t *testing.T) {
+	server, s := newTaggedServer(t)
+	runningInstances(s)
+	s.handle("DescribeSecurityGroups", func(query url.Values) (int, interface{}) {
+		return http.StatusOK, &ecs.DescribeSecurityGroupsResponse{}
+	})
+	s.handle("DescribeKeyPairs", func(query url.Values) (int, interface{}) {
+		return http.StatusOK, &ecs.DescribeKeyPairsResponse{}
+	})
+	s.handle("DescribeEipAddresses", func(query url.Values) (int, interface{}) {
+		resp := ecs.DescribeEipAddressesResponse{TotalCount: 1}
+		address := ecs.EipAddress{AllocationId: "eip-1", IpAddress: "1.1.1.1"}
+		address.Tags.Tag = []ecs.VpcTag{{Key: cloud.TagCluster, Value: "test"}}
+		resp.EipAddresses.EipAddress = []ecs.EipAddress{address}
+		return http.StatusOK, &resp
+	})
+
+	if _, err := server.DescribeInstances(&cloud.InstanceFilter{Tags: map[string]string{"app": "web"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.DescribeSecurityGroups(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.DescribeKeyPairs(); err != nil {
+		t.Fatal(err)
+	}
+	addresses, err := server.DescribeAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addresses) != 1 || addresses[0].Tags[cloud.TagCluster] != "test" {
+		t.Fatalf("addresses = %+v, want tagged eip-1", addresses)
+	}
+
+	selector := map[string]string{cloud.TagCreatedBy: cloud.CreatedBy, cloud.TagCluster: "test"}
+	for _, action := range []string{"DescribeSecurityGroups", "DescribeKeyPairs", "DescribeEipAddresses"} {
+		if got := tagsOf(s.sent(action)[0]); !reflect.DeepEqual(got, selector) {
+			t.Fatalf("%s tags = %v, want %v", action, got, selector)
+		}
+	}
+	instances := cloud.MergeTags(selector, map[string]string{"app": "web"})
+	if got := tagsOf(s.sent("DescribeInstances")[0]); !reflect.DeepEqual(got, instances) {
+		t.Fatalf("DescribeInstances tags = %v, want %v", got, instances)
+	}
+
+	// 指定资源ID时不按资源标签过滤
+	if _, err := server.DescribeInstances(&cloud.InstanceFilter{InstanceIds: []string{"i-1"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.DescribeAddresses("eip-1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range []string{"DescribeInstances", "DescribeEipAddresses"} {
+		if got := tagsOf(s.sent(action)[1]); len(got) != 0 {
+			t.Fatalf("%s by id tags = %v, want none", action, got)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package ecs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	APIVersion      = "2014-05-26"
+	VpcAPIVersion   = "2016-04-28" // 专有网络 API 版本, 弹性公网IP的标签只能通过专有网络 API 设置和查询
+	signatureMethod = "HMAC-SHA1"
+	timestampFormat = "2006-01-02T15:04:05Z"
+)
+
+// 可重试的错误码
+var retryableCodes = map[string]bool{
+	"ServiceUnavailable":                   true,
+	"InternalError":                        true,
+	"LastTokenProcessing":                  true,
+	"OperationConflict":                    true,
+	"IncorrectInstanceStatus.Initializing": true,
+}
+
+// 流控错误码, 服务端未处理请求
+var throttlingCodes = map[string]bool{
+	"Throttling":      true,
+	"Throttling.User": true,
+}
+
+// Error 阿里云 API 错误
+type Error struct {
+	HttpStatus int    // HTTP 状态码
+	Code       string `json:"Code"`      // 错误码
+	Message    string `json:"Message"`   // 错误信息
+	RequestId  string `json:"RequestId"` // 请求ID
+
+	unsent bool // 请求未发送到服务端, 如建立连接失败
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("[AliYunSDKError] Code=%s, Message=%s, RequestId=%s", e.Code, e.Message, e.RequestId)
+}
+
+// Client ECS 客户端, 使用 RPC 风格签名, 修改 Endpoint、Version 后可访问同样签名的专有网络等 API
+type Client struct {
+	AccessKeyId     string        // AccessKey ID
+	AccessKeySecret string        // AccessKey Secret
+	SecurityToken   string        // STS 临时凭证 token
+	RegionId        string        // 地域
+	Endpoint        string        // 服务地址, 如 https://ecs.cn-hangzhou.aliyuncs.com
+	Version         string        // API 版本
+	MaxAttempts     int           // 最大尝试次数
+	RetryDelay      time.Duration // 重试间隔基数
+	httpClient      *http.Client
+}
+
+// NewClient 实例化, endpoint 为空时使用地域默认地址
+func NewClient(accessKeyId, accessKeySecret, regionId, endpoint string) *Client {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://ecs.%s.aliyuncs.com", regionId)
+	}
+	return &Client{
+		AccessKeyId:     accessKeyId,
+		AccessKeySecret: accessKeySecret,
+		RegionId:        regionId,
+		Endpoint:        strings.TrimSuffix(endpoint, "/"),
+		Version:         APIVersion,
+		MaxAttempts:     3,
+		RetryDelay:      500 * time.Millisecond,
+		httpClient:      &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// NewVpcClient 实例化专有网络 API 客户端, endpoint 为空时使用地域默认地址
+func NewVpcClient(accessKeyId, accessKeySecret, regionId, endpoint string) *Client {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://vpc.%s.aliyuncs.com", regionId)
+	}
+	c := NewClient(accessKeyId, accessKeySecret, regionId, endpoint)
+	c.Version = VpcAPIVersion
+	return c
+}
+
+// Do 调用 action, 结果解析到 resp
+// 只读的 Describe* 接口及带有 ClientToken 的请求由阿里云去重, 在网络错误、5xx 及可重试的错误码时重试;
+// 其他接口只在确定服务端未处理请求时重试, 避免重复创建实例等资源
+func (c *Client) Do(ctx context.Context, action string, params Params, resp interface{}) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.do(ctx, action, params, resp)
+		if err == nil || attempt >= c.MaxAttempts || !retryable(action, params, err) {
+			return err
+		}
+		delay := c.RetryDelay << uint(attempt-1)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// do 发送一次请求
+func (c *Client) do(ctx context.Context, action string, params Params, resp interface{}) error {
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	query.Set("Action", action)
+	query.Set("Format", "JSON")
+	query.Set("Version", c.Version)
+	query.Set("AccessKeyId", c.AccessKeyId)
+	query.Set("SignatureMethod", signatureMethod)
+	query.Set("SignatureVersion", "1.0")
+	query.Set("SignatureNonce", strconv.FormatInt(rand.Int63(), 10)+strconv.FormatInt(time.Now().UnixNano(), 10))
+	query.Set("Timestamp", time.Now().UTC().Format(timestampFormat))
+	if c.SecurityToken != "" {
+		query.Set("SecurityToken", c.SecurityToken)
+	}
+	if query.Get("RegionId") == "" {
+		query.Set("RegionId", c.RegionId)
+	}
+	query.Set("Signature", Sign(http.MethodGet, query, c.AccessKeySecret))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Endpoint+"/?"+canonicalize(query), nil)
+	if err != nil {
+		return err
+	}
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		unsent := errors.As(err, &opErr) && opErr.Op == "dial"
+		return &Error{Code: "SDK.ServerUnreachable", Message: err.Error(), unsent: unsent}
+	}
+	defer httpResp.Body.Close()
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return &Error{HttpStatus: httpResp.StatusCode, Code: "SDK.ReadBodyFailed", Message: err.Error()}
+	}
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		e := &Error{HttpStatus: httpResp.StatusCode}
+		if json.Unmarshal(body, e) != nil || e.Code == "" {
+			e.Code, e.Message = "SDK.HttpStatusError", string(body)
+		}
+		return e
+	}
+	if resp == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return &Error{HttpStatus: httpResp.StatusCode, Code: "SDK.UnmarshalFailed", Message: err.Error()}
+	}
+	return nil
+}
+
+// Sign 计算 RPC 风格签名
+func Sign(method string, query url.Values, secret string) string {
+	values := url.Values{}
+	for k, v := range query {
+		if k != "Signature" {
+			values[k] = v
+		}
+	}
+	stringToSign := method + "&" + percentEncode("/") + "&" + percentEncode(canonicalize(values))
+	mac := hmac.New(sha1.New, []byte(secret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// canonicalize 按参数名排序并编码
+func canonicalize(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, percentEncode(k)+"="+percentEncode(values.Get(k)))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// percentEncode RFC3986 编码
+func percentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+// retryable 是否可重试, 非 Describe* 接口且未指定 ClientToken 时只在确定服务端未处理请求时重试
+func retryable(action string, params Params, err error) bool {
+	e, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	if e.unsent || throttlingCodes[e.Code] {
+		return true
+	}
+	if !strings.HasPrefix(action, "Describe") && params["ClientToken"] == "" {
+		return false
+	}
+	return retryableCodes[e.Code] || e.Code == "SDK.ServerUnreachable" || e.HttpStatus >= http.StatusInternalServerError
+}
+
+// Params 请求参数
+type Params map[string]string
+
+// Set 设置参数, 值为空时忽略
+func (p Params) Set(key, value string) Params {
+	if value != "" {
+		p[key] = value
+	}
+	return p
+}
+
+// SetInt 设置整数参数, 值为0时忽略
+func (p Params) SetInt(key string, value int64) Params {
+	if value != 0 {
+		p[key] = strconv.FormatInt(value, 10)
+	}
+	return p
+}
+
+// SetList 设置 Key.N 形式的列表参数
+func (p Params) SetList(key string, values []string) Params {
+	for i, v := range values {
+		p[key+"."+strconv.Itoa(i+1)] = v
+	}
+	return p
+}
+
+// SetJSON 设置 JSON 数组形式的列表参数, 如 InstanceIds=["i-1","i-2"]
+func (p Params) SetJSON(key string, values []string) Params {
+	if len(values) > 0 {
+		data, _ := json.Marshal(values)
+		p[key] = string(data)
+	}
+	return p
+}
+
+// SetTags 设置 Tag.N.Key、Tag.N.Value 形式的标签参数, 按键排序
+func (p Params) SetTags(tags map[string]string) Params {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		n := strconv.Itoa(i + 1)
+		p["Tag."+n+".Key"] = k
+		p["Tag."+n+".Value"] = tags[k]
+	}
+	return p
+}
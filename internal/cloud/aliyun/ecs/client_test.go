@@ -0,0 +1,120 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSign 阿里云签名机制文档中的示例
+func TestSign(t *testing.T) {
+	query := url.Values{
+		"Action":           {"DescribeRegions"},
+		"AccessKeyId":      {"testid"},
+		"Format":           {"XML"},
+		"SignatureMethod":  {"HMAC-SHA1"},
+		"SignatureNonce":   {"3ee8c1b8-83d3-44af-a94f-4e0ad82fd6cf"},
+		"SignatureVersion": {"1.0"},
+		"Timestamp":        {"2016-02-23T12:46:24Z"},
+		"Version":          {"2014-05-26"},
+	}
+	if signature := Sign(http.MethodGet, query, "testsecret"); signature != "OLeaidS1JvxuMvnyHOwuJ+uX5qY=" {
+		t.Fatalf("signature = %s, want OLeaidS1JvxuMvnyHOwuJ+uX5qY=", signature)
+	}
+}
+
+// newTestClient 启动按 status、body 返回的模拟服务, 返回客户端及请求计数
+func newTestClient(t *testing.T, status int, body string) (*Client, *int32) {
+	t.Helper()
+	var calls int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	c := NewClient("key", "secret", "cn-hangzhou", s.URL)
+	c.RetryDelay = time.Millisecond
+	return c, &calls
+}
+
+func TestDoSignsRequest(t *testing.T) {
+	var query url.Values
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		if Sign(r.Method, query, "secret") != query.Get("Signature") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"Code":"IncompleteSignature","Message":"signature mismatch","RequestId":"req-1"}`))
+			return
+		}
+		w.Write([]byte(`{"RequestId":"req-2","TotalCount":0}`))
+	}))
+	defer s.Close()
+	c := NewClient("key", "secret", "cn-hangzhou", s.URL)
+	c.SecurityToken = "sts-token"
+	var resp DescribeInstancesResponse
+	if err := c.Do(context.Background(), "DescribeInstances", Params{"InstanceName": "k8s worker/中文"}, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.RequestId != "req-2" {
+		t.Fatalf("RequestId = %s, want req-2", resp.RequestId)
+	}
+	for key, want := range map[string]string{"Action": "DescribeInstances", "RegionId": "cn-hangzhou", "SecurityToken": "sts-token", "InstanceName": "k8s worker/中文"} {
+		if query.Get(key) != want {
+			t.Errorf("%s = %q, want %q", key, query.Get(key), want)
+		}
+	}
+
+	c.AccessKeySecret = "wrong"
+	err := c.Do(context.Background(), "DescribeInstances", Params{}, nil)
+	var e *Error
+	if !errors.As(err, &e) || e.Code != "IncompleteSignature" || e.HttpStatus != http.StatusBadRequest || e.RequestId != "req-1" {
+		t.Fatalf("err = %v, want IncompleteSignature with request id", err)
+	}
+}
+
+func TestDoRetries(t *testing.T) {
+	cases := []struct {
+		name   string
+		action string
+		params Params
+		status int
+		body   string
+		calls  int32
+	}{
+		{"describe on server error", "DescribeInstances", Params{}, http.StatusServiceUnavailable, `{"Code":"ServiceUnavailable"}`, 3},
+		{"run instances on server error", "RunInstances", Params{}, http.StatusInternalServerError, `{"Code":"InternalError"}`, 1},
+		{"run instances with client token", "RunInstances", Params{"ClientToken": "token-1"}, http.StatusInternalServerError, `{"Code":"InternalError"}`, 3},
+		{"run instances when throttled", "RunInstances", Params{}, http.StatusBadRequest, `{"Code":"Throttling.User"}`, 3},
+		{"client error", "DescribeInstances", Params{}, http.StatusBadRequest, `{"Code":"InvalidParameter"}`, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, calls := newTestClient(t, tc.status, tc.body)
+			if err := c.Do(context.Background(), tc.action, tc.params, nil); err == nil {
+				t.Fatal("expected error")
+			}
+			if n := atomic.LoadInt32(calls); n != tc.calls {
+				t.Fatalf("%s sent %d times, want %d", tc.action, n, tc.calls)
+			}
+		})
+	}
+}
+
+func TestRetryableRunInstancesBeforeSend(t *testing.T) {
+	s := httptest.NewServer(http.NotFoundHandler())
+	s.Close()
+	c := NewClient("key", "secret", "cn-hangzhou", s.URL)
+	err := c.do(context.Background(), "RunInstances", Params{}, nil)
+	if err == nil {
+		t.Fatal("expected connection error")
+	}
+	if !retryable("RunInstances", Params{}, err) {
+		t.Fatalf("RunInstances not retryable after dial error %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package ecs
+
+// 实例状态
+const (
+	InstanceStatusPending  = "Pending"  // 创建中
+	InstanceStatusRunning  = "Running"  // 运行中
+	InstanceStatusStarting = "Starting" // 启动中
+	InstanceStatusStopping = "Stopping" // 停止中
+	InstanceStatusStopped  = "Stopped"  // 已停止
+)
+
+// 镜像来源
+const (
+	ImageOwnerSystem = "system" // 公共镜像
+	ImageOwnerSelf   = "self"   // 自定义镜像
+	ImageOwnerOthers = "others" // 共享镜像
+)
+
+// ImageStatusAvailable 镜像可用状态
+const ImageStatusAvailable = "Available"
+
+// 弹性公网IP状态
+const (
+	EipStatusAvailable     = "Available"     // 可用
+	EipStatusInUse         = "InUse"         // 已绑定
+	EipStatusAssociating   = "Associating"   // 绑定中
+	EipStatusUnassociating = "Unassociating" // 解绑中
+)
+
+// NetworkInterfacePrimary 主网卡类型
+const NetworkInterfacePrimary = "Primary"
+
+// IpAddressSet IP 地址列表
+type IpAddressSet struct {
+	IpAddress []string `json:"IpAddress"`
+}
+
+// Tag 标签
+type Tag struct {
+	TagKey   string `json:"TagKey"`
+	TagValue string `json:"TagValue"`
+}
+
+// VpcTag 专有网络 API 返回的标签
+type VpcTag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+// Instance 实例信息
+type Instance struct {
+	InstanceId      string       `json:"InstanceId"`      // 实例ID
+	InstanceName    string       `json:"InstanceName"`    // 实例名称
+	InstanceType    string       `json:"InstanceType"`    // 实例规格
+	ZoneId          string       `json:"ZoneId"`          // 可用区
+	ImageId         string       `json:"ImageId"`         // 镜像ID
+	Status          string       `json:"Status"`          // 实例状态
+	HostName        string       `json:"HostName"`        // 主机名
+	KeyPairName     string       `json:"KeyPairName"`     // 密钥对名称
+	CreationTime    string       `json:"CreationTime"`    // 创建时间, 如 2017-12-10T04:04Z
+	SpotStrategy    string       `json:"SpotStrategy"`    // 抢占策略, NoSpot、SpotWithPriceLimit、SpotAsPriceGo
+	PublicIpAddress IpAddressSet `json:"PublicIpAddress"` // 公网IP
+	EipAddress      struct {
+		AllocationId string `json:"AllocationId"`
+		IpAddress    string `json:"IpAddress"`
+	} `json:"EipAddress"` // 弹性公网IP
+	VpcAttributes struct {
+		VpcId            string       `json:"VpcId"`
+		VSwitchId        string       `json:"VSwitchId"`
+		PrivateIpAddress IpAddressSet `json:"PrivateIpAddress"`
+	} `json:"VpcAttributes"` // 专有网络属性
+	SecurityGroupIds struct {
+		SecurityGroupId []string `json:"SecurityGroupId"`
+	} `json:"SecurityGroupIds"` // 安全组ID
+	Tags struct {
+		Tag []Tag `json:"Tag"`
+	} `json:"Tags"` // 标签
+}
+
+// DescribeInstancesResponse 查询实例响应结果
+type DescribeInstancesResponse struct {
+	RequestId  string `json:"RequestId"`
+	TotalCount int64  `json:"TotalCount"`
+	Instances  struct {
+		Instance []Instance `json:"Instance"`
+	} `json:"Instances"`
+}
+
+// RunInstancesResponse 创建实例响应结果
+type RunInstancesResponse struct {
+	RequestId      string `json:"RequestId"`
+	InstanceIdSets struct {
+		InstanceIdSet []string `json:"InstanceIdSet"`
+	} `json:"InstanceIdSets"`
+}
+
+// Image 镜像信息
+type Image struct {
+	ImageId         string `json:"ImageId"`         // 镜像ID
+	ImageName       string `json:"ImageName"`       // 镜像名称
+	ImageOwnerAlias string `json:"ImageOwnerAlias"` // 镜像来源
+	OSName          string `json:"OSName"`          // 操作系统名称
+	OSNameEn        string `json:"OSNameEn"`        // 操作系统英文名称, 如 Ubuntu  20.04 64 bit
+	Platform        string `json:"Platform"`        // 操作系统平台, 如 Ubuntu
+	Architecture    string `json:"Architecture"`    // 架构
+	Size            int64  `json:"Size"`            // 镜像大小, 单位GB
+	Status          string `json:"Status"`          // 镜像状态
+	CreationTime    string `json:"CreationTime"`    // 创建时间
+}
+
+// DescribeImagesResponse 查询镜像响应结果
+type DescribeImagesResponse struct {
+	RequestId  string `json:"RequestId"`
+	TotalCount int64  `json:"TotalCount"`
+	Images     struct {
+		Image []Image `json:"Image"`
+	} `json:"Images"`
+}
+
+// CreateImageResponse 制作镜像响应结果
+type CreateImageResponse struct {
+	RequestId string `json:"RequestId"`
+	ImageId   string `json:"ImageId"`
+}
+
+// Vpc 专有网络
+type Vpc struct {
+	VpcId     string `json:"VpcId"`
+	VpcName   string `json:"VpcName"`
+	CidrBlock string `json:"CidrBlock"`
+	IsDefault bool   `json:"IsDefault"`
+}
+
+// DescribeVpcsResponse 查询专有网络响应结果
+type DescribeVpcsResponse struct {
+	RequestId  string `json:"RequestId"`
+	TotalCount int64  `json:"TotalCount"`
+	Vpcs       struct {
+		Vpc []Vpc `json:"Vpc"`
+	} `json:"Vpcs"`
+}
+
+// VSwitch 交换机
+type VSwitch struct {
+	VSwitchId               string `json:"VSwitchId"`
+	VpcId                   string `json:"VpcId"`
+	VSwitchName             string `json:"VSwitchName"`
+	CidrBlock               string `json:"CidrBlock"`
+	ZoneId                  string `json:"ZoneId"`
+	AvailableIpAddressCount int64  `json:"AvailableIpAddressCount"`
+	IsDefault               bool   `json:"IsDefault"`
+}
+
+// DescribeVSwitchesResponse 查询交换机响应结果
+type DescribeVSwitchesResponse struct {
+	RequestId  string `json:"RequestId"`
+	TotalCount int64  `json:"TotalCount"`
+	VSwitches  struct {
+		VSwitch []VSwitch `json:"VSwitch"`
+	} `json:"VSwitches"`
+}
+
+// EipAddress 弹性公网IP
+type EipAddress struct {
+	AllocationId string `json:"AllocationId"`
+	IpAddress    string `json:"IpAddress"`
+	Status       string `json:"Status"`
+	InstanceId   string `json:"InstanceId"`
+	Tags         struct {
+		Tag []VpcTag `json:"Tag"`
+	} `json:"Tags"` // 标签, 仅专有网络 API 返回
+}
+
+// DescribeEipAddressesResponse 查询弹性公网IP响应结果
+type DescribeEipAddressesResponse struct {
+	RequestId    string `json:"RequestId"`
+	TotalCount   int64  `json:"TotalCount"`
+	EipAddresses struct {
+		EipAddress []EipAddress `json:"EipAddress"`
+	} `json:"EipAddresses"`
+}
+
+// AllocateEipAddressResponse 申请弹性公网IP响应结果
+type AllocateEipAddressResponse struct {
+	RequestId    string `json:"RequestId"`
+	AllocationId string `json:"AllocationId"`
+	EipAddress   string `json:"EipAddress"`
+}
+
+// NetworkInterfaceSet 弹性网卡
+type NetworkInterfaceSet struct {
+	NetworkInterfaceId string `json:"NetworkInterfaceId"`
+	VpcId              string `json:"VpcId"`
+	VSwitchId          string `json:"VSwitchId"`
+	InstanceId         string `json:"InstanceId"`
+	MacAddress         string `json:"MacAddress"`
+	Type               string `json:"Type"`
+	Status             string `json:"Status"`
+	PrivateIpSets      struct {
+		PrivateIpSet []struct {
+			PrivateIpAddress   string `json:"PrivateIpAddress"`
+			AssociatedPublicIp struct {
+				PublicIpAddress string `json:"PublicIpAddress"`
+			} `json:"AssociatedPublicIp"`
+		} `json:"PrivateIpSet"`
+	} `json:"PrivateIpSets"`
+}
+
+// DescribeNetworkInterfacesResponse 查询弹性网卡响应结果
+type DescribeNetworkInterfacesResponse struct {
+	RequestId            string `json:"RequestId"`
+	TotalCount           int64  `json:"TotalCount"`
+	NetworkInterfaceSets struct {
+		NetworkInterfaceSet []NetworkInterfaceSet `json:"NetworkInterfaceSet"`
+	} `json:"NetworkInterfaceSets"`
+}
+
+// SecurityGroup 安全组
+type SecurityGroup struct {
+	SecurityGroupId   string `json:"SecurityGroupId"`
+	SecurityGroupName string `json:"SecurityGroupName"`
+	Description       string `json:"Description"`
+	VpcId             string `json:"VpcId"`
+	CreationTime      string `json:"CreationTime"`
+	Tags              struct {
+		Tag []Tag `json:"Tag"`
+	} `json:"Tags"` // 标签
+}
+
+// DescribeSecurityGroupsResponse 查询安全组响应结果
+type DescribeSecurityGroupsResponse struct {
+	RequestId      string `json:"RequestId"`
+	TotalCount     int64  `json:"TotalCount"`
+	SecurityGroups struct {
+		SecurityGroup []SecurityGroup `json:"SecurityGroup"`
+	} `json:"SecurityGroups"`
+}
+
+// CreateSecurityGroupResponse 创建安全组响应结果
+type CreateSecurityGroupResponse struct {
+	RequestId       string `json:"RequestId"`
+	SecurityGroupId string `json:"SecurityGroupId"`
+}
+
+// Permission 安全组规则
+type Permission struct {
+	Direction    string `json:"Direction"`    // ingress 或 egress
+	IpProtocol   string `json:"IpProtocol"`   // tcp、udp、icmp、all
+	PortRange    string `json:"PortRange"`    // 端口范围, 如 22/22, 不限时为 -1/-1
+	SourceCidrIp string `json:"SourceCidrIp"` // 入站源网段
+	DestCidrIp   string `json:"DestCidrIp"`   // 出站目的网段
+	Policy       string `json:"Policy"`       // accept 或 drop
+	Description  string `json:"Description"`  // 描述
+}
+
+// DescribeSecurityGroupAttributeResponse 查询安全组规则响应结果
+type DescribeSecurityGroupAttributeResponse struct {
+	RequestId       string `json:"RequestId"`
+	SecurityGroupId string `json:"SecurityGroupId"`
+	Permissions     struct {
+		Permission []Permission `json:"Permission"`
+	} `json:"Permissions"`
+}
+
+// KeyPair 密钥对, 阿里云以名称标识密钥对
+type KeyPair struct {
+	KeyPairName        string `json:"KeyPairName"`
+	KeyPairFingerPrint string `json:"KeyPairFingerPrint"`
+	PrivateKeyBody     string `json:"PrivateKeyBody"` // 仅创建时返回
+	CreationTime       string `json:"CreationTime"`
+	Tags               struct {
+		Tag []Tag `json:"Tag"`
+	} `json:"Tags"` // 标签
+}
+
+// DescribeKeyPairsResponse 查询密钥对响应结果
+type DescribeKeyPairsResponse struct {
+	RequestId  string `json:"RequestId"`
+	TotalCount int64  `json:"TotalCount"`
+	KeyPairs   struct {
+		KeyPair []KeyPair `json:"KeyPair"`
+	} `json:"KeyPairs"`
+}
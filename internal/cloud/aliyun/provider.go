@@ -0,0 +1,44 @@
+package aliyun
+
+import (
+	"fmt"
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/internal/cloud/aliyun/ecs"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"os"
+)
+
+const (
+	EnvAccessKeyId     = "ALIBABA_CLOUD_ACCESS_KEY_ID"
+	EnvAccessKeySecret = "ALIBABA_CLOUD_ACCESS_KEY_SECRET"
+	EnvSecurityToken   = "ALIBABA_CLOUD_SECURITY_TOKEN"
+)
+
+func init() {
+	cloud.Register(cloud.AliYun, NewProvider)
+}
+
+// NewProvider 根据配置构建阿里云实现, 密钥为空时读取环境变量
+func NewProvider(c *config.Config) (cloud.Provider, error) {
+	if c.AliYun == nil {
+		return nil, fmt.Errorf("%w: missing aliyun section", cloud.ErrNotConfigured)
+	}
+	if c.AliYun.Region == "" {
+		return nil, fmt.Errorf("%w: aliyun.region is required", cloud.ErrNotConfigured)
+	}
+	accessKeyId, accessKeySecret, token := c.AliYun.AccessKeyId, c.AliYun.AccessKeySecret, c.AliYun.SecurityToken
+	if accessKeyId == "" || accessKeySecret == "" {
+		accessKeyId = os.Getenv(EnvAccessKeyId)
+		accessKeySecret = os.Getenv(EnvAccessKeySecret)
+		token = os.Getenv(EnvSecurityToken)
+	}
+	if accessKeyId == "" || accessKeySecret == "" {
+		return nil, fmt.Errorf("%w: aliyun access key is required", cloud.ErrNotConfigured)
+	}
+	client := ecs.NewClient(accessKeyId, accessKeySecret, c.AliYun.Region, c.AliYun.Endpoint)
+	client.SecurityToken = token
+	vpc := ecs.NewVpcClient(accessKeyId, accessKeySecret, c.AliYun.Region, c.AliYun.VpcEndpoint)
+	vpc.SecurityToken = token
+	server := NewInstanceServer(client, vpc, c.AliYun.VpcId)
+	return server, nil
+}
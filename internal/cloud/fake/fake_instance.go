@@ -0,0 +1,438 @@
+package fake
+
+import (
+	"fmt"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"net"
+	"sort"
+	"time"
+)
+
+// instance 模拟实例, 状态转换在访问时按时间推进
+type instance struct {
+	info     cloud.InstanceInfo
+	next     cloud.InstanceState // 过渡状态结束后的状态, 为空时不在过渡中
+	readyAt  time.Time           // 过渡状态结束时间
+	subnetId string              // 所在子网
+	notice   *cloud.Interruption // 竞价实例回收通知
+}
+
+// transitions 过渡状态及结束后的状态
+var transitions = map[cloud.InstanceState]cloud.InstanceState{
+	cloud.InstanceStatePending:     cloud.InstanceStateRunning,
+	cloud.InstanceStateStarting:    cloud.InstanceStateRunning,
+	cloud.InstanceStateRebooting:   cloud.InstanceStateRunning,
+	cloud.InstanceStateStopping:    cloud.InstanceStateStopped,
+	cloud.InstanceStateTerminating: cloud.InstanceStateTerminated,
+}
+
+// GetImage 获取镜像
+func (p *Provider) GetImage(query *cloud.ImageQuery) (*cloud.ImageInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("GetImage"); err != nil {
+		return nil, err
+	}
+	var images []cloud.ImageInfo
+	for _, image := range p.images {
+		if query.ImageType == "" || image.ImageType == query.ImageType {
+			images = append(images, *image)
+		}
+	}
+	return cloud.SelectImage(images, query.NameRegex, query.OS)
+}
+
+// CreateImage 使用实例制作自定义镜像, 返回镜像ID
+func (p *Provider) CreateImage(instanceId, name, description string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("CreateImage"); err != nil {
+		return "", err
+	}
+	in, err := p.instance(instanceId)
+	if err != nil {
+		return "", err
+	}
+	if in.info.State == cloud.InstanceStateTerminating || in.info.State == cloud.InstanceStateTerminated {
+		return "", fmt.Errorf("%w: instance %s is %s", ErrInvalidState, instanceId, in.info.State)
+	}
+	image := cloud.ImageInfo{ImageId: p.nextId("img"), Name: name, ImageType: cloud.ImageTypePrivate, CreatedTime: time.Now()}
+	if source, ok := p.images[in.info.ImageId]; ok {
+		image.OsName, image.Platform, image.Architecture, image.SizeGB = source.OsName, source.Platform, source.Architecture, source.SizeGB
+	}
+	p.images[image.ImageId] = &image
+	return image.ImageId, nil
+}
+
+// CreateInstance 创建实例, 校验配额及关联资源后等待实例运行
+func (p *Provider) CreateInstance(spec *cloud.InstanceSpec) ([]cloud.InstanceInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("CreateInstance"); err != nil {
+		return nil, err
+	}
+	count := int(spec.Count)
+	if count <= 0 {
+		count = 1
+	}
+	zone := spec.Zone
+	if zone == "" {
+		zone = p.opts.Zones[0]
+	}
+	if !contains(p.opts.Zones, zone) {
+		return nil, fmt.Errorf("%w: zone %s", ErrNotFound, zone)
+	}
+	if contains(p.opts.UnavailableZones, zone) {
+		return nil, fmt.Errorf("%w: zone %s is unavailable", ErrInvalidState, zone)
+	}
+	if p.soldOut(zone, spec.InstanceType) {
+		return nil, fmt.Errorf("%w: %s in zone %s", cloud.ErrInsufficientStock, spec.InstanceType, zone)
+	}
+	if _, ok := p.images[spec.ImageId]; !ok {
+		return nil, fmt.Errorf("%w: image %q", ErrNotFound, spec.ImageId)
+	}
+	subnet, err := p.pickSubnet(spec, zone, int64(count))
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range spec.SecurityGroupIds {
+		if _, ok := p.groups[id]; !ok {
+			return nil, fmt.Errorf("%w: security group %s", ErrNotFound, id)
+		}
+	}
+	for _, id := range spec.KeyPairIds {
+		if _, ok := p.keyPairs[id]; !ok {
+			return nil, fmt.Errorf("%w: key pair %s", ErrNotFound, id)
+		}
+	}
+	p.advance()
+	if quota := p.opts.Quota.Instances; quota > 0 && p.activeInstances()+count > quota {
+		return nil, fmt.Errorf("%w: instances %d, requested %d", ErrQuotaExceeded, quota, count)
+	}
+	if quota := p.opts.Quota.Addresses; quota > 0 && spec.InternetBandwidth > 0 && len(p.addresses)+count > quota {
+		return nil, fmt.Errorf("%w: addresses %d, requested %d", ErrQuotaExceeded, quota, count)
+	}
+
+	now := time.Now()
+	ids := make([]string, 0, count)
+	for n := 0; n < count; n++ {
+		in := &instance{
+			info: cloud.InstanceInfo{
+				InstanceId:       p.nextId("ins"),
+				Name:             spec.Name,
+				InstanceType:     spec.InstanceType,
+				Zone:             zone,
+				ImageId:          spec.ImageId,
+				PrivateIps:       []string{p.allocateIp(subnet)},
+				SecurityGroupIds: append([]string(nil), spec.SecurityGroupIds...),
+				KeyPairIds:       append([]string(nil), spec.KeyPairIds...),
+				Tags:             p.withTags(spec.Tags),
+				Spot:             spec.Spot != nil,
+				CreatedTime:      now,
+			},
+			subnetId: subnet.SubnetId,
+		}
+		if spec.InternetBandwidth > 0 {
+			address := p.allocateAddress()
+			address.InstanceId, address.Status = in.info.InstanceId, addressStatusInUse
+			in.info.PublicIps = []string{address.Ip}
+		}
+		for _, keyId := range spec.KeyPairIds {
+			keyPair := p.keyPairs[keyId]
+			keyPair.InstanceIds = append(keyPair.InstanceIds, in.info.InstanceId)
+		}
+		p.transition(in, cloud.InstanceStatePending)
+		p.instances[in.info.InstanceId] = in
+		ids = append(ids, in.info.InstanceId)
+	}
+
+	// 模拟等待实例运行, 等待期间释放锁
+	if p.opts.Latency > 0 {
+		p.mu.Unlock()
+		time.Sleep(p.opts.Latency)
+		p.mu.Lock()
+	}
+	p.advance()
+	infos := make([]cloud.InstanceInfo, 0, len(ids))
+	for _, id := range ids {
+		if in, ok := p.instances[id]; ok {
+			infos = append(infos, in.snapshot())
+		}
+	}
+	return infos, nil
+}
+
+// DescribeInstances 查询实例, 已销毁的实例仍可查询, 未指定实例ID时只返回带有资源标签的实例
+func (p *Provider) DescribeInstances(filter *cloud.InstanceFilter) ([]cloud.InstanceInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeInstances"); err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = &cloud.InstanceFilter{}
+	}
+	p.advance()
+	var infos []cloud.InstanceInfo
+	for _, in := range p.instances {
+		if matchInstance(&in.info, filter) && (len(filter.InstanceIds) > 0 || p.owns(in.info.Tags)) {
+			infos = append(infos, in.snapshot())
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].InstanceId < infos[j].InstanceId })
+	return infos, nil
+}
+
+// StartInstance 启动实例, 只能启动已关机的实例
+func (p *Provider) StartInstance(instanceIds ...string) error {
+	return p.changeState("StartInstance", instanceIds, cloud.InstanceStateStarting, cloud.InstanceStateStopped)
+}
+
+// StopInstance 停止实例, 只能停止运行中的实例
+func (p *Provider) StopInstance(instanceIds ...string) error {
+	return p.changeState("StopInstance", instanceIds, cloud.InstanceStateStopping, cloud.InstanceStateRunning)
+}
+
+// RestartInstance 重启实例, 只能重启运行中的实例
+func (p *Provider) RestartInstance(instanceIds ...string) error {
+	return p.changeState("RestartInstance", instanceIds, cloud.InstanceStateRebooting, cloud.InstanceStateRunning)
+}
+
+// TerminateInstance 销毁实例, 销毁后释放内网IP并解绑弹性公网IP、密钥对
+func (p *Provider) TerminateInstance(instanceIds ...string) error {
+	return p.changeState("TerminateInstance", instanceIds, cloud.InstanceStateTerminating,
+		cloud.InstanceStatePending, cloud.InstanceStateRunning, cloud.InstanceStateStarting,
+		cloud.InstanceStateStopping, cloud.InstanceStateStopped, cloud.InstanceStateRebooting)
+}
+
+// DescribeInterruption 查询竞价实例的回收通知, 通知由 Interrupt 注入
+func (p *Provider) DescribeInterruption(instanceId string) (*cloud.Interruption, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeInterruption"); err != nil {
+		return nil, err
+	}
+	in, err := p.instance(instanceId)
+	if err != nil || in.notice == nil {
+		return nil, err
+	}
+	notice := *in.notice
+	return &notice, nil
+}
+
+// RemoteInterruption 回收通知保存在内存中, 可以查询任意实例
+func (p *Provider) RemoteInterruption() bool {
+	return true
+}
+
+// Interrupt 模拟竞价实例回收通知, 实例在 at 时刻被回收
+func (p *Provider) Interrupt(instanceId string, at time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	in, err := p.instance(instanceId)
+	if err != nil {
+		return err
+	}
+	if !in.info.Spot {
+		return fmt.Errorf("%w: instance %s is not a spot instance", ErrInvalidState, instanceId)
+	}
+	in.notice = &cloud.Interruption{InstanceId: instanceId, Time: at}
+	return nil
+}
+
+// changeState 校验所有实例状态后统一进入过渡状态, 任一实例不满足时不做修改
+func (p *Provider) changeState(method string, instanceIds []string, to cloud.InstanceState, from ...cloud.InstanceState) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call(method); err != nil {
+		return err
+	}
+	p.advance()
+	list := make([]*instance, 0, len(instanceIds))
+	for _, id := range instanceIds {
+		in, err := p.instance(id)
+		if err != nil {
+			return err
+		}
+		if !containsState(from, in.info.State) {
+			return fmt.Errorf("%w: instance %s is %s", ErrInvalidState, id, in.info.State)
+		}
+		list = append(list, in)
+	}
+	for _, in := range list {
+		p.transition(in, to)
+	}
+	return nil
+}
+
+// transition 实例进入过渡状态, 无延迟时立即完成
+func (p *Provider) transition(in *instance, state cloud.InstanceState) {
+	in.info.State = state
+	in.next = transitions[state]
+	in.readyAt = time.Now().Add(p.opts.Latency)
+	if p.opts.Latency <= 0 {
+		p.finish(in)
+	}
+}
+
+// advance 推进所有到期的过渡状态, 到达回收时间的竞价实例开始销毁
+func (p *Provider) advance() {
+	now := time.Now()
+	for _, in := range p.instances {
+		if in.next != "" && !now.Before(in.readyAt) {
+			p.finish(in)
+		}
+		if in.notice != nil && !now.Before(in.notice.Time) && in.next == "" && in.info.State != cloud.InstanceStateTerminated {
+			p.transition(in, cloud.InstanceStateTerminating)
+		}
+	}
+}
+
+// finish 结束过渡状态, 实例销毁时释放关联资源
+func (p *Provider) finish(in *instance) {
+	in.info.State, in.next = in.next, ""
+	if in.info.State != cloud.InstanceStateTerminated {
+		return
+	}
+	if subnet, ok := p.subnets[in.subnetId]; ok {
+		subnet.AvailableIpCount += int64(len(in.info.PrivateIps))
+	}
+	for _, ip := range in.info.PrivateIps {
+		delete(p.usedIps, ip)
+	}
+	for _, address := range p.addresses {
+		if address.InstanceId == in.info.InstanceId {
+			address.InstanceId, address.Status = "", addressStatusAvailable
+		}
+	}
+	for _, keyPair := range p.keyPairs {
+		keyPair.InstanceIds = remove(keyPair.InstanceIds, in.info.InstanceId)
+	}
+	in.info.PublicIps = nil
+}
+
+// instance 查询实例
+func (p *Provider) instance(instanceId string) (*instance, error) {
+	in, ok := p.instances[instanceId]
+	if !ok {
+		return nil, fmt.Errorf("%w: instance %s", ErrNotFound, instanceId)
+	}
+	return in, nil
+}
+
+// activeInstances 未销毁的实例数
+func (p *Provider) activeInstances() int {
+	n := 0
+	for _, in := range p.instances {
+		if in.info.State != cloud.InstanceStateTerminated {
+			n++
+		}
+	}
+	return n
+}
+
+// pickSubnet 使用指定子网或选择可用区内可用IP最多的子网
+func (p *Provider) pickSubnet(spec *cloud.InstanceSpec, zone string, need int64) (*cloud.Subnet, error) {
+	if spec.SubnetId != "" {
+		subnet, ok := p.subnets[spec.SubnetId]
+		if !ok {
+			return nil, fmt.Errorf("%w: subnet %s", ErrNotFound, spec.SubnetId)
+		}
+		if subnet.Zone != zone {
+			return nil, fmt.Errorf("%w: subnet %s is in zone %s", ErrInvalidState, subnet.SubnetId, subnet.Zone)
+		}
+		if subnet.AvailableIpCount < need {
+			return nil, fmt.Errorf("%w: subnet %s has %d available ips", ErrQuotaExceeded, subnet.SubnetId, subnet.AvailableIpCount)
+		}
+		return subnet, nil
+	}
+	subnet, err := cloud.PickSubnet(p.subnetList(spec.VpcId, zone), zone, need)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrQuotaExceeded, err)
+	}
+	return p.subnets[subnet.SubnetId], nil
+}
+
+// snapshot 复制实例信息, 避免调用方修改内部状态
+func (in *instance) snapshot() cloud.InstanceInfo {
+	info := in.info
+	info.PrivateIps = append([]string(nil), in.info.PrivateIps...)
+	info.PublicIps = append([]string(nil), in.info.PublicIps...)
+	info.SecurityGroupIds = append([]string(nil), in.info.SecurityGroupIds...)
+	info.KeyPairIds = append([]string(nil), in.info.KeyPairIds...)
+	info.Tags = copyTags(in.info.Tags)
+	return info
+}
+
+// matchInstance 实例是否满足过滤条件
+func matchInstance(info *cloud.InstanceInfo, filter *cloud.InstanceFilter) bool {
+	if len(filter.InstanceIds) > 0 && !contains(filter.InstanceIds, info.InstanceId) {
+		return false
+	}
+	if filter.Name != "" && info.Name != filter.Name {
+		return false
+	}
+	if filter.Zone != "" && info.Zone != filter.Zone {
+		return false
+	}
+	for k, v := range filter.Tags {
+		if value, ok := info.Tags[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// allocateIp 分配子网中第一个未使用的内网IP, 跳过网络地址、网关及广播地址
+func (p *Provider) allocateIp(subnet *cloud.Subnet) string {
+	_, ipNet, err := net.ParseCIDR(subnet.CidrBlock)
+	if err != nil {
+		return ""
+	}
+	base := ipNet.IP.To4()
+	for host := 2; host < 255; host++ {
+		ip := net.IPv4(base[0], base[1], base[2], byte(host)).String()
+		if !p.usedIps[ip] {
+			p.usedIps[ip] = true
+			subnet.AvailableIpCount--
+			return ip
+		}
+	}
+	return ""
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	m := make(map[string]string, len(tags))
+	for k, v := range tags {
+		m[k] = v
+	}
+	return m
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsState(list []cloud.InstanceState, state cloud.InstanceState) bool {
+	for _, v := range list {
+		if v == state {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(list []string, s string) []string {
+	var result []string
+	for _, v := range list {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
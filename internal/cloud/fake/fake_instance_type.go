@@ -0,0 +1,42 @@
+package fake
+
+import (
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+var _ cloud.InstanceTypes = (*Provider)(nil)
+
+// instanceTypes 每个可用区均提供的机型
+var instanceTypes = []cloud.InstanceTypeOffering{
+	{InstanceType: "S1.MEDIUM4", Family: "S1", CPU: 2, MemoryGB: 4, Price: 0.4},
+	{InstanceType: "S1.LARGE8", Family: "S1", CPU: 4, MemoryGB: 8, Price: 0.8},
+	{InstanceType: "S2.LARGE8", Family: "S2", CPU: 4, MemoryGB: 8, Price: 0.7},
+	{InstanceType: "S2.XLARGE16", Family: "S2", CPU: 8, MemoryGB: 16, Price: 1.4},
+	{InstanceType: "G1.XLARGE16", Family: "G1", CPU: 8, MemoryGB: 16, GPU: 1, Price: 8},
+}
+
+// DescribeInstanceTypes 查询可用区内的机型及库存, 售罄的机型由 Options.SoldOut 指定
+func (p *Provider) DescribeInstanceTypes(zones ...string) ([]cloud.InstanceTypeOffering, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeInstanceTypes"); err != nil {
+		return nil, err
+	}
+	var offerings []cloud.InstanceTypeOffering
+	for _, zone := range p.opts.Zones {
+		if len(zones) > 0 && !contains(zones, zone) {
+			continue
+		}
+		for _, offering := range instanceTypes {
+			offering.Zone = zone
+			offering.InStock = !p.soldOut(zone, offering.InstanceType)
+			offerings = append(offerings, offering)
+		}
+	}
+	return offerings, nil
+}
+
+// soldOut 可用区内机型是否售罄
+func (p *Provider) soldOut(zone, instanceType string) bool {
+	return contains(p.opts.SoldOut, zone+"/"+instanceType) || contains(p.opts.SoldOut, "*/"+instanceType)
+}
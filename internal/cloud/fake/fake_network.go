@@ -0,0 +1,216 @@
+package fake
+
+import (
+	"fmt"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"sort"
+)
+
+// 弹性公网IP状态
+const (
+	addressStatusAvailable = "Available" // 未绑定
+	addressStatusInUse     = "InUse"     // 已绑定
+)
+
+// DescribeVpcs 查询私有网络
+func (p *Provider) DescribeVpcs() ([]cloud.Vpc, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeVpcs"); err != nil {
+		return nil, err
+	}
+	return append([]cloud.Vpc(nil), p.vpcs...), nil
+}
+
+// DescribeSubnets 查询子网
+func (p *Provider) DescribeSubnets(vpcId, zone string) ([]cloud.Subnet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeSubnets"); err != nil {
+		return nil, err
+	}
+	p.advance()
+	return p.subnetList(vpcId, zone), nil
+}
+
+// DescribeAddresses 查询弹性公网IP, addressIds 为空时只返回带有资源标签的弹性公网IP
+func (p *Provider) DescribeAddresses(addressIds ...string) ([]cloud.Address, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeAddresses"); err != nil {
+		return nil, err
+	}
+	p.advance()
+	var addresses []cloud.Address
+	for _, address := range p.addresses {
+		if len(addressIds) == 0 && p.owns(address.Tags) || contains(addressIds, address.AddressId) {
+			a := *address
+			a.Tags = copyTags(address.Tags)
+			addresses = append(addresses, a)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].AddressId < addresses[j].AddressId })
+	return addresses, nil
+}
+
+// AllocateAddress 申请弹性公网IP
+func (p *Provider) AllocateAddress(bandwidth int64) (*cloud.Address, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("AllocateAddress"); err != nil {
+		return nil, err
+	}
+	if quota := p.opts.Quota.Addresses; quota > 0 && len(p.addresses) >= quota {
+		return nil, fmt.Errorf("%w: addresses %d", ErrQuotaExceeded, quota)
+	}
+	address := *p.allocateAddress()
+	address.Tags = copyTags(address.Tags)
+	return &address, nil
+}
+
+// AssociateAddress 绑定弹性公网IP到实例
+func (p *Provider) AssociateAddress(addressId, instanceId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("AssociateAddress"); err != nil {
+		return err
+	}
+	p.advance()
+	address, err := p.address(addressId)
+	if err != nil {
+		return err
+	}
+	in, err := p.instance(instanceId)
+	if err != nil {
+		return err
+	}
+	if address.InstanceId != "" {
+		return fmt.Errorf("%w: address %s is bound to %s", ErrInvalidState, addressId, address.InstanceId)
+	}
+	if in.info.State == cloud.InstanceStateTerminating || in.info.State == cloud.InstanceStateTerminated {
+		return fmt.Errorf("%w: instance %s is %s", ErrInvalidState, instanceId, in.info.State)
+	}
+	address.InstanceId, address.Status = instanceId, addressStatusInUse
+	in.info.PublicIps = append(in.info.PublicIps, address.Ip)
+	return nil
+}
+
+// DisassociateAddress 解绑弹性公网IP, 未绑定时不报错
+func (p *Provider) DisassociateAddress(addressId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DisassociateAddress"); err != nil {
+		return err
+	}
+	address, err := p.address(addressId)
+	if err != nil {
+		return err
+	}
+	if in, ok := p.instances[address.InstanceId]; ok {
+		in.info.PublicIps = remove(in.info.PublicIps, address.Ip)
+	}
+	address.InstanceId, address.Status = "", addressStatusAvailable
+	return nil
+}
+
+// ReleaseAddress 释放弹性公网IP, 只能释放未绑定的弹性公网IP
+func (p *Provider) ReleaseAddress(addressId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("ReleaseAddress"); err != nil {
+		return err
+	}
+	p.advance()
+	address, err := p.address(addressId)
+	if err != nil {
+		return err
+	}
+	if address.InstanceId != "" {
+		return fmt.Errorf("%w: address %s is bound to %s", ErrInvalidState, addressId, address.InstanceId)
+	}
+	delete(p.addresses, addressId)
+	return nil
+}
+
+// DescribeNetworkInterfaces 查询实例绑定的弹性网卡, 每个实例只有一个主网卡
+func (p *Provider) DescribeNetworkInterfaces(instanceId string) ([]cloud.NetworkInterface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeNetworkInterfaces"); err != nil {
+		return nil, err
+	}
+	p.advance()
+	in, err := p.instance(instanceId)
+	if err != nil {
+		return nil, err
+	}
+	if in.info.State == cloud.InstanceStateTerminated {
+		return nil, nil
+	}
+	eni := cloud.NetworkInterface{
+		NetworkInterfaceId: "eni-" + in.info.InstanceId[len("ins-"):],
+		SubnetId:           in.subnetId,
+		InstanceId:         instanceId,
+		MacAddress:         macAddress(in.info.InstanceId),
+		Primary:            true,
+		State:              "Available",
+		PrivateIps:         append([]string(nil), in.info.PrivateIps...),
+		PublicIps:          append([]string(nil), in.info.PublicIps...),
+	}
+	if subnet, ok := p.subnets[in.subnetId]; ok {
+		eni.VpcId = subnet.VpcId
+	}
+	return []cloud.NetworkInterface{eni}, nil
+}
+
+// PickSubnet 在可用区内选择可用IP数不少于 need 的子网
+func (p *Provider) PickSubnet(vpcId, zone string, need int64) (*cloud.Subnet, error) {
+	subnets, err := p.DescribeSubnets(vpcId, zone)
+	if err != nil {
+		return nil, err
+	}
+	return cloud.PickSubnet(subnets, zone, need)
+}
+
+// subnetList 按私有网络、可用区过滤子网, 为空时不过滤
+func (p *Provider) subnetList(vpcId, zone string) []cloud.Subnet {
+	var subnets []cloud.Subnet
+	for _, subnet := range p.subnets {
+		if (vpcId == "" || subnet.VpcId == vpcId) && (zone == "" || subnet.Zone == zone) {
+			subnets = append(subnets, *subnet)
+		}
+	}
+	sort.Slice(subnets, func(i, j int) bool { return subnets[i].SubnetId < subnets[j].SubnetId })
+	return subnets
+}
+
+// allocateAddress 分配文档保留网段 203.0.113.0/24 中的公网IP, 调用方负责检查配额
+func (p *Provider) allocateAddress() *cloud.Address {
+	id := p.nextId("eip")
+	address := &cloud.Address{
+		AddressId: id,
+		Ip:        fmt.Sprintf("203.0.113.%d", p.seq%254+1),
+		Status:    addressStatusAvailable,
+		Tags:      p.withTags(nil),
+	}
+	p.addresses[id] = address
+	return address
+}
+
+// address 查询弹性公网IP
+func (p *Provider) address(addressId string) (*cloud.Address, error) {
+	address, ok := p.addresses[addressId]
+	if !ok {
+		return nil, fmt.Errorf("%w: address %s", ErrNotFound, addressId)
+	}
+	return address, nil
+}
+
+// macAddress 根据实例ID生成固定的 MAC 地址
+func macAddress(instanceId string) string {
+	var sum uint32
+	for _, c := range instanceId {
+		sum = sum*31 + uint32(c)
+	}
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", byte(sum>>16), byte(sum>>8), byte(sum))
+}
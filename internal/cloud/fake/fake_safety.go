@@ -0,0 +1,390 @@
+package fake
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// securityGroup 模拟安全组
+type securityGroup struct {
+	info  cloud.SecurityGroupInfo
+	rules []cloud.SecurityRule
+}
+
+// CreateSecurityGroup 创建安全组并添加规则, 返回安全组ID
+func (p *Provider) CreateSecurityGroup(name, description string, rules []cloud.SecurityRule) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("CreateSecurityGroup"); err != nil {
+		return "", err
+	}
+	if quota := p.opts.Quota.SecurityGroups; quota > 0 && len(p.groups) >= quota {
+		return "", fmt.Errorf("%w: security groups %d", ErrQuotaExceeded, quota)
+	}
+	id := p.nextId("sg")
+	p.groups[id] = &securityGroup{
+		info: cloud.SecurityGroupInfo{
+			SecurityGroupId: id,
+			Name:            name,
+			Description:     description,
+			CreatedTime:     time.Now(),
+			Tags:            p.withTags(nil),
+		},
+		rules: append([]cloud.SecurityRule(nil), rules...),
+	}
+	return id, nil
+}
+
+// DescribeSecurityGroups 查询安全组, groupIds 为空时只返回带有资源标签的安全组
+func (p *Provider) DescribeSecurityGroups(groupIds ...string) ([]cloud.SecurityGroupInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeSecurityGroups"); err != nil {
+		return nil, err
+	}
+	var groups []cloud.SecurityGroupInfo
+	for _, group := range p.groups {
+		if len(groupIds) == 0 && p.owns(group.info.Tags) || contains(groupIds, group.info.SecurityGroupId) {
+			info := group.info
+			info.Tags = copyTags(group.info.Tags)
+			groups = append(groups, info)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].SecurityGroupId < groups[j].SecurityGroupId })
+	return groups, nil
+}
+
+// DeleteSecurityGroup 删除安全组, 仍有实例使用时不能删除
+func (p *Provider) DeleteSecurityGroup(groupId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DeleteSecurityGroup"); err != nil {
+		return err
+	}
+	p.advance()
+	if _, err := p.group(groupId); err != nil {
+		return err
+	}
+	for _, in := range p.instances {
+		if in.info.State != cloud.InstanceStateTerminated && contains(in.info.SecurityGroupIds, groupId) {
+			return fmt.Errorf("%w: security group %s is used by %s", ErrInvalidState, groupId, in.info.InstanceId)
+		}
+	}
+	delete(p.groups, groupId)
+	return nil
+}
+
+// DescribeRules 查询安全组规则
+func (p *Provider) DescribeRules(groupId string) ([]cloud.SecurityRule, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeRules"); err != nil {
+		return nil, err
+	}
+	group, err := p.group(groupId)
+	if err != nil {
+		return nil, err
+	}
+	return append([]cloud.SecurityRule(nil), group.rules...), nil
+}
+
+// AddRules 添加安全组规则, 已存在的规则忽略
+func (p *Provider) AddRules(groupId string, rules []cloud.SecurityRule) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("AddRules"); err != nil {
+		return err
+	}
+	group, err := p.group(groupId)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if indexRule(group.rules, rule) < 0 {
+			group.rules = append(group.rules, rule)
+		}
+	}
+	return nil
+}
+
+// RemoveRules 删除安全组规则, 按方向、协议、端口、网段匹配
+func (p *Provider) RemoveRules(groupId string, rules []cloud.SecurityRule) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("RemoveRules"); err != nil {
+		return err
+	}
+	group, err := p.group(groupId)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if n := indexRule(group.rules, rule); n >= 0 {
+			group.rules = append(group.rules[:n], group.rules[n+1:]...)
+		}
+	}
+	return nil
+}
+
+// Bind 绑定安全组
+func (p *Provider) Bind(instanceIds []string, groupIds ...string) error {
+	return p.membership("Bind", instanceIds, groupIds, func(in *instance, groupId string) {
+		if !contains(in.info.SecurityGroupIds, groupId) {
+			in.info.SecurityGroupIds = append(in.info.SecurityGroupIds, groupId)
+		}
+	})
+}
+
+// UnBind 解绑安全组
+func (p *Provider) UnBind(instanceIds []string, groupIds ...string) error {
+	return p.membership("UnBind", instanceIds, groupIds, func(in *instance, groupId string) {
+		in.info.SecurityGroupIds = remove(in.info.SecurityGroupIds, groupId)
+	})
+}
+
+// DescribeKeyPairs 查询密钥对, keyIds 为空时只返回带有资源标签的密钥对
+func (p *Provider) DescribeKeyPairs(keyIds ...string) ([]cloud.KeyPair, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeKeyPairs"); err != nil {
+		return nil, err
+	}
+	p.advance()
+	var keyPairs []cloud.KeyPair
+	for _, keyPair := range p.keyPairs {
+		if len(keyIds) == 0 && p.owns(keyPair.Tags) || contains(keyIds, keyPair.KeyId) {
+			k := *keyPair
+			k.InstanceIds = append([]string(nil), keyPair.InstanceIds...)
+			k.Tags = copyTags(keyPair.Tags)
+			keyPairs = append(keyPairs, k)
+		}
+	}
+	sort.Slice(keyPairs, func(i, j int) bool { return keyPairs[i].KeyId < keyPairs[j].KeyId })
+	return keyPairs, nil
+}
+
+// CreateKeyPair 生成 ed25519 密钥对, 私钥以密钥对ID为名称保存到 KeyStore
+func (p *Provider) CreateKeyPair(name string) (*cloud.KeyPair, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("CreateKeyPair"); err != nil {
+		return nil, err
+	}
+	if quota := p.opts.Quota.KeyPairs; quota > 0 && len(p.keyPairs) >= quota {
+		return nil, fmt.Errorf("%w: key pairs %d", ErrQuotaExceeded, quota)
+	}
+	publicKey, privateKey, err := generateKey(p.rand)
+	if err != nil {
+		return nil, err
+	}
+	keyPair := p.addKeyPair(name, publicKey)
+	keyPair.PrivateKey = privateKey
+	if err := p.keyStore.Save(keyPair.KeyId, privateKey); err != nil {
+		return keyPair, fmt.Errorf("save private key of %s, %s", keyPair.KeyId, err)
+	}
+	return keyPair, nil
+}
+
+// ImportKeyPair 导入已有公钥, privateKey 不为空时保存到 KeyStore
+func (p *Provider) ImportKeyPair(name, publicKey, privateKey string) (*cloud.KeyPair, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("ImportKeyPair"); err != nil {
+		return nil, err
+	}
+	if quota := p.opts.Quota.KeyPairs; quota > 0 && len(p.keyPairs) >= quota {
+		return nil, fmt.Errorf("%w: key pairs %d", ErrQuotaExceeded, quota)
+	}
+	if !validPublicKey(publicKey) {
+		return nil, fmt.Errorf("%w: invalid public key", ErrInvalidState)
+	}
+	keyPair := p.addKeyPair(name, publicKey)
+	if privateKey != "" {
+		if err := p.keyStore.Save(keyPair.KeyId, privateKey); err != nil {
+			return keyPair, fmt.Errorf("save private key of %s, %s", keyPair.KeyId, err)
+		}
+	}
+	return keyPair, nil
+}
+
+// BindKeyPairs 绑定密钥对
+func (p *Provider) BindKeyPairs(instanceIds []string, keyIds ...string) error {
+	return p.keyPairMembership("BindKeyPairs", instanceIds, keyIds, true)
+}
+
+// UnBindKeyPairs 解绑密钥对
+func (p *Provider) UnBindKeyPairs(instanceIds []string, keyIds ...string) error {
+	return p.keyPairMembership("UnBindKeyPairs", instanceIds, keyIds, false)
+}
+
+// DeleteKeyPairs 删除密钥对及保存的私钥, 仍绑定实例时不能删除
+func (p *Provider) DeleteKeyPairs(keyIds ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DeleteKeyPairs"); err != nil {
+		return err
+	}
+	p.advance()
+	for _, id := range keyIds {
+		keyPair, err := p.keyPair(id)
+		if err != nil {
+			return err
+		}
+		if len(keyPair.InstanceIds) > 0 {
+			return fmt.Errorf("%w: key pair %s is bound to %v", ErrInvalidState, id, keyPair.InstanceIds)
+		}
+	}
+	for _, id := range keyIds {
+		delete(p.keyPairs, id)
+		if err := p.keyStore.Delete(id); err != nil {
+			return fmt.Errorf("delete private key of %s, %s", id, err)
+		}
+	}
+	return nil
+}
+
+// membership 校验实例及安全组后修改实例的安全组
+func (p *Provider) membership(method string, instanceIds, groupIds []string, apply func(in *instance, groupId string)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call(method); err != nil {
+		return err
+	}
+	for _, id := range groupIds {
+		if _, err := p.group(id); err != nil {
+			return err
+		}
+	}
+	list := make([]*instance, 0, len(instanceIds))
+	for _, id := range instanceIds {
+		in, err := p.instance(id)
+		if err != nil {
+			return err
+		}
+		list = append(list, in)
+	}
+	for _, in := range list {
+		for _, groupId := range groupIds {
+			apply(in, groupId)
+		}
+	}
+	return nil
+}
+
+// keyPairMembership 校验实例及密钥对后绑定或解绑
+func (p *Provider) keyPairMembership(method string, instanceIds, keyIds []string, bind bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call(method); err != nil {
+		return err
+	}
+	p.advance()
+	for _, id := range keyIds {
+		if _, err := p.keyPair(id); err != nil {
+			return err
+		}
+	}
+	list := make([]*instance, 0, len(instanceIds))
+	for _, id := range instanceIds {
+		in, err := p.instance(id)
+		if err != nil {
+			return err
+		}
+		if in.info.State == cloud.InstanceStateTerminating || in.info.State == cloud.InstanceStateTerminated {
+			return fmt.Errorf("%w: instance %s is %s", ErrInvalidState, id, in.info.State)
+		}
+		list = append(list, in)
+	}
+	for _, in := range list {
+		for _, keyId := range keyIds {
+			keyPair := p.keyPairs[keyId]
+			if bind {
+				if !contains(in.info.KeyPairIds, keyId) {
+					in.info.KeyPairIds = append(in.info.KeyPairIds, keyId)
+					keyPair.InstanceIds = append(keyPair.InstanceIds, in.info.InstanceId)
+				}
+				continue
+			}
+			in.info.KeyPairIds = remove(in.info.KeyPairIds, keyId)
+			keyPair.InstanceIds = remove(keyPair.InstanceIds, in.info.InstanceId)
+		}
+	}
+	return nil
+}
+
+// addKeyPair 保存密钥对, 返回副本
+func (p *Provider) addKeyPair(name, publicKey string) *cloud.KeyPair {
+	keyPair := &cloud.KeyPair{KeyId: p.nextId("skey"), Name: name, PublicKey: publicKey, CreatedTime: time.Now(), Tags: p.withTags(nil)}
+	p.keyPairs[keyPair.KeyId] = keyPair
+	k := *keyPair
+	k.Tags = copyTags(keyPair.Tags)
+	return &k
+}
+
+// group 查询安全组
+func (p *Provider) group(groupId string) (*securityGroup, error) {
+	group, ok := p.groups[groupId]
+	if !ok {
+		return nil, fmt.Errorf("%w: security group %s", ErrNotFound, groupId)
+	}
+	return group, nil
+}
+
+// keyPair 查询密钥对
+func (p *Provider) keyPair(keyId string) (*cloud.KeyPair, error) {
+	keyPair, ok := p.keyPairs[keyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: key pair %s", ErrNotFound, keyId)
+	}
+	return keyPair, nil
+}
+
+// indexRule 查找方向、协议、端口、网段相同的规则
+func indexRule(rules []cloud.SecurityRule, rule cloud.SecurityRule) int {
+	for n, r := range rules {
+		if r.Direction == rule.Direction && r.Protocol == rule.Protocol && r.Port == rule.Port && r.CidrBlock == rule.CidrBlock {
+			return n
+		}
+	}
+	return -1
+}
+
+// generateKey 生成 OpenSSH 格式公钥及 PKCS8 PEM 格式私钥
+func generateKey(rand io.Reader) (string, string, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand)
+	if err != nil {
+		return "", "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	// OpenSSH 公钥格式: 长度前缀的算法名称及公钥
+	var blob []byte
+	for _, field := range [][]byte{[]byte("ssh-ed25519"), publicKey} {
+		blob = append(blob, make([]byte, 4)...)
+		binary.BigEndian.PutUint32(blob[len(blob)-4:], uint32(len(field)))
+		blob = append(blob, field...)
+	}
+	private := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return "ssh-ed25519 " + base64.StdEncoding.EncodeToString(blob), string(private), nil
+}
+
+// validPublicKey 是否为 OpenSSH 格式公钥, 如 ssh-rsa AAAA... comment
+func validPublicKey(publicKey string) bool {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ssh-") && !strings.HasPrefix(fields[0], "ecdsa-") {
+		return false
+	}
+	_, err := base64.StdEncoding.DecodeString(fields[1])
+	return err == nil
+}
@@ -0,0 +1,27 @@
+package fake
+
+import (
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+var _ cloud.TagAware = (*Provider)(nil)
+
+// SetTags 设置资源标签, 创建的资源均带有这些标签, 未指定资源ID的查询只返回满足 Selector 的资源
+func (p *Provider) SetTags(tags cloud.ResourceTags) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tags = &tags
+}
+
+// withTags 合并资源标签, 同名时以资源标签为准, 返回新的标签
+func (p *Provider) withTags(tags map[string]string) map[string]string {
+	if p.tags == nil {
+		return cloud.MergeTags(tags)
+	}
+	return cloud.MergeTags(tags, p.tags.Tags())
+}
+
+// owns 资源是否满足资源标签过滤条件, 未设置资源标签时均满足
+func (p *Provider) owns(tags map[string]string) bool {
+	return p.tags == nil || p.tags.Owns(tags)
+}
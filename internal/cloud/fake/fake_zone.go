@@ -0,0 +1,21 @@
+package fake
+
+import (
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+var _ cloud.Zones = (*Provider)(nil)
+
+// DescribeZones 查询可用区, 不可用的可用区由 Options.UnavailableZones 指定
+func (p *Provider) DescribeZones() ([]cloud.Zone, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DescribeZones"); err != nil {
+		return nil, err
+	}
+	zones := make([]cloud.Zone, 0, len(p.opts.Zones))
+	for _, zone := range p.opts.Zones {
+		zones = append(zones, cloud.Zone{Zone: zone, Name: zone, Available: !contains(p.opts.UnavailableZones, zone)})
+	}
+	return zones, nil
+}
@@ -0,0 +1,218 @@
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("fake: resource not found")     // 资源不存在
+	ErrInvalidState  = errors.New("fake: invalid resource state") // 当前状态不允许该操作
+	ErrQuotaExceeded = errors.New("fake: quota exceeded")         // 超出配额
+	ErrInjected      = errors.New("fake: injected failure")       // 注入的失败
+)
+
+var _ cloud.Provider = (*Provider)(nil)
+
+func init() {
+	cloud.Register(cloud.Fake, NewProvider)
+}
+
+// Quota 资源配额, 为0时不限
+type Quota struct {
+	Instances      int // 未销毁的实例数
+	Addresses      int // 弹性公网IP数
+	SecurityGroups int // 安全组数
+	KeyPairs       int // 密钥对数
+}
+
+// Options 模拟行为
+type Options struct {
+	Zones            []string           // 可用区, 默认 fake-zone-1、fake-zone-2
+	UnavailableZones []string           // 不可用的可用区, 不能在其中创建实例
+	Latency          time.Duration      // 实例状态转换耗时, 如 Pending→Running、Stopping→Stopped
+	CallLatency      time.Duration      // 每次接口调用耗时
+	Quota            Quota              // 资源配额
+	FailureRates     map[string]float64 // 按方法名称随机注入失败的概率, 如 CreateInstance: 0.1
+	SoldOut          []string           // 售罄的机型, 格式为 zone/instanceType, zone 为 * 时所有可用区均售罄
+	Seed             int64              // 随机数种子, 为0时使用当前时间
+}
+
+// Provider 内存模拟的云厂商实现, 用于单元测试、CI 及离线演示, 并发安全
+type Provider struct {
+	mu        sync.Mutex
+	opts      Options
+	rand      *rand.Rand
+	keyStore  cloud.KeyStore
+	tags      *cloud.ResourceTags         // 资源标签, 为空时不添加标签也不按标签过滤
+	seq       int64                       // 资源ID序号
+	failures  map[string][]error          // 按方法名称注入的失败, 依次返回
+	instances map[string]*instance        // 实例
+	images    map[string]*cloud.ImageInfo // 镜像
+	vpcs      []cloud.Vpc                 // 私有网络
+	subnets   map[string]*cloud.Subnet    // 子网, 网段均为 /24
+	usedIps   map[string]bool             // 已分配的内网IP
+	addresses map[string]*cloud.Address   // 弹性公网IP
+	groups    map[string]*securityGroup   // 安全组
+	keyPairs  map[string]*cloud.KeyPair   // 密钥对
+}
+
+// NewProvider 根据配置构建模拟实现, 未配置 fake 时使用默认行为
+func NewProvider(c *config.Config) (cloud.Provider, error) {
+	opts := Options{}
+	if c.Fake != nil {
+		opts = Options{
+			Zones:            c.Fake.Zones,
+			UnavailableZones: c.Fake.UnavailableZones,
+			Latency:          c.Fake.Latency,
+			CallLatency:      c.Fake.CallLatency,
+			Quota: Quota{
+				Instances:      c.Fake.Quota.Instances,
+				Addresses:      c.Fake.Quota.Addresses,
+				SecurityGroups: c.Fake.Quota.SecurityGroups,
+				KeyPairs:       c.Fake.Quota.KeyPairs,
+			},
+			FailureRates: c.Fake.FailureRates,
+			SoldOut:      c.Fake.SoldOut,
+			Seed:         c.Fake.Seed,
+		}
+	}
+	return New(opts), nil
+}
+
+// New 实例化, 预置每个可用区一个子网及若干公共镜像, 私钥默认保存在内存
+func New(opts Options) *Provider {
+	if len(opts.Zones) == 0 {
+		opts.Zones = []string{"fake-zone-1", "fake-zone-2"}
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	p := &Provider{
+		opts:      opts,
+		rand:      rand.New(rand.NewSource(opts.Seed)),
+		keyStore:  newMemoryKeyStore(),
+		failures:  make(map[string][]error),
+		instances: make(map[string]*instance),
+		images:    make(map[string]*cloud.ImageInfo),
+		subnets:   make(map[string]*cloud.Subnet),
+		usedIps:   make(map[string]bool),
+		addresses: make(map[string]*cloud.Address),
+		groups:    make(map[string]*securityGroup),
+		keyPairs:  make(map[string]*cloud.KeyPair),
+	}
+	p.seed()
+	return p
+}
+
+// SetKeyStore 设置私钥存储
+func (p *Provider) SetKeyStore(store cloud.KeyStore) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keyStore = store
+}
+
+// InjectFailure 注入失败, method 接下来的 len(errs) 次调用依次返回 errs, err 为 nil 时返回 ErrInjected
+func (p *Provider) InjectFailure(method string, errs ...error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, err := range errs {
+		if err == nil {
+			err = fmt.Errorf("%w: %s", ErrInjected, method)
+		}
+		p.failures[method] = append(p.failures[method], err)
+	}
+}
+
+// call 模拟接口调用, 调用方需持有锁, 等待调用耗时期间释放锁
+func (p *Provider) call(method string) error {
+	if p.opts.CallLatency > 0 {
+		p.mu.Unlock()
+		time.Sleep(p.opts.CallLatency)
+		p.mu.Lock()
+	}
+	if errs := p.failures[method]; len(errs) > 0 {
+		p.failures[method] = errs[1:]
+		return errs[0]
+	}
+	if rate := p.opts.FailureRates[method]; rate > 0 && p.rand.Float64() < rate {
+		return fmt.Errorf("%w: %s", ErrInjected, method)
+	}
+	return nil
+}
+
+// nextId 生成资源ID, 如 ins-00000001
+func (p *Provider) nextId(prefix string) string {
+	p.seq++
+	return fmt.Sprintf("%s-%08x", prefix, p.seq)
+}
+
+// seed 预置私有网络、子网及公共镜像
+func (p *Provider) seed() {
+	p.vpcs = []cloud.Vpc{{VpcId: "vpc-fake", Name: "default", CidrBlock: "10.0.0.0/16", IsDefault: true}}
+	for n, zone := range p.opts.Zones {
+		id := fmt.Sprintf("subnet-fake-%d", n+1)
+		p.subnets[id] = &cloud.Subnet{
+			SubnetId:         id,
+			VpcId:            "vpc-fake",
+			Name:             zone,
+			CidrBlock:        fmt.Sprintf("10.0.%d.0/24", n+1),
+			Zone:             zone,
+			AvailableIpCount: 253,
+			IsDefault:        true,
+		}
+	}
+	created := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, os := range []string{"Ubuntu Server 18.04 LTS 64bit", "Ubuntu Server 20.04 LTS 64bit", "CentOS 7.9 64bit"} {
+		id := p.nextId("img")
+		p.images[id] = &cloud.ImageInfo{
+			ImageId:      id,
+			Name:         os,
+			ImageType:    cloud.ImageTypePublic,
+			OsName:       os,
+			Platform:     "Linux",
+			Architecture: "x86_64",
+			SizeGB:       20,
+			CreatedTime:  created,
+		}
+	}
+}
+
+// memoryKeyStore 内存私钥存储
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]string
+}
+
+func newMemoryKeyStore() *memoryKeyStore {
+	return &memoryKeyStore{keys: make(map[string]string)}
+}
+
+func (s *memoryKeyStore) Save(name, privateKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[name] = privateKey
+	return nil
+}
+
+func (s *memoryKeyStore) Load(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[name]
+	if !ok {
+		return "", fmt.Errorf("%w: private key %s", ErrNotFound, name)
+	}
+	return key, nil
+}
+
+func (s *memoryKeyStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, name)
+	return nil
+}
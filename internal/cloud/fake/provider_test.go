@@ -0,0 +1,182 @@
+package fake
+
+import (
+	"errors"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"testing"
+	"time"
+)
+
+// newSpec 使用预置 ubuntu 镜像的实例参数
+func newSpec(t *testing.T, p *Provider) *cloud.InstanceSpec {
+	t.Helper()
+	image, err := p.GetImage(&cloud.ImageQuery{ImageType: cloud.ImageTypePublic, OS: "ubuntu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &cloud.InstanceSpec{Name: "k8s-worker", InstanceType: "S1.MEDIUM4", ImageId: image.ImageId}
+}
+
+// state 查询实例当前状态
+func state(t *testing.T, p *Provider, instanceId string) cloud.InstanceState {
+	t.Helper()
+	infos, err := p.DescribeInstances(&cloud.InstanceFilter{InstanceIds: []string{instanceId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("instance %s not found", instanceId)
+	}
+	return infos[0].State
+}
+
+func TestInstanceLifecycle(t *testing.T) {
+	p := New(Options{Seed: 1})
+	instances, err := p.CreateInstance(newSpec(t, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := instances[0].InstanceId
+	if instances[0].State != cloud.InstanceStateRunning || len(instances[0].PrivateIps) != 1 {
+		t.Fatalf("created instance = %+v, want running with a private ip", instances[0])
+	}
+	if err := p.StartInstance(id); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("start running instance err = %v, want ErrInvalidState", err)
+	}
+	if err := p.StopInstance(id); err != nil {
+		t.Fatal(err)
+	}
+	if s := state(t, p, id); s != cloud.InstanceStateStopped {
+		t.Fatalf("state after stop = %s, want %s", s, cloud.InstanceStateStopped)
+	}
+	if err := p.StartInstance(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.TerminateInstance(id); err != nil {
+		t.Fatal(err)
+	}
+	if s := state(t, p, id); s != cloud.InstanceStateTerminated {
+		t.Fatalf("state after terminate = %s, want %s", s, cloud.InstanceStateTerminated)
+	}
+	if err := p.TerminateInstance(id); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("terminate terminated instance err = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestTransitionLatency(t *testing.T) {
+	p := New(Options{Seed: 1, Latency: 20 * time.Millisecond})
+	instances, err := p.CreateInstance(newSpec(t, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := instances[0].InstanceId
+	if err := p.StopInstance(id); err != nil {
+		t.Fatal(err)
+	}
+	if s := state(t, p, id); s != cloud.InstanceStateStopping {
+		t.Fatalf("state right after stop = %s, want %s", s, cloud.InstanceStateStopping)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if s := state(t, p, id); s != cloud.InstanceStateStopped {
+		t.Fatalf("state after latency = %s, want %s", s, cloud.InstanceStateStopped)
+	}
+}
+
+func TestInstanceQuota(t *testing.T) {
+	p := New(Options{Seed: 1, Quota: Quota{Instances: 1}})
+	instances, err := p.CreateInstance(newSpec(t, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.CreateInstance(newSpec(t, p)); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("create over quota err = %v, want ErrQuotaExceeded", err)
+	}
+	// 已销毁的实例不占用配额
+	if err := p.TerminateInstance(instances[0].InstanceId); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.CreateInstance(newSpec(t, p)); err != nil {
+		t.Fatalf("create after terminate err = %v", err)
+	}
+}
+
+func TestInjectFailure(t *testing.T) {
+	p := New(Options{Seed: 1})
+	custom := errors.New("boom")
+	p.InjectFailure("CreateInstance", nil, custom)
+	spec := newSpec(t, p)
+	if _, err := p.CreateInstance(spec); !errors.Is(err, ErrInjected) {
+		t.Fatalf("first create err = %v, want ErrInjected", err)
+	}
+	if _, err := p.CreateInstance(spec); err != custom {
+		t.Fatalf("second create err = %v, want %v", err, custom)
+	}
+	if _, err := p.CreateInstance(spec); err != nil {
+		t.Fatalf("third create err = %v, want nil", err)
+	}
+}
+
+func TestFailureRate(t *testing.T) {
+	p := New(Options{Seed: 1, FailureRates: map[string]float64{"StopInstance": 1}})
+	instances, err := p.CreateInstance(newSpec(t, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.StopInstance(instances[0].InstanceId); !errors.Is(err, ErrInjected) {
+		t.Fatalf("stop err = %v, want ErrInjected", err)
+	}
+	if s := state(t, p, instances[0].InstanceId); s != cloud.InstanceStateRunning {
+		t.Fatalf("state after failed stop = %s, want %s", s, cloud.InstanceStateRunning)
+	}
+}
+
+func TestSoldOut(t *testing.T) {
+	p := New(Options{Seed: 1, SoldOut: []string{"fake-zone-1/S1.MEDIUM4"}})
+	spec := newSpec(t, p)
+	spec.Zone = "fake-zone-1"
+	if _, err := p.CreateInstance(spec); !errors.Is(err, cloud.ErrInsufficientStock) {
+		t.Fatalf("create sold out err = %v, want ErrInsufficientStock", err)
+	}
+	offerings, err := p.DescribeInstanceTypes("fake-zone-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offering, ok := cloud.FindInstanceType(offerings, "fake-zone-1", "S1.MEDIUM4"); !ok || offering.InStock {
+		t.Fatalf("offering = %+v, want out of stock", offering)
+	}
+	spec.Zone = "fake-zone-2"
+	if _, err := p.CreateInstance(spec); err != nil {
+		t.Fatalf("create in other zone err = %v", err)
+	}
+}
+
+func TestInterruptSpotInstance(t *testing.T) {
+	p := New(Options{Seed: 1})
+	spec := newSpec(t, p)
+	instances, err := p.CreateInstance(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Interrupt(instances[0].InstanceId, time.Now()); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("interrupt on-demand instance err = %v, want ErrInvalidState", err)
+	}
+	spec.Spot = &cloud.SpotOptions{MaxPrice: 0.2}
+	if instances, err = p.CreateInstance(spec); err != nil {
+		t.Fatal(err)
+	}
+	id := instances[0].InstanceId
+	at := time.Now()
+	if err := p.Interrupt(id, at); err != nil {
+		t.Fatal(err)
+	}
+	notice, err := p.DescribeInterruption(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notice == nil || !notice.Time.Equal(at) {
+		t.Fatalf("notice = %+v, want interruption at %s", notice, at)
+	}
+	if s := state(t, p, id); s != cloud.InstanceStateTerminated {
+		t.Fatalf("state after interruption = %s, want %s", s, cloud.InstanceStateTerminated)
+	}
+}
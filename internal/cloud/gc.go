@@ -0,0 +1,291 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/zlog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 孤儿资源类型
+const (
+	KindInstance = "instance"
+	KindAddress  = "address"
+	KindKeyPair  = "keypair"
+)
+
+// 孤儿资源回收默认值
+const (
+	defaultGCInterval    = 10 * time.Minute
+	defaultGCGracePeriod = 30 * time.Minute
+	defaultGCJoinTimeout = 30 * time.Minute
+)
+
+// 审计日志动作
+const (
+	auditDelete = "delete"
+	auditDryRun = "dry-run"
+)
+
+// GCPolicy 孤儿资源回收策略
+type GCPolicy struct {
+	Interval    time.Duration // 检查间隔, 为0时默认10m
+	GracePeriod time.Duration // 资源持续为孤儿超过该时间才回收, 为0时默认30m
+	JoinTimeout time.Duration // 实例创建后超过该时间仍未加入集群时不再视为等待加入, 为0时默认30m
+	DryRun      bool          // 只记录到审计日志, 不删除
+	AuditLog    string        // 审计日志路径, 为空时使用 ~/.k8s-aim/gc-audit.log
+}
+
+// Orphan 孤儿资源
+type Orphan struct {
+	Kind   string    // 资源类型
+	Id     string    // 资源ID
+	Name   string    // 资源名称
+	Reason string    // 判定为孤儿的原因
+	Since  time.Time // 首次发现为孤儿的时间, 由 CollectGarbage 设置
+}
+
+// auditEntry 审计日志, 每行一条 JSON
+type auditEntry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"` // delete 或 dry-run
+	Kind   string    `json:"kind"`
+	Id     string    `json:"id"`
+	Name   string    `json:"name,omitempty"`
+	Reason string    `json:"reason"`
+	Since  time.Time `json:"since"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Orphans 查询当前的孤儿资源, 只包含带有当前集群及节点池资源标签的资源:
+// 未加入集群的实例(按节点 providerID 或IP匹配)、未绑定实例的弹性公网IP、未绑定实例且不在节点模板中的密钥对
+// 幂等令牌仍保留且未超过 GC.JoinTimeout 的实例正在创建或等待加入集群, 不视为孤儿, 发现其已加入集群或超时时删除令牌
+// 未配置集群名称时无法区分其他集群创建的资源, 返回错误
+func (c *NodeServer) Orphans() ([]Orphan, error) {
+	if c.Tags.Cluster == "" {
+		return nil, fmt.Errorf("garbage collection requires tags.cluster to identify owned resources")
+	}
+	if c.ClientSet == nil {
+		return nil, fmt.Errorf("garbage collection requires kubernetes client")
+	}
+	refs, err := c.NodeRefs()
+	if err != nil {
+		return nil, err
+	}
+	var orphans []Orphan
+	instances, err := c.Provider.DescribeInstances(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, instance := range instances {
+		ips := append(append([]string(nil), instance.PrivateIps...), instance.PublicIps...)
+		pending := c.pendingJoin(instance)
+		switch {
+		case !c.Tags.Owns(instance.Tags):
+		case instance.State == cloud.InstanceStateTerminating || instance.State == cloud.InstanceStateTerminated:
+		case refs.Has(instance.InstanceId, ips...):
+			if pending {
+				c.joined(instance)
+			}
+		case pending:
+		default:
+			orphans = append(orphans, Orphan{Kind: KindInstance, Id: instance.InstanceId, Name: instance.Name, Reason: "instance is not a cluster node"})
+		}
+	}
+	addresses, err := c.Provider.DescribeAddresses()
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		if c.Tags.Owns(address.Tags) && address.InstanceId == "" {
+			orphans = append(orphans, Orphan{Kind: KindAddress, Id: address.AddressId, Name: address.Ip, Reason: "address is not associated with any instance"})
+		}
+	}
+	keyPairs, err := c.Provider.DescribeKeyPairs()
+	if err != nil {
+		return nil, err
+	}
+	keyIds := c.template().KeyPairIds
+	for _, keyPair := range keyPairs {
+		switch {
+		case !c.Tags.Owns(keyPair.Tags):
+		case len(keyPair.InstanceIds) > 0:
+		case contains(keyIds, keyPair.KeyId):
+		default:
+			orphans = append(orphans, Orphan{Kind: KindKeyPair, Id: keyPair.KeyId, Name: keyPair.Name, Reason: "key pair is not bound to any instance"})
+		}
+	}
+	return orphans, nil
+}
+
+// CollectGarbage 回收持续为孤儿超过 GC.GracePeriod 的资源, 每个资源的处理结果追加到审计日志
+// 返回已删除的孤儿资源, dry-run 时返回应删除的孤儿资源
+// 首次发现为孤儿的时间只保存在内存中, 进程重启或资源不再是孤儿时重新计时
+func (c *NodeServer) CollectGarbage() ([]Orphan, error) {
+	orphans, err := c.Orphans()
+	if err != nil {
+		return nil, err
+	}
+	c.gcMu.Lock()
+	defer c.gcMu.Unlock()
+	now := time.Now()
+	seen := make(map[string]time.Time, len(orphans))
+	var expired []Orphan
+	for _, orphan := range orphans {
+		key := orphan.Kind + "/" + orphan.Id
+		since, ok := c.orphans[key]
+		if !ok {
+			since = now
+		}
+		seen[key] = since
+		orphan.Since = since
+		if now.Sub(since) >= c.gcGracePeriod() {
+			expired = append(expired, orphan)
+		}
+	}
+	c.orphans = seen
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	// 审计日志不可写时不删除任何资源
+	audit, err := c.openAuditLog()
+	if err != nil {
+		return nil, err
+	}
+	defer audit.Close()
+	encoder := json.NewEncoder(audit)
+	var collected []Orphan
+	for _, orphan := range expired {
+		entry := auditEntry{Action: auditDryRun, Kind: orphan.Kind, Id: orphan.Id, Name: orphan.Name, Reason: orphan.Reason, Since: orphan.Since}
+		var deleteErr error
+		if !c.GC.DryRun {
+			entry.Action = auditDelete
+			if deleteErr = c.deleteOrphan(orphan); deleteErr != nil {
+				entry.Error = deleteErr.Error()
+			}
+		}
+		entry.Time = time.Now()
+		if err := encoder.Encode(&entry); err != nil {
+			return collected, fmt.Errorf("write audit log, %s", err)
+		}
+		if deleteErr != nil {
+			zlog.Warnf("delete orphaned %s %s failed, %s", orphan.Kind, orphan.Id, deleteErr)
+			continue
+		}
+		zlog.Infof("%s orphaned %s %s (%s), orphaned since %s, %s", entry.Action, orphan.Kind, orphan.Id, orphan.Name,
+			orphan.Since.Format(time.RFC3339), orphan.Reason)
+		if !c.GC.DryRun {
+			delete(c.orphans, orphan.Kind+"/"+orphan.Id)
+		}
+		collected = append(collected, orphan)
+	}
+	return collected, nil
+}
+
+// RunGC 按 GC.Interval 定期回收孤儿资源, 直到 ctx 结束
+func (c *NodeServer) RunGC(ctx context.Context) {
+	interval := c.GC.Interval
+	if interval <= 0 {
+		interval = defaultGCInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := c.CollectGarbage(); err != nil {
+			zlog.Warnf("collect orphaned cloud resources failed, %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pendingJoin 实例是否仍在创建或等待加入集群, 即实例的令牌标签与节点保存的幂等令牌一致且创建后未超过 GC.JoinTimeout
+// 超时的实例多为进程崩溃或创建超时后遗留, 删除过期的令牌使其按孤儿回收; 云厂商未返回创建时间时无法判断超时
+func (c *NodeServer) pendingJoin(instance cloud.InstanceInfo) bool {
+	token, ok := instance.Tags[cloud.ClientTokenTag]
+	if !ok || c.Tokens == nil {
+		return false
+	}
+	stored, err := c.Tokens.Load(instance.Name)
+	if err != nil {
+		// 无法确认时按等待加入处理, 宁可漏回收也不误删
+		zlog.Warnf("load client token of instance %s (%s) failed, %s", instance.InstanceId, instance.Name, err)
+		return true
+	}
+	if stored != token {
+		return false
+	}
+	if instance.CreatedTime.IsZero() || time.Since(instance.CreatedTime) < c.gcJoinTimeout() {
+		return true
+	}
+	zlog.Warnf("instance %s (%s) has not joined the cluster %s after creation, drop its client token",
+		instance.InstanceId, instance.Name, c.gcJoinTimeout())
+	if err := c.Tokens.Delete(instance.Name); err != nil {
+		zlog.Warnf("delete client token of node %s failed, %s", instance.Name, err)
+	}
+	return false
+}
+
+// joined 实例已加入集群, 删除节点的幂等令牌
+func (c *NodeServer) joined(instance cloud.InstanceInfo) {
+	if err := c.Tokens.Delete(instance.Name); err != nil {
+		zlog.Warnf("delete client token of node %s failed, %s", instance.Name, err)
+	}
+}
+
+// deleteOrphan 删除孤儿资源
+func (c *NodeServer) deleteOrphan(orphan Orphan) error {
+	switch orphan.Kind {
+	case KindInstance:
+		return c.Provider.TerminateInstance(orphan.Id)
+	case KindAddress:
+		return c.Provider.ReleaseAddress(orphan.Id)
+	case KindKeyPair:
+		return c.Provider.DeleteKeyPairs(orphan.Id)
+	}
+	return fmt.Errorf("unknown resource kind %s", orphan.Kind)
+}
+
+// openAuditLog 以追加方式打开审计日志
+func (c *NodeServer) openAuditLog() (*os.File, error) {
+	path := c.GC.AuditLog
+	if path == "" {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, ".k8s-aim", "gc-audit.log")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+}
+
+func (c *NodeServer) gcGracePeriod() time.Duration {
+	if c.GC.GracePeriod <= 0 {
+		return defaultGCGracePeriod
+	}
+	return c.GC.GracePeriod
+}
+
+func (c *NodeServer) gcJoinTimeout() time.Duration {
+	if c.GC.JoinTimeout <= 0 {
+		return defaultGCJoinTimeout
+	}
+	return c.GC.JoinTimeout
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package cloud
+
+import (
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/k8s"
+	"testing"
+	"time"
+)
+
+func orphanIds(orphans []Orphan) []string {
+	var ids []string
+	for _, orphan := range orphans {
+		ids = append(ids, orphan.Id)
+	}
+	return ids
+}
+
+func TestOrphansSkipInstancesPendingJoin(t *testing.T) {
+	server, provider, api := newTestNodeServer(t)
+	pending := createNode(t, server, provider, "worker-1")
+
+	orphans, err := server.Orphans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 0 {
+		t.Fatalf("orphans = %v, want none while worker-1 is pending join", orphanIds(orphans))
+	}
+
+	// 加入集群后删除令牌, 之后离开集群的实例视为孤儿
+	api.addNode("worker-1", pending)
+	if orphans, err = server.Orphans(); err != nil || len(orphans) != 0 {
+		t.Fatalf("orphans = %v, %v, want none after join", orphanIds(orphans), err)
+	}
+	if token, err := server.Tokens.Load("worker-1"); err != nil || token != "" {
+		t.Fatalf("token of joined node = %q, %v, want deleted", token, err)
+	}
+	api.mu.Lock()
+	api.nodes = nil
+	api.mu.Unlock()
+	if orphans, err = server.Orphans(); err != nil || len(orphans) != 1 || orphans[0].Id != pending {
+		t.Fatalf("orphans = %v, %v, want [%s]", orphanIds(orphans), err, pending)
+	}
+}
+
+func TestOrphansReportInstancesPastJoinTimeout(t *testing.T) {
+	server, provider, _ := newTestNodeServer(t)
+	server.GC.JoinTimeout = 10 * time.Millisecond
+	pending := createNode(t, server, provider, "worker-1")
+	if orphans, err := server.Orphans(); err != nil || len(orphans) != 0 {
+		t.Fatalf("orphans = %v, %v, want none before join timeout", orphanIds(orphans), err)
+	}
+
+	// 未加入集群且超时的实例视为孤儿, 并删除过期令牌
+	time.Sleep(20 * time.Millisecond)
+	orphans, err := server.Orphans()
+	if err != nil || len(orphans) != 1 || orphans[0].Id != pending {
+		t.Fatalf("orphans = %v, %v, want [%s] after join timeout", orphanIds(orphans), err, pending)
+	}
+	if token, err := server.Tokens.Load("worker-1"); err != nil || token != "" {
+		t.Fatalf("token of timed out node = %q, %v, want deleted", token, err)
+	}
+}
+
+func TestCollectGarbageDryRun(t *testing.T) {
+	server, provider, api := newTestNodeServer(t)
+	instanceId := createNode(t, server, provider, "worker-1")
+	api.addNode("worker-1", instanceId)
+	if _, err := server.Orphans(); err != nil {
+		t.Fatal(err)
+	}
+	api.mu.Lock()
+	api.nodes = nil
+	api.mu.Unlock()
+
+	server.GC.DryRun = true
+	// 首次发现只开始计时
+	if _, err := server.CollectGarbage(); err != nil {
+		t.Fatal(err)
+	}
+	collected, err := server.CollectGarbage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collected) != 1 || collected[0].Id != instanceId {
+		t.Fatalf("collected = %v, want [%s]", orphanIds(collected), instanceId)
+	}
+	instances, err := provider.DescribeInstances(&cloud.InstanceFilter{InstanceIds: []string{instanceId}})
+	if err != nil || instances[0].State != cloud.InstanceStateRunning {
+		t.Fatalf("instance after dry run = %+v, %v, want running", instances, err)
+	}
+
+	server.GC.DryRun = false
+	if collected, err = server.CollectGarbage(); err != nil || len(collected) != 1 {
+		t.Fatalf("collected = %v, %v, want [%s]", orphanIds(collected), err, instanceId)
+	}
+	instances, err = provider.DescribeInstances(&cloud.InstanceFilter{InstanceIds: []string{instanceId}})
+	if err != nil || instances[0].State != cloud.InstanceStateTerminated {
+		t.Fatalf("instance after collection = %+v, %v, want terminated", instances, err)
+	}
+}
+
+func TestNewNodeServerDefaultsToDryRun(t *testing.T) {
+	disabled := false
+	cases := []struct {
+		gc   *config.GC
+		want bool
+	}{
+		{nil, true},
+		{&config.GC{}, true},
+		{&config.GC{DryRun: &disabled}, false},
+	}
+	for _, c := range cases {
+		server, err := NewNodeServer(&config.Config{Manufacturers: string(cloud.Fake), GC: c.gc, TokenStore: &config.TokenStore{Dir: t.TempDir()}}, &k8s.KClient{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if server.GC.DryRun != c.want {
+			t.Fatalf("gc %+v: DryRun = %v, want %v", c.gc, server.GC.DryRun, c.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/k8s"
+	"github.com/eadydb/k8s-aim/pkg/zlog"
+	"strings"
+	"time"
+)
+
+// defaultKeyRotationInterval 默认密钥轮换检查间隔
+const defaultKeyRotationInterval = 24 * time.Hour
+
+// KeyRotationPolicy 节点密钥轮换策略
+type KeyRotationPolicy struct {
+	Interval  time.Duration // 检查间隔, 为0时默认24h
+	MaxAge    time.Duration // 密钥对创建超过该时间后轮换, 为0时不轮换
+	ForceStop bool          // 需关机绑定密钥的实例软关机失败后强制关机
+}
+
+// NewKeyStore 根据配置构建私钥存储, 未配置时使用本地文件
+func NewKeyStore(c *config.KeyStore, client *k8s.KClient) cloud.KeyStore {
+	if c == nil {
+		return cloud.NewFileKeyStore("")
+	}
+	if strings.EqualFold(c.Type, "secret") {
+		return k8s.NewSecretKeyStore(client, c.Prefix)
+	}
+	return cloud.NewFileKeyStore(c.Dir)
+}
+
+// RotateKeyPairs 轮换节点模板中创建超过 KeyRotation.MaxAge 的密钥对, 新密钥对绑定到原密钥对的实例并替换节点模板中的密钥对
+// 返回轮换后的新密钥对; 轮换失败时节点模板保持不变, 已创建的新密钥对未绑定实例, 由孤儿资源回收删除
+func (c *NodeServer) RotateKeyPairs() ([]cloud.KeyPair, error) {
+	if c.KeyRotation.MaxAge <= 0 {
+		return nil, nil
+	}
+	rotator, ok := c.Provider.(cloud.KeyRotator)
+	if !ok {
+		return nil, fmt.Errorf("%w: manufacturer %s cannot rotate key pairs", cloud.ErrNotSupported, c.Manufacturers)
+	}
+	keyIds := c.template().KeyPairIds
+	if len(keyIds) == 0 {
+		return nil, nil
+	}
+	keyPairs, err := c.Provider.DescribeKeyPairs(keyIds...)
+	if err != nil {
+		return nil, err
+	}
+	var rotated []cloud.KeyPair
+	for _, old := range keyPairs {
+		if old.CreatedTime.IsZero() || time.Since(old.CreatedTime) < c.KeyRotation.MaxAge {
+			continue
+		}
+		keyPair, err := rotator.RotateKeyPair(old.KeyId, keyPairName(time.Now()), old.InstanceIds, c.KeyRotation.ForceStop)
+		if err != nil {
+			return rotated, fmt.Errorf("rotate key pair %s, %w", old.KeyId, err)
+		}
+		c.replaceKeyPair(old.KeyId, keyPair.KeyId)
+		zlog.Infof("rotate key pair %s created at %s to %s, instances %v", old.KeyId, old.CreatedTime.Format(time.RFC3339), keyPair.KeyId, old.InstanceIds)
+		rotated = append(rotated, *keyPair)
+	}
+	return rotated, nil
+}
+
+// RunKeyRotation 按 KeyRotation.Interval 定期轮换密钥对, 直到 ctx 结束
+func (c *NodeServer) RunKeyRotation(ctx context.Context) {
+	interval := c.KeyRotation.Interval
+	if interval <= 0 {
+		interval = defaultKeyRotationInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := c.RotateKeyPairs(); err != nil {
+			zlog.Warnf("rotate key pairs failed, %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// template 节点实例模板, 密钥对可能被轮换替换, 需加锁读取
+func (c *NodeServer) template() cloud.InstanceSpec {
+	c.specMu.RLock()
+	defer c.specMu.RUnlock()
+	return c.Spec
+}
+
+// replaceKeyPair 替换节点模板中的密钥对
+func (c *NodeServer) replaceKeyPair(oldKeyId, newKeyId string) {
+	c.specMu.Lock()
+	defer c.specMu.Unlock()
+	keyIds := make([]string, len(c.Spec.KeyPairIds))
+	for n, keyId := range c.Spec.KeyPairIds {
+		if keyId == oldKeyId {
+			keyId = newKeyId
+		}
+		keyIds[n] = keyId
+	}
+	c.Spec.KeyPairIds = keyIds
+}
+
+// keyPairName 新密钥对名称, 腾讯云只允许数字、字母及下划线且不超过25个字符
+func keyPairName(now time.Time) string {
+	return "k8s_aim_" + now.UTC().Format("20060102150405")
+}
@@ -0,0 +1,69 @@
+package cloud
+
+import (
+	"errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/fake"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"testing"
+	"time"
+)
+
+// rotatingProvider 创建新密钥对并绑定到实例的轮换实现
+type rotatingProvider struct {
+	*fake.Provider
+	rotated []string // 已轮换的旧密钥对ID
+}
+
+func (p *rotatingProvider) RotateKeyPair(oldKeyId, name string, instanceIds []string, forceStop bool) (*cloud.KeyPair, error) {
+	keyPair, err := p.CreateKeyPair(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(instanceIds) > 0 {
+		if err := p.BindKeyPairs(instanceIds, keyPair.KeyId); err != nil {
+			return keyPair, err
+		}
+	}
+	p.rotated = append(p.rotated, oldKeyId)
+	return keyPair, nil
+}
+
+func TestRotateKeyPairs(t *testing.T) {
+	server, provider, _ := newTestNodeServer(t)
+	old, err := provider.CreateKeyPair("k8s_aim_old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Spec.KeyPairIds = []string{old.KeyId}
+	instanceId := createNode(t, server, provider, "worker-1")
+
+	// 不支持轮换的云厂商返回错误
+	server.KeyRotation.MaxAge = time.Millisecond
+	if _, err := server.RotateKeyPairs(); !errors.Is(err, cloud.ErrNotSupported) {
+		t.Fatalf("rotate err = %v, want ErrNotSupported", err)
+	}
+
+	rotator := &rotatingProvider{Provider: provider}
+	server.Provider = rotator
+	server.KeyRotation.MaxAge = time.Hour
+	if rotated, err := server.RotateKeyPairs(); err != nil || len(rotated) != 0 {
+		t.Fatalf("rotated = %v, %v, want none before max age", rotated, err)
+	}
+
+	server.KeyRotation.MaxAge = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	rotated, err := server.RotateKeyPairs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 1 || len(rotator.rotated) != 1 || rotator.rotated[0] != old.KeyId {
+		t.Fatalf("rotated = %+v, old keys %v, want %s rotated", rotated, rotator.rotated, old.KeyId)
+	}
+	if keyIds := server.template().KeyPairIds; len(keyIds) != 1 || keyIds[0] != rotated[0].KeyId {
+		t.Fatalf("template key pairs = %v, want [%s]", keyIds, rotated[0].KeyId)
+	}
+	instances, err := provider.DescribeInstances(&cloud.InstanceFilter{InstanceIds: []string{instanceId}})
+	if err != nil || !contains(instances[0].KeyPairIds, rotated[0].KeyId) {
+		t.Fatalf("instance key pairs = %+v, %v, want %s bound", instances, err, rotated[0].KeyId)
+	}
+}
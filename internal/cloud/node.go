@@ -1,25 +1,546 @@
 package cloud
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/eadydb/k8s-aim/config"
 	"github.com/eadydb/k8s-aim/pkg/cloud"
 	"github.com/eadydb/k8s-aim/pkg/k8s"
+	"github.com/eadydb/k8s-aim/pkg/zlog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // NodeServer New Node Server
 type NodeServer struct {
-	config.Config // Configuration
-	k8s.KClient   // kubernetes cluster client
+	config.Config                    // Configuration
+	k8s.KClient                      // kubernetes cluster client
+	Provider      cloud.Provider     // 云厂商
+	Spec          cloud.InstanceSpec // 节点实例模板, 名称和主机名取自 ClusterNode
+	Image         *cloud.ImageQuery  // 镜像查询条件, Spec 未指定镜像时创建节点前按条件选择
+	Tokens        cloud.TokenStore   // 幂等令牌存储, 为空时不保证幂等
+	Fallback      FallbackPolicy     // 机型或可用区售罄时的备选策略
+	Placement     *cloud.Placement   // 新节点的可用区分布策略, 为空时使用 Spec 中的可用区
+	NodeSelector  string             // 统计已有节点可用区分布时的标签选择器, 为空时统计全部节点
+	Interruption  InterruptionPolicy // 竞价实例回收处理策略
+	Tags          cloud.ResourceTags // 创建云资源时添加的标签
+	GC            GCPolicy           // 孤儿资源回收策略
+	KeyRotation   KeyRotationPolicy  // 节点密钥轮换策略
+
+	specMu  sync.RWMutex // 保护轮换密钥时替换的 Spec.KeyPairIds
+	gcMu    sync.Mutex
+	orphans map[string]time.Time // 孤儿资源首次发现的时间, 键为 类型/资源ID
+
+	watchMu     sync.Mutex
+	unsupported map[string]bool // 无法查询回收通知且已告警的实例ID
+}
+
+// InterruptionPolicy 竞价实例回收处理策略
+type InterruptionPolicy struct {
+	PollInterval time.Duration // 检查回收通知的间隔, 为0时默认5s
+	GracePeriod  time.Duration // 驱逐 Pod 的优雅退出时间, 为0时使用 Pod 自身配置
+	DrainTimeout time.Duration // 驱逐节点超时时间, 为0时默认90s
+}
+
+// 竞价实例回收处理默认值, 腾讯云在回收前约2分钟发出通知
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultDrainTimeout = 90 * time.Second
+)
+
+// FallbackPolicy 售罄时的备选策略
+type FallbackPolicy struct {
+	Requirement cloud.InstanceTypeRequirement // 备选机型要求, CPU、内存均未指定时使用与原机型相同的配置
+	MaxAttempts int                           // 最多尝试的备选机型数, 为0时默认3个, 为负数时不切换
+}
+
+// defaultFallbackAttempts 默认最多尝试的备选机型数
+const defaultFallbackAttempts = 3
+
+// NewNodeServer 根据配置中的 manufacturers 构建节点管理服务
+func NewNodeServer(c *config.Config, kClient *k8s.KClient) (*NodeServer, error) {
+	provider, err := cloud.NewProvider(c)
+	if err != nil {
+		return nil, err
+	}
+	if aware, ok := provider.(cloud.KeyStoreAware); ok {
+		aware.SetKeyStore(NewKeyStore(c.KeyStore, kClient))
+	}
+	var tokenDir string
+	if c.TokenStore != nil {
+		tokenDir = c.TokenStore.Dir
+	}
+	server := &NodeServer{
+		Config:   *c,
+		KClient:  *kClient,
+		Provider: provider,
+		Tokens:   cloud.NewFileTokenStore(tokenDir),
+		Tags:     cloud.NewResourceTags(c.Tags),
+	}
+	if aware, ok := provider.(cloud.TagAware); ok {
+		aware.SetTags(server.Tags)
+	}
+	if t := c.NodeTemplate; t != nil {
+		server.Spec = newInstanceSpec(t)
+		if t.ImageId == "" && t.ImageOS != "" {
+			server.Image = &cloud.ImageQuery{ImageType: cloud.ImageTypePublic, OS: t.ImageOS}
+		}
+	}
+	if f := c.Fallback; f != nil {
+		server.Fallback = FallbackPolicy{
+			Requirement: cloud.InstanceTypeRequirement{
+				MinCPU:      f.MinCPU,
+				MaxCPU:      f.MaxCPU,
+				MinMemoryGB: f.MinMemoryGB,
+				MaxMemoryGB: f.MaxMemoryGB,
+				AllowGPU:    f.AllowGPU,
+				Families:    f.Families,
+				Zones:       f.Zones,
+			},
+			MaxAttempts: f.MaxAttempts,
+		}
+	}
+	if aware, ok := provider.(cloud.DrainerAware); ok && kClient.ClientSet != nil {
+		aware.SetDrainer(server)
+	}
+	if p := c.Placement; p != nil && p.Policy != "" {
+		server.Placement = &cloud.Placement{
+			Policy:     cloud.PlacementPolicy(p.Policy),
+			Zones:      p.Zones,
+			Weights:    p.Weights,
+			MaxPerZone: p.MaxPerZone,
+		}
+		if err := server.Placement.Validate(); err != nil {
+			return nil, err
+		}
+		server.NodeSelector = p.NodeSelector
+	}
+	if s := c.Spot; s != nil {
+		if s.Enabled {
+			server.Spec.Spot = &cloud.SpotOptions{MaxPrice: s.MaxPrice, SpotType: s.SpotType}
+		}
+		server.Interruption = InterruptionPolicy{PollInterval: s.PollInterval, GracePeriod: s.GracePeriod, DrainTimeout: s.DrainTimeout}
+	}
+	if k := c.KeyRotation; k != nil {
+		server.KeyRotation = KeyRotationPolicy{Interval: k.Interval, MaxAge: k.MaxAge, ForceStop: k.ForceStop}
+	}
+	// 回收会删除资源, 未显式关闭 dry_run 时只记录
+	server.GC = GCPolicy{DryRun: true}
+	if g := c.GC; g != nil {
+		server.GC = GCPolicy{Interval: g.Interval, GracePeriod: g.GracePeriod, JoinTimeout: g.JoinTimeout, DryRun: g.DryRun == nil || *g.DryRun, AuditLog: g.AuditLog}
+	}
+	return server, nil
+}
+
+// newInstanceSpec 根据节点模板配置构建实例模板
+func newInstanceSpec(t *config.NodeTemplate) cloud.InstanceSpec {
+	spec := cloud.InstanceSpec{
+		Zone:              t.Zone,
+		InstanceType:      t.InstanceType,
+		ImageId:           t.ImageId,
+		VpcId:             t.VpcId,
+		SubnetId:          t.SubnetId,
+		SecurityGroupIds:  t.SecurityGroupIds,
+		KeyPairIds:        t.KeyPairIds,
+		InternetBandwidth: t.InternetBandwidth,
+		UserData:          t.UserData,
+		Tags:              t.Tags,
+	}
+	if t.SystemDisk != nil {
+		spec.SystemDisk = &cloud.Disk{Type: t.SystemDisk.Type, SizeGB: t.SystemDisk.SizeGB}
+	}
+	for _, disk := range t.DataDisks {
+		spec.DataDisks = append(spec.DataDisks, cloud.Disk{Type: disk.Type, SizeGB: disk.SizeGB})
+	}
+	return spec
 }
 
 func (c *NodeServer) CreateClusterNode(node cloud.ClusterNode) (bool, error) {
+	if c.Provider == nil {
+		return false, fmt.Errorf("no cloud provider for manufacturer %s", c.Manufacturers)
+	}
+	tags, err := cloud.ParseTags(node.Tags)
+	if err != nil {
+		return false, err
+	}
+	spec := c.template()
+	spec.Name = node.Name
+	spec.HostName = node.HostName
+	spec.Count = 1
+	spec.Tags = cloud.MergeTags(tags, spec.Tags, c.Tags.Tags())
+	if spec.InstanceType == "" {
+		return false, fmt.Errorf("node_template.instance_type is required to create cluster nodes")
+	}
+	zones, err := c.place(&spec)
+	if err != nil {
+		return false, err
+	}
+	if spec.ImageId == "" && c.Image != nil {
+		query := *c.Image
+		query.InstanceType = spec.InstanceType
+		image, err := c.Provider.GetImage(&query)
+		if err != nil {
+			return false, err
+		}
+		spec.ImageId = image.ImageId
+	}
+	instances, err := c.createInstance(node, spec)
+	if errors.Is(err, cloud.ErrInsufficientStock) {
+		instances, err = c.fallback(node, spec, zones, err)
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, instance := range instances {
+		zlog.Infof("create cluster node %s, instance %s, type %s, zone %s, private ips %v",
+			node.Name, instance.InstanceId, instance.InstanceType, instance.Zone, instance.PrivateIps)
+	}
 	return true, nil
 }
 
+// createInstance 使用节点的幂等令牌创建实例
+// 节点加入集群前令牌一直保留, 超时等结果不确定时重试会复用令牌, 由云厂商去重或按标签找回已创建的实例,
+// 孤儿资源回收也据此跳过尚未加入集群的实例, 参见 Orphans; 售罄时确定未创建实例, 删除令牌以便备选机型使用新令牌
+func (c *NodeServer) createInstance(node cloud.ClusterNode, spec cloud.InstanceSpec) ([]cloud.InstanceInfo, error) {
+	if c.Tokens != nil {
+		token, err := cloud.ClientToken(c.Tokens, node.Name)
+		if err != nil {
+			return nil, err
+		}
+		tags := spec.Tags
+		spec.ClientToken = token
+		spec.Tags = make(map[string]string, len(tags)+1)
+		for k, v := range tags {
+			spec.Tags[k] = v
+		}
+		spec.Tags[cloud.ClientTokenTag] = token
+	}
+	instances, err := c.Provider.CreateInstance(&spec)
+	if c.Tokens != nil && errors.Is(err, cloud.ErrInsufficientStock) {
+		if err := c.Tokens.Delete(node.Name); err != nil {
+			zlog.Warnf("delete client token of node %s failed, %s", node.Name, err)
+		}
+	}
+	return instances, err
+}
+
+// place 按可用区分布策略选择新节点的可用区及子网, 返回按优先级排列的可用区, 未配置策略时返回 nil
+func (c *NodeServer) place(spec *cloud.InstanceSpec) ([]string, error) {
+	if c.Placement == nil {
+		return nil, nil
+	}
+	provider, ok := c.Provider.(cloud.Zones)
+	if !ok {
+		return nil, fmt.Errorf("manufacturer %s does not support zone placement", c.Manufacturers)
+	}
+	zones, err := provider.DescribeZones()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := c.zoneCounts()
+	if err != nil {
+		return nil, err
+	}
+	ranked, err := c.Placement.Rank(zones, counts)
+	if err != nil {
+		return nil, err
+	}
+	for _, zone := range ranked {
+		if err := c.useZone(spec, zone); err != nil {
+			zlog.Warnf("skip zone %s for node %s, %s", zone, spec.Name, err)
+			continue
+		}
+		zlog.Infof("place node %s in zone %s by %s policy, nodes per zone %v", spec.Name, zone, c.Placement.Policy, counts)
+		return ranked, nil
+	}
+	return nil, fmt.Errorf("%w: no subnet of vpc %s in zones %v", cloud.ErrNoAvailableZone, spec.VpcId, ranked)
+}
+
+// zoneCounts 统计各可用区已有节点数, 即集群节点数加上带有资源标签、未终止且尚未加入集群的实例数
+// JoinCluster 完成前新建的实例不在集群中, 同一批次内先创建的实例也计入, 避免 balanced 等策略将整批节点放在同一可用区
+func (c *NodeServer) zoneCounts() (map[string]int, error) {
+	counts := make(map[string]int)
+	refs := &k8s.NodeRefs{}
+	if c.ClientSet != nil {
+		nodes, err := c.NodeZones(c.NodeSelector)
+		if err != nil {
+			return nil, err
+		}
+		for zone, n := range nodes {
+			counts[zone] = n
+		}
+		if refs, err = c.NodeRefs(); err != nil {
+			return nil, err
+		}
+	}
+	instances, err := c.Provider.DescribeInstances(&cloud.InstanceFilter{Tags: c.Tags.Selector()})
+	if err != nil {
+		return nil, err
+	}
+	for _, instance := range instances {
+		ips := append(append([]string(nil), instance.PrivateIps...), instance.PublicIps...)
+		switch {
+		case instance.Zone == "":
+		case instance.State == cloud.InstanceStateTerminating || instance.State == cloud.InstanceStateTerminated:
+		case refs.Has(instance.InstanceId, ips...):
+		default:
+			counts[instance.Zone]++
+		}
+	}
+	return counts, nil
+}
+
+// useZone 将实例放置到可用区, 指定的子网不在该可用区时选择可用IP最多的子网
+func (c *NodeServer) useZone(spec *cloud.InstanceSpec, zone string) error {
+	if spec.Zone == zone && spec.SubnetId != "" {
+		return nil
+	}
+	subnets, err := c.Provider.DescribeSubnets(spec.VpcId, zone)
+	if err != nil {
+		return err
+	}
+	for _, subnet := range subnets {
+		if subnet.SubnetId == spec.SubnetId {
+			spec.Zone = zone
+			return nil
+		}
+	}
+	subnet, err := cloud.PickSubnet(subnets, zone, spec.Count)
+	if err != nil {
+		return err
+	}
+	spec.Zone, spec.SubnetId = zone, subnet.SubnetId
+	return nil
+}
+
+// fallback 原机型售罄时按库存依次尝试同配置的其他机型或其他可用区, 云厂商不支持机型目录时返回原错误
+// zones 为可用区分布策略排序后的可用区, 未指定备选可用区时只在其中选择
+func (c *NodeServer) fallback(node cloud.ClusterNode, spec cloud.InstanceSpec, zones []string, cause error) ([]cloud.InstanceInfo, error) {
+	catalog, ok := c.Provider.(cloud.InstanceTypes)
+	attempts := c.Fallback.MaxAttempts
+	if attempts == 0 {
+		attempts = defaultFallbackAttempts
+	}
+	if !ok || attempts < 0 {
+		return nil, cause
+	}
+	offerings, err := catalog.DescribeInstanceTypes()
+	if err != nil {
+		zlog.Warnf("describe instance types for node %s failed, %s", node.Name, err)
+		return nil, cause
+	}
+	req := c.Fallback.Requirement
+	if req.MinCPU == 0 && req.MaxCPU == 0 && req.MinMemoryGB == 0 && req.MaxMemoryGB == 0 {
+		current, ok := cloud.FindInstanceType(offerings, spec.Zone, spec.InstanceType)
+		if !ok {
+			zlog.Warnf("instance type %s of node %s not found in catalog, no fallback", spec.InstanceType, node.Name)
+			return nil, cause
+		}
+		req.MinCPU, req.MaxCPU = current.CPU, current.CPU
+		req.MinMemoryGB, req.MaxMemoryGB = current.MemoryGB, current.MemoryGB
+		req.AllowGPU = req.AllowGPU || current.GPU > 0
+	}
+	if len(req.Zones) == 0 {
+		req.Zones = zones
+	}
+	candidates := cloud.SelectInstanceTypes(offerings, req)
+	// 同一可用区的机型优先, 无需更换子网
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Zone == spec.Zone && candidates[j].Zone != spec.Zone
+	})
+	for _, candidate := range candidates {
+		if attempts == 0 {
+			break
+		}
+		if !candidate.InStock || candidate.Zone == spec.Zone && candidate.InstanceType == spec.InstanceType {
+			continue
+		}
+		next := spec
+		next.InstanceType = candidate.InstanceType
+		if candidate.Zone != spec.Zone {
+			if err := c.useZone(&next, candidate.Zone); err != nil {
+				zlog.Warnf("skip zone %s for node %s, %s", candidate.Zone, node.Name, err)
+				continue
+			}
+		}
+		attempts--
+		zlog.Warnf("%s, node %s fall back to instance type %s in zone %s", cause, node.Name, next.InstanceType, next.Zone)
+		instances, err := c.createInstance(node, next)
+		if !errors.Is(err, cloud.ErrInsufficientStock) {
+			return instances, err
+		}
+		cause = err
+	}
+	return nil, cause
+}
+
 func (c *NodeServer) JoinCluster(node cloud.ClusterNode) (bool, error) {
 	return true, nil
 }
 
+// Monitor 检查节点的竞价实例是否收到回收通知, 收到时封锁并驱逐节点, 然后创建替换节点
+// 云厂商无法查询该实例的回收通知时每个实例只告警一次, 需定期调用, 参见 Watch
 func (c *NodeServer) Monitor(node cloud.ClusterNode) error {
+	if c.Provider == nil {
+		return fmt.Errorf("no cloud provider for manufacturer %s", c.Manufacturers)
+	}
+	instances, err := c.Provider.DescribeInstances(&cloud.InstanceFilter{Name: node.Name})
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		if !instance.Spot || instance.State == cloud.InstanceStateTerminated {
+			continue
+		}
+		notice, err := c.Provider.DescribeInterruption(instance.InstanceId)
+		if errors.Is(err, cloud.ErrNotSupported) {
+			c.warnUnsupported(node, instance, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if notice != nil {
+			return c.replace(node, instance, notice)
+		}
+	}
 	return nil
 }
+
+// Watch 按 Interruption.PollInterval 定期检查 nodes 返回的节点, 直到 ctx 结束
+// 云厂商只能在竞价实例自身查询回收通知时无法集中检查, 直接返回错误, 参见 cloud.InterruptionSource
+func (c *NodeServer) Watch(ctx context.Context, nodes func() []cloud.ClusterNode) error {
+	if source, ok := c.Provider.(cloud.InterruptionSource); !ok || !source.RemoteInterruption() {
+		return fmt.Errorf("%w: manufacturer %s cannot query interruption notices of other instances", cloud.ErrNotSupported, c.Manufacturers)
+	}
+	interval := c.Interruption.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, node := range nodes() {
+			if err := c.Monitor(node); err != nil {
+				zlog.Warnf("monitor node %s failed, %s", node.Name, err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// warnUnsupported 无法查询实例的回收通知, 每个实例只告警一次
+func (c *NodeServer) warnUnsupported(node cloud.ClusterNode, instance cloud.InstanceInfo, err error) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+	if c.unsupported[instance.InstanceId] {
+		return
+	}
+	if c.unsupported == nil {
+		c.unsupported = make(map[string]bool)
+	}
+	c.unsupported[instance.InstanceId] = true
+	zlog.Warnf("interruption notices of spot instance %s of node %s cannot be queried, %s", instance.InstanceId, node.Name, err)
+}
+
+// SpotNodes 带有资源标签且未销毁的竞价实例对应的节点, 作为 Watch 检查的节点, 查询失败时返回空
+func (c *NodeServer) SpotNodes() []cloud.ClusterNode {
+	instances, err := c.Provider.DescribeInstances(nil)
+	if err != nil {
+		zlog.Warnf("describe spot instances failed, %s", err)
+		return nil
+	}
+	var nodes []cloud.ClusterNode
+	for _, instance := range instances {
+		if !instance.Spot || instance.State == cloud.InstanceStateTerminating || instance.State == cloud.InstanceStateTerminated {
+			continue
+		}
+		node := cloud.ClusterNode{Name: instance.Name}
+		if len(instance.PrivateIps) > 0 {
+			node.Ip = instance.PrivateIps[0]
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// replace 竞价实例即将回收, 封锁并驱逐实例对应的集群节点后创建替换节点, 替换节点已存在时不再处理
+func (c *NodeServer) replace(node cloud.ClusterNode, instance cloud.InstanceInfo, notice *cloud.Interruption) error {
+	replacement := replacementNode(node, notice.InstanceId)
+	existing, err := c.Provider.DescribeInstances(&cloud.InstanceFilter{Name: replacement.Name})
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if e.State != cloud.InstanceStateTerminated && e.State != cloud.InstanceStateFailed {
+			return nil
+		}
+	}
+	zlog.Warnf("spot instance %s of node %s will be terminated at %s, replace with node %s",
+		notice.InstanceId, node.Name, notice.Time.Format(time.RFC3339), replacement.Name)
+	// 驱逐失败时仍需创建替换节点, 实例很快会被回收
+	if err := c.DrainInstance(instance); err != nil {
+		zlog.Warnf("drain node of spot instance %s failed, %s", instance.InstanceId, err)
+	}
+	if _, err := c.CreateClusterNode(replacement); err != nil {
+		return err
+	}
+	_, err = c.JoinCluster(replacement)
+	return err
+}
+
+var _ cloud.NodeDrainer = (*NodeServer)(nil)
+
+// DrainInstance 封锁并驱逐实例对应的节点, 实例未加入集群时不做处理
+func (c *NodeServer) DrainInstance(instance cloud.InstanceInfo) error {
+	name, err := c.nodeName(instance)
+	if err != nil || name == "" {
+		return err
+	}
+	if err := c.Cordon(name); err != nil {
+		return err
+	}
+	return c.Drain(name, c.Interruption.GracePeriod, c.drainTimeout())
+}
+
+// UncordonInstance 恢复实例对应节点的调度, 实例未加入集群时不做处理
+func (c *NodeServer) UncordonInstance(instance cloud.InstanceInfo) error {
+	name, err := c.nodeName(instance)
+	if err != nil || name == "" {
+		return err
+	}
+	return c.Uncordon(name)
+}
+
+// nodeName 实例对应的集群节点名称, 未设置 kubernetes 客户端或实例未加入集群时为空
+func (c *NodeServer) nodeName(instance cloud.InstanceInfo) (string, error) {
+	if c.ClientSet == nil {
+		return "", nil
+	}
+	ips := append(append([]string(nil), instance.PrivateIps...), instance.PublicIps...)
+	return c.NodeName(instance.InstanceId, ips...)
+}
+
+func (c *NodeServer) drainTimeout() time.Duration {
+	if c.Interruption.DrainTimeout <= 0 {
+		return defaultDrainTimeout
+	}
+	return c.Interruption.DrainTimeout
+}
+
+// replacementNode 替换节点, 名称为原节点名称加被回收实例ID的后缀, 重复处理同一通知时名称不变
+func replacementNode(node cloud.ClusterNode, instanceId string) cloud.ClusterNode {
+	suffix := strings.ToLower(instanceId[strings.LastIndex(instanceId, "-")+1:])
+	replacement := cloud.ClusterNode{Name: node.Name + "-" + suffix, Tags: node.Tags}
+	if node.HostName != "" {
+		replacement.HostName = node.HostName + "-" + suffix
+	}
+	return replacement
+}
@@ -0,0 +1,275 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/internal/cloud/fake"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// apiServer 只实现节点列表、节点封锁、Pod 列表及驱逐的 kubernetes API 模拟服务
+type apiServer struct {
+	mu      sync.Mutex
+	nodes   []corev1.Node
+	pods    []corev1.Pod
+	evicted []string // 已驱逐的 Pod, 格式为 namespace/name
+}
+
+func (s *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/api/v1/nodes":
+		json.NewEncoder(w).Encode(&corev1.NodeList{TypeMeta: metav1.TypeMeta{Kind: "NodeList", APIVersion: "v1"}, Items: s.nodes})
+	case strings.HasPrefix(r.URL.Path, "/api/v1/nodes/") && r.Method == http.MethodPatch:
+		s.patchNode(w, r)
+	case r.URL.Path == "/api/v1/pods":
+		// 只支持 spec.nodeName=<节点名称> 的字段选择器
+		nodeName := strings.TrimPrefix(r.URL.Query().Get("fieldSelector"), "spec.nodeName=")
+		list := corev1.PodList{TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"}}
+		for _, pod := range s.pods {
+			if pod.Spec.NodeName == nodeName {
+				list.Items = append(list.Items, pod)
+			}
+		}
+		json.NewEncoder(w).Encode(&list)
+	case strings.HasSuffix(r.URL.Path, "/eviction") && r.Method == http.MethodPost:
+		// /api/v1/namespaces/{namespace}/pods/{name}/eviction
+		parts := strings.Split(r.URL.Path, "/")
+		s.evicted = append(s.evicted, parts[4]+"/"+parts[6])
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// patchNode 只处理 spec.unschedulable
+func (s *apiServer) patchNode(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/nodes/")
+	var patch corev1.Node
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for i := range s.nodes {
+		if s.nodes[i].Name == name {
+			s.nodes[i].Spec.Unschedulable = patch.Spec.Unschedulable
+			json.NewEncoder(w).Encode(&s.nodes[i])
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// addNode 添加 providerID 以实例ID结尾的节点
+func (s *apiServer) addNode(name, instanceId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1.NodeSpec{ProviderID: "fake:///" + instanceId}}
+	s.nodes = append(s.nodes, node)
+}
+
+// addPod 在节点上添加 Pod
+func (s *apiServer) addPod(namespace, name, nodeName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}, Spec: corev1.PodSpec{NodeName: nodeName}}
+	s.pods = append(s.pods, pod)
+}
+
+// node 查询节点
+func (s *apiServer) node(name string) corev1.Node {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, node := range s.nodes {
+		if node.Name == name {
+			return node
+		}
+	}
+	return corev1.Node{}
+}
+
+// newTestNodeServer 使用模拟云厂商及 kubernetes API 的节点管理服务, 回收不等待宽限期
+func newTestNodeServer(t *testing.T) (*NodeServer, *fake.Provider, *apiServer) {
+	t.Helper()
+	api := &apiServer{}
+	s := httptest.NewServer(api)
+	t.Cleanup(s.Close)
+	clientSet, err := kubernetes.NewForConfig(&rest.Config{Host: s.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := fake.New(fake.Options{Seed: 1})
+	tags := cloud.NewResourceTags(&config.Tags{Cluster: "test"})
+	provider.SetTags(tags)
+	image, err := provider.GetImage(&cloud.ImageQuery{ImageType: cloud.ImageTypePublic, OS: "ubuntu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	server := &NodeServer{
+		KClient:  k8s.KClient{ClientSet: clientSet, Ctx: context.Background()},
+		Provider: provider,
+		Spec:     cloud.InstanceSpec{InstanceType: "S1.MEDIUM4", ImageId: image.ImageId},
+		Tokens:   cloud.NewFileTokenStore(filepath.Join(dir, "tokens")),
+		Tags:     tags,
+		GC:       GCPolicy{GracePeriod: 1, AuditLog: filepath.Join(dir, "gc-audit.log")},
+	}
+	return server, provider, api
+}
+
+// createNode 创建节点实例, 返回实例ID
+func createNode(t *testing.T, server *NodeServer, provider *fake.Provider, name string) string {
+	t.Helper()
+	if _, err := server.CreateClusterNode(cloud.ClusterNode{Name: name}); err != nil {
+		t.Fatal(err)
+	}
+	instances, err := provider.DescribeInstances(&cloud.InstanceFilter{Name: name})
+	if err != nil || len(instances) != 1 {
+		t.Fatalf("instances of node %s = %v, %v", name, instances, err)
+	}
+	return instances[0].InstanceId
+}
+
+// metadataOnly 只能在实例自身查询回收通知的云厂商
+type metadataOnly struct {
+	cloud.Provider
+}
+
+func (metadataOnly) DescribeInterruption(instanceId string) (*cloud.Interruption, error) {
+	return nil, cloud.ErrNotSupported
+}
+
+func TestMonitorReplacesInterruptedSpotNode(t *testing.T) {
+	server, provider, api := newTestNodeServer(t)
+	server.Spec.Spot = &cloud.SpotOptions{MaxPrice: 0.2}
+	spot := createNode(t, server, provider, "worker-spot-1")
+	// 集群节点名称与实例名称不同, 按 providerID 匹配
+	api.addNode("10.0.1.2", spot)
+	api.addPod("default", "app", "10.0.1.2")
+	node := cloud.ClusterNode{Name: "worker-spot-1"}
+
+	if err := server.Monitor(node); err != nil {
+		t.Fatal(err)
+	}
+	if api.node("10.0.1.2").Spec.Unschedulable {
+		t.Fatal("node cordoned without interruption notice")
+	}
+
+	if err := provider.Interrupt(spot, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Monitor(node); err != nil {
+		t.Fatal(err)
+	}
+	if !api.node("10.0.1.2").Spec.Unschedulable {
+		t.Fatal("interrupted node not cordoned")
+	}
+	api.mu.Lock()
+	evicted := append([]string(nil), api.evicted...)
+	api.mu.Unlock()
+	if len(evicted) != 1 || evicted[0] != "default/app" {
+		t.Fatalf("evicted pods = %v, want [default/app]", evicted)
+	}
+	replacement := replacementNode(node, spot)
+	instances, err := provider.DescribeInstances(&cloud.InstanceFilter{Name: replacement.Name})
+	if err != nil || len(instances) != 1 || !instances[0].Spot {
+		t.Fatalf("replacement instances = %+v, %v, want one spot instance", instances, err)
+	}
+
+	// 重复处理同一通知时不再创建替换节点
+	if err := server.Monitor(node); err != nil {
+		t.Fatal(err)
+	}
+	if instances, err = provider.DescribeInstances(&cloud.InstanceFilter{Name: replacement.Name}); err != nil || len(instances) != 1 {
+		t.Fatalf("replacement instances = %+v, %v, want still one", instances, err)
+	}
+}
+
+func TestWatchRequiresRemoteInterruption(t *testing.T) {
+	server, provider, _ := newTestNodeServer(t)
+	server.Spec.Spot = &cloud.SpotOptions{MaxPrice: 0.2}
+	createNode(t, server, provider, "worker-spot-1")
+	server.Provider = metadataOnly{provider}
+
+	// 无法查询时不处理也不报错
+	if err := server.Monitor(cloud.ClusterNode{Name: "worker-spot-1"}); err != nil {
+		t.Fatalf("monitor err = %v, want nil", err)
+	}
+	if err := server.Watch(context.Background(), server.SpotNodes); !errors.Is(err, cloud.ErrNotSupported) {
+		t.Fatalf("watch err = %v, want ErrNotSupported", err)
+	}
+
+	server.Provider = provider
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := server.Watch(ctx, server.SpotNodes); err != nil {
+		t.Fatalf("watch err = %v, want nil after cancel", err)
+	}
+}
+
+func TestSpotNodes(t *testing.T) {
+	server, provider, _ := newTestNodeServer(t)
+	createNode(t, server, provider, "worker-1")
+	server.Spec.Spot = &cloud.SpotOptions{MaxPrice: 0.2}
+	spot := createNode(t, server, provider, "worker-spot-1")
+	createNode(t, server, provider, "worker-spot-2")
+
+	nodes := server.SpotNodes()
+	if len(nodes) != 2 || nodes[0].Name == "worker-1" || nodes[1].Name == "worker-1" || nodes[0].Ip == "" {
+		t.Fatalf("spot nodes = %+v, want worker-spot-1 and worker-spot-2 with ip", nodes)
+	}
+	// 已回收的竞价实例不再检查
+	if err := provider.TerminateInstance(spot); err != nil {
+		t.Fatal(err)
+	}
+	if nodes = server.SpotNodes(); len(nodes) != 1 || nodes[0].Name != "worker-spot-2" {
+		t.Fatalf("spot nodes = %+v, want worker-spot-2", nodes)
+	}
+}
+
+func TestPlaceCountsInstancesNotYetJoined(t *testing.T) {
+	server, provider, api := newTestNodeServer(t)
+	server.Spec.VpcId = "vpc-fake"
+	server.Placement = &cloud.Placement{Policy: cloud.PlacementBalanced}
+	zoneOf := func(name string) string {
+		instances, err := provider.DescribeInstances(&cloud.InstanceFilter{Name: name})
+		if err != nil || len(instances) != 1 {
+			t.Fatalf("instances of node %s = %v, %v", name, instances, err)
+		}
+		return instances[0].Zone
+	}
+
+	// 同一批次的节点均未加入集群, 按云上实例分布到不同可用区
+	first := createNode(t, server, provider, "worker-1")
+	createNode(t, server, provider, "worker-2")
+	if a, b := zoneOf("worker-1"), zoneOf("worker-2"); a == b {
+		t.Fatalf("worker-1 and worker-2 both placed in %s", a)
+	}
+
+	// 已加入集群的实例只按集群节点计数一次
+	api.addNode("worker-1", first)
+	api.mu.Lock()
+	api.nodes[0].Labels = map[string]string{k8s.LabelTopologyZone: zoneOf("worker-1")}
+	api.mu.Unlock()
+	createNode(t, server, provider, "worker-3")
+	if zone := zoneOf("worker-3"); zone != "fake-zone-1" {
+		t.Fatalf("worker-3 placed in %s, want fake-zone-1", zone)
+	}
+}
@@ -0,0 +1,264 @@
+package iaas
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	APIVersion      = "1"
+	DefaultEndpoint = "https://api.qingcloud.com/iaas/"
+	signatureMethod = "HmacSHA256"
+	timestampFormat = "2006-01-02T15:04:05Z"
+)
+
+// 任务状态
+const (
+	JobStatusPending    = "pending"
+	JobStatusWorking    = "working"
+	JobStatusSuccessful = "successful"
+	JobStatusFailed     = "failed"
+)
+
+// 可重试的返回码: 1400 请求过于频繁, 5000 内部错误, 5100 资源繁忙
+var retryableCodes = map[int]bool{1400: true, 5000: true, 5100: true}
+
+// retCodeThrottled 请求过于频繁, 服务端未处理请求
+const retCodeThrottled = 1400
+
+// Error 青云 API 错误
+type Error struct {
+	HttpStatus int    // HTTP 状态码
+	RetCode    int    `json:"ret_code"` // 返回码, 0 表示成功
+	Message    string `json:"message"`  // 错误信息
+	Action     string // 接口名称
+
+	unsent bool // 请求未发送到服务端, 如建立连接失败
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("[QingCloudSDKError] Action=%s, RetCode=%d, Message=%s", e.Action, e.RetCode, e.Message)
+}
+
+// Response 公共响应字段
+type Response struct {
+	Action  string `json:"action"`
+	RetCode int    `json:"ret_code"`
+	Message string `json:"message"`
+	JobId   string `json:"job_id"`
+}
+
+// Client IaaS 客户端
+type Client struct {
+	AccessKeyId     string        // API 密钥ID
+	SecretAccessKey string        // API 密钥私钥
+	Zone            string        // 区域, 如 pek3、sh1a
+	Endpoint        string        // 服务地址, 默认 https://api.qingcloud.com/iaas/
+	MaxAttempts     int           // 最大尝试次数
+	RetryDelay      time.Duration // 重试间隔基数
+	httpClient      *http.Client
+}
+
+// NewClient 实例化, endpoint 为空时使用公有云地址
+func NewClient(accessKeyId, secretAccessKey, zone, endpoint string) *Client {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	return &Client{
+		AccessKeyId:     accessKeyId,
+		SecretAccessKey: secretAccessKey,
+		Zone:            zone,
+		Endpoint:        endpoint,
+		MaxAttempts:     3,
+		RetryDelay:      500 * time.Millisecond,
+		httpClient:      &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Do 调用 action, 结果解析到 resp
+// 只读的 Describe* 接口在网络错误、5xx 及可重试的返回码时重试; 青云不对其他接口去重,
+// 服务端可能已受理请求时不重试, 避免重复创建实例等资源
+func (c *Client) Do(ctx context.Context, action string, params Params, resp interface{}) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.do(ctx, action, params, resp)
+		if err == nil || attempt >= c.MaxAttempts || !retryable(action, err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.RetryDelay << uint(attempt-1)):
+		}
+	}
+}
+
+// do 发送一次请求
+func (c *Client) do(ctx context.Context, action string, params Params, resp interface{}) error {
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	query.Set("action", action)
+	query.Set("version", APIVersion)
+	query.Set("access_key_id", c.AccessKeyId)
+	query.Set("signature_method", signatureMethod)
+	query.Set("signature_version", "1")
+	query.Set("time_stamp", time.Now().UTC().Format(timestampFormat))
+	if query.Get("zone") == "" {
+		query.Set("zone", c.Zone)
+	}
+	signature := Sign(http.MethodGet, endpoint.Path, query, c.SecretAccessKey)
+	rawQuery := canonicalize(query) + "&signature=" + url.QueryEscape(signature)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Endpoint+"?"+rawQuery, nil)
+	if err != nil {
+		return err
+	}
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		unsent := errors.As(err, &opErr) && opErr.Op == "dial"
+		return &Error{RetCode: -1, Message: err.Error(), Action: action, unsent: unsent}
+	}
+	defer httpResp.Body.Close()
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return &Error{HttpStatus: httpResp.StatusCode, RetCode: -1, Message: err.Error(), Action: action}
+	}
+	e := &Error{HttpStatus: httpResp.StatusCode, Action: action}
+	if err := json.Unmarshal(body, e); err != nil {
+		e.RetCode, e.Message = -1, fmt.Sprintf("http status %d, %s", httpResp.StatusCode, body)
+		return e
+	}
+	if e.RetCode != 0 {
+		return e
+	}
+	if resp == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return &Error{HttpStatus: httpResp.StatusCode, RetCode: -1, Message: err.Error(), Action: action}
+	}
+	return nil
+}
+
+// Sign 计算签名, 待签名字符串为 GET\n/iaas/\n 加排序后的参数
+func Sign(method, path string, query url.Values, secret string) string {
+	values := url.Values{}
+	for k, v := range query {
+		if k != "signature" {
+			values[k] = v
+		}
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + path + "\n" + canonicalize(values)))
+	return strings.TrimSpace(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+// canonicalize 按参数名排序并编码, 空格编码为 %20
+func canonicalize(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, escape(k)+"="+escape(values.Get(k)))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escape URL 编码
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// retryable 是否可重试, 非 Describe* 接口只在确定服务端未处理请求时重试
+func retryable(action string, err error) bool {
+	e, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	if e.unsent || e.RetCode == retCodeThrottled {
+		return true
+	}
+	if !strings.HasPrefix(action, "Describe") {
+		return false
+	}
+	return retryableCodes[e.RetCode] || e.RetCode == -1 && e.HttpStatus == 0 || e.HttpStatus >= http.StatusInternalServerError
+}
+
+// WaitJob 轮询直到异步任务结束
+func (c *Client) WaitJob(ctx context.Context, jobId string, interval time.Duration) error {
+	if jobId == "" {
+		return nil
+	}
+	for {
+		var resp DescribeJobsResponse
+		if err := c.Do(ctx, "DescribeJobs", Params{}.SetList("jobs", []string{jobId}), &resp); err != nil {
+			return err
+		}
+		if len(resp.JobSet) > 0 {
+			switch resp.JobSet[0].Status {
+			case JobStatusSuccessful:
+				return nil
+			case JobStatusFailed:
+				return fmt.Errorf("job %s %s failed", jobId, resp.JobSet[0].JobAction)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait job %s, %s", jobId, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Params 请求参数
+type Params map[string]string
+
+// Set 设置参数, 值为空时忽略
+func (p Params) Set(key, value string) Params {
+	if value != "" {
+		p[key] = value
+	}
+	return p
+}
+
+// SetInt 设置整数参数, 值为0时忽略
+func (p Params) SetInt(key string, value int64) Params {
+	if value != 0 {
+		p[key] = strconv.FormatInt(value, 10)
+	}
+	return p
+}
+
+// SetList 设置 key.n 形式的列表参数
+func (p Params) SetList(key string, values []string) Params {
+	for i, v := range values {
+		p[key+"."+strconv.Itoa(i+1)] = v
+	}
+	return p
+}
@@ -0,0 +1,78 @@
+package iaas
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient 启动按 status、body 返回的模拟服务, 返回客户端及请求计数
+func newTestClient(t *testing.T, status int, body string) (*Client, *int32) {
+	t.Helper()
+	var calls int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	c := NewClient("key", "secret", "pek3", s.URL+"/iaas/")
+	c.RetryDelay = time.Millisecond
+	return c, &calls
+}
+
+func TestDoRetriesDescribeOnServerError(t *testing.T) {
+	c, calls := newTestClient(t, http.StatusBadGateway, "bad gateway")
+	if err := c.Do(context.Background(), "DescribeInstances", Params{}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := atomic.LoadInt32(calls); n != 3 {
+		t.Fatalf("DescribeInstances sent %d times, want 3", n)
+	}
+}
+
+func TestDoDoesNotRetryRunInstancesOnServerError(t *testing.T) {
+	c, calls := newTestClient(t, http.StatusGatewayTimeout, "gateway timeout")
+	if err := c.Do(context.Background(), "RunInstances", Params{}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Fatalf("RunInstances sent %d times, want 1", n)
+	}
+}
+
+func TestDoDoesNotRetryRunInstancesOnInternalError(t *testing.T) {
+	c, calls := newTestClient(t, http.StatusOK, `{"ret_code":5000,"message":"internal error"}`)
+	if err := c.Do(context.Background(), "RunInstances", Params{}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Fatalf("RunInstances sent %d times, want 1", n)
+	}
+}
+
+func TestDoRetriesRunInstancesWhenThrottled(t *testing.T) {
+	c, calls := newTestClient(t, http.StatusOK, `{"ret_code":1400,"message":"too many requests"}`)
+	if err := c.Do(context.Background(), "RunInstances", Params{}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := atomic.LoadInt32(calls); n != 3 {
+		t.Fatalf("RunInstances sent %d times, want 3", n)
+	}
+}
+
+func TestRetryableRunInstancesBeforeSend(t *testing.T) {
+	s := httptest.NewServer(http.NotFoundHandler())
+	s.Close()
+	c := NewClient("key", "secret", "pek3", s.URL+"/iaas/")
+	err := c.do(context.Background(), "RunInstances", Params{}, nil)
+	if err == nil {
+		t.Fatal("expected connection error")
+	}
+	if !retryable("RunInstances", err) {
+		t.Fatalf("RunInstances not retryable after dial error %v", err)
+	}
+}
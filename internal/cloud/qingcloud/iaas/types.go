@@ -0,0 +1,281 @@
+package iaas
+
+// 实例状态
+const (
+	InstanceStatusPending    = "pending"    // 创建中
+	InstanceStatusRunning    = "running"    // 运行中
+	InstanceStatusStopped    = "stopped"    // 已关机
+	InstanceStatusSuspended  = "suspended"  // 欠费暂停
+	InstanceStatusTerminated = "terminated" // 已删除, 可恢复
+	InstanceStatusCeased     = "ceased"     // 已彻底删除
+)
+
+// 镜像状态
+const (
+	ImageStatusAvailable = "available" // 可用
+)
+
+// 镜像来源
+const (
+	ImageProviderSystem = "system" // 系统镜像
+	ImageProviderSelf   = "self"   // 自有镜像
+)
+
+// 弹性公网IP状态
+const (
+	EipStatusPending    = "pending"    // 分配中
+	EipStatusAvailable  = "available"  // 可用
+	EipStatusAssociated = "associated" // 已绑定
+)
+
+// 网卡角色
+const (
+	NicRolePrimary = 1 // 主网卡
+)
+
+// 安全组规则方向
+const (
+	DirectionIngress = 0 // 下行(入站)
+	DirectionEgress  = 1 // 上行(出站)
+)
+
+// Job 异步任务
+type Job struct {
+	JobId     string `json:"job_id"`
+	JobAction string `json:"job_action"`
+	Status    string `json:"status"`
+}
+
+// DescribeJobsResponse 查询任务响应结果
+type DescribeJobsResponse struct {
+	Response
+	JobSet []Job `json:"job_set"`
+}
+
+// Tag 标签, 青云标签只有名称, 使用 key=value 形式的名称表示键值
+type Tag struct {
+	TagId   string `json:"tag_id"`
+	TagName string `json:"tag_name"`
+}
+
+// DescribeTagsResponse 查询标签响应结果
+type DescribeTagsResponse struct {
+	Response
+	TotalCount int64 `json:"total_count"`
+	TagSet     []Tag `json:"tag_set"`
+}
+
+// CreateTagResponse 创建标签响应结果
+type CreateTagResponse struct {
+	Response
+	TagId string `json:"tag_id"`
+}
+
+// InstanceVxnet 实例所在私有网络
+type InstanceVxnet struct {
+	VxnetId   string `json:"vxnet_id"`
+	VxnetName string `json:"vxnet_name"`
+	PrivateIp string `json:"private_ip"`
+	NicId     string `json:"nic_id"`
+}
+
+// Instance 实例信息
+type Instance struct {
+	InstanceId       string          `json:"instance_id"`       // 实例ID
+	InstanceName     string          `json:"instance_name"`     // 实例名称
+	InstanceType     string          `json:"instance_type"`     // 实例类型
+	Status           string          `json:"status"`            // 实例状态
+	TransitionStatus string          `json:"transition_status"` // 过渡状态, 如 creating、starting、stopping
+	ZoneId           string          `json:"zone_id"`           // 区域
+	CreateTime       string          `json:"create_time"`       // 创建时间
+	KeypairIds       []string        `json:"keypair_ids"`       // 密钥对ID
+	Vxnets           []InstanceVxnet `json:"vxnets"`            // 私有网络
+	Tags             []Tag           `json:"tags"`              // 标签
+	Image            struct {
+		ImageId string `json:"image_id"`
+	} `json:"image"` // 镜像
+	Eip struct {
+		EipId   string `json:"eip_id"`
+		EipAddr string `json:"eip_addr"`
+	} `json:"eip"` // 弹性公网IP
+	SecurityGroup struct {
+		SecurityGroupId string `json:"security_group_id"`
+	} `json:"security_group"` // 安全组
+}
+
+// DescribeInstancesResponse 查询实例响应结果
+type DescribeInstancesResponse struct {
+	Response
+	TotalCount  int64      `json:"total_count"`
+	InstanceSet []Instance `json:"instance_set"`
+}
+
+// RunInstancesResponse 创建实例响应结果
+type RunInstancesResponse struct {
+	Response
+	Instances []string `json:"instances"`
+}
+
+// Image 镜像信息
+type Image struct {
+	ImageId       string `json:"image_id"`       // 镜像ID
+	ImageName     string `json:"image_name"`     // 镜像名称, 如 Ubuntu Server 20.04 LTS 64bit
+	Provider      string `json:"provider"`       // 来源 system、self
+	OsFamily      string `json:"os_family"`      // 操作系统, 如 ubuntu、centos
+	Platform      string `json:"platform"`       // 平台 linux、windows
+	ProcessorType string `json:"processor_type"` // 架构, 如 64bit
+	Size          int64  `json:"size"`           // 镜像大小, 单位GB
+	Status        string `json:"status"`         // 状态
+	CreateTime    string `json:"create_time"`    // 创建时间
+}
+
+// DescribeImagesResponse 查询镜像响应结果
+type DescribeImagesResponse struct {
+	Response
+	TotalCount int64   `json:"total_count"`
+	ImageSet   []Image `json:"image_set"`
+}
+
+// CaptureInstanceResponse 制作镜像响应结果
+type CaptureInstanceResponse struct {
+	Response
+	ImageId string `json:"image_id"`
+}
+
+// Router VPC 网络
+type Router struct {
+	RouterId   string `json:"router_id"`
+	RouterName string `json:"router_name"`
+	VpcNetwork string `json:"vpc_network"` // VPC 网段
+}
+
+// DescribeRoutersResponse 查询 VPC 网络响应结果
+type DescribeRoutersResponse struct {
+	Response
+	TotalCount int64    `json:"total_count"`
+	RouterSet  []Router `json:"router_set"`
+}
+
+// Vxnet 私有网络
+type Vxnet struct {
+	VxnetId     string `json:"vxnet_id"`
+	VxnetName   string `json:"vxnet_name"`
+	VpcRouterId string `json:"vpc_router_id"` // 连接的 VPC 网络
+	Router      struct {
+		IpNetwork string `json:"ip_network"` // 网段
+	} `json:"router"`
+	AvailabilityZone string   `json:"availability_zone"` // 可用区
+	InstanceIds      []string `json:"instance_ids"`      // 私有网络中的实例
+}
+
+// DescribeVxnetsResponse 查询私有网络响应结果
+type DescribeVxnetsResponse struct {
+	Response
+	TotalCount int64   `json:"total_count"`
+	VxnetSet   []Vxnet `json:"vxnet_set"`
+}
+
+// Eip 弹性公网IP
+type Eip struct {
+	EipId    string `json:"eip_id"`
+	EipAddr  string `json:"eip_addr"`
+	Status   string `json:"status"`
+	Resource struct {
+		ResourceId   string `json:"resource_id"`
+		ResourceType string `json:"resource_type"`
+	} `json:"resource"` // 绑定的资源
+}
+
+// DescribeEipsResponse 查询弹性公网IP响应结果
+type DescribeEipsResponse struct {
+	Response
+	TotalCount int64 `json:"total_count"`
+	EipSet     []Eip `json:"eip_set"`
+}
+
+// AllocateEipsResponse 申请弹性公网IP响应结果
+type AllocateEipsResponse struct {
+	Response
+	Eips []string `json:"eips"`
+}
+
+// Nic 网卡
+type Nic struct {
+	NicId      string `json:"nic_id"` // 网卡ID, 即 MAC 地址
+	VxnetId    string `json:"vxnet_id"`
+	InstanceId string `json:"instance_id"`
+	PrivateIp  string `json:"private_ip"`
+	Status     string `json:"status"`
+	Role       int    `json:"role"` // 网卡角色
+}
+
+// DescribeNicsResponse 查询网卡响应结果
+type DescribeNicsResponse struct {
+	Response
+	TotalCount int64 `json:"total_count"`
+	NicSet     []Nic `json:"nic_set"`
+}
+
+// SecurityGroup 安全组
+type SecurityGroup struct {
+	SecurityGroupId   string `json:"security_group_id"`
+	SecurityGroupName string `json:"security_group_name"`
+	Description       string `json:"description"`
+	CreateTime        string `json:"create_time"`
+}
+
+// DescribeSecurityGroupsResponse 查询安全组响应结果
+type DescribeSecurityGroupsResponse struct {
+	Response
+	TotalCount       int64           `json:"total_count"`
+	SecurityGroupSet []SecurityGroup `json:"security_group_set"`
+}
+
+// CreateSecurityGroupResponse 创建安全组响应结果
+type CreateSecurityGroupResponse struct {
+	Response
+	SecurityGroupId string `json:"security_group_id"`
+}
+
+// SecurityGroupRule 安全组规则
+type SecurityGroupRule struct {
+	SecurityGroupRuleId   string `json:"security_group_rule_id"`
+	SecurityGroupRuleName string `json:"security_group_rule_name"`
+	Protocol              string `json:"protocol"`  // tcp、udp、icmp
+	Direction             int    `json:"direction"` // 0 入站, 1 出站
+	Action                string `json:"action"`    // accept、drop
+	Priority              int    `json:"priority"`  // 优先级, 0-100, 越小越优先
+	Val1                  string `json:"val1"`      // 起始端口或 ICMP 类型
+	Val2                  string `json:"val2"`      // 结束端口或 ICMP 代码
+	Val3                  string `json:"val3"`      // 对端网段
+}
+
+// DescribeSecurityGroupRulesResponse 查询安全组规则响应结果
+type DescribeSecurityGroupRulesResponse struct {
+	Response
+	TotalCount           int64               `json:"total_count"`
+	SecurityGroupRuleSet []SecurityGroupRule `json:"security_group_rule_set"`
+}
+
+// KeyPair 密钥对
+type KeyPair struct {
+	KeypairId   string   `json:"keypair_id"`
+	KeypairName string   `json:"keypair_name"`
+	PubKey      string   `json:"pub_key"`
+	InstanceIds []string `json:"instance_ids"`
+	CreateTime  string   `json:"create_time"`
+}
+
+// DescribeKeyPairsResponse 查询密钥对响应结果
+type DescribeKeyPairsResponse struct {
+	Response
+	TotalCount int64     `json:"total_count"`
+	KeypairSet []KeyPair `json:"keypair_set"`
+}
+
+// CreateKeyPairResponse 创建密钥对响应结果
+type CreateKeyPairResponse struct {
+	Response
+	KeypairId  string `json:"keypair_id"`
+	PrivateKey string `json:"private_key"` // 仅 system 模式创建时返回
+}
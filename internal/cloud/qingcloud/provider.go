@@ -0,0 +1,39 @@
+package qingcloud
+
+import (
+	"fmt"
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/internal/cloud/qingcloud/iaas"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"os"
+)
+
+const (
+	EnvAccessKeyId     = "QINGCLOUD_ACCESS_KEY_ID"
+	EnvAccessKeySecret = "QINGCLOUD_SECRET_ACCESS_KEY"
+)
+
+func init() {
+	cloud.Register(cloud.QingCloud, NewProvider)
+}
+
+// NewProvider 根据配置构建青云实现, 密钥为空时读取环境变量
+func NewProvider(c *config.Config) (cloud.Provider, error) {
+	if c.QingCloud == nil {
+		return nil, fmt.Errorf("%w: missing qingcloud section", cloud.ErrNotConfigured)
+	}
+	if c.QingCloud.Zone == "" {
+		return nil, fmt.Errorf("%w: qingcloud.zone is required", cloud.ErrNotConfigured)
+	}
+	accessKeyId, secretAccessKey := c.QingCloud.AccessKeyId, c.QingCloud.SecretAccessKey
+	if accessKeyId == "" || secretAccessKey == "" {
+		accessKeyId = os.Getenv(EnvAccessKeyId)
+		secretAccessKey = os.Getenv(EnvAccessKeySecret)
+	}
+	if accessKeyId == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("%w: qingcloud access key is required", cloud.ErrNotConfigured)
+	}
+	client := iaas.NewClient(accessKeyId, secretAccessKey, c.QingCloud.Zone, c.QingCloud.Endpoint)
+	server := NewInstanceServer(client)
+	return server, nil
+}
@@ -0,0 +1,403 @@
+package qingcloud
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/qingcloud/iaas"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	pageLimit    = 100              // 分页查询每页数量
+	waitInterval = 5 * time.Second  // 轮询实例状态间隔
+	waitTimeout  = 10 * time.Minute // 等待实例状态超时时间
+)
+
+// softStopTimeout 软关机超过该时间仍未关机时强制关机
+var softStopTimeout = 2 * time.Minute
+
+var _ cloud.Provider = (*InstanceServer)(nil)
+
+type InstanceServer struct {
+	client   *iaas.Client   // 青云IaaS客户端
+	keyStore cloud.KeyStore // 私钥存储
+}
+
+// NewInstanceServer 实例化, 私钥默认保存在 ~/.k8s-aim/keys
+func NewInstanceServer(client *iaas.Client) *InstanceServer {
+	return &InstanceServer{client: client, keyStore: cloud.NewFileKeyStore("")}
+}
+
+// SetKeyStore 设置私钥存储
+func (i *InstanceServer) SetKeyStore(store cloud.KeyStore) {
+	i.keyStore = store
+}
+
+// GetImage 获取镜像
+func (i *InstanceServer) GetImage(query *cloud.ImageQuery) (*cloud.ImageInfo, error) {
+	params := iaas.Params{"status.1": iaas.ImageStatusAvailable}
+	params.Set("provider", imageProviders[query.ImageType])
+	var images []cloud.ImageInfo
+	err := i.pages(params, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeImagesResponse
+		if err := i.client.Do(context.Background(), "DescribeImages", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, image := range resp.ImageSet {
+			images = append(images, toImageInfo(&image))
+		}
+		return len(resp.ImageSet), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloud.SelectImage(images, query.NameRegex, query.OS)
+}
+
+// CreateImage 使用已准备好的节点制作自定义镜像, 返回镜像ID
+// 青云只能对已关机的实例制作镜像, 且不支持镜像描述
+func (i *InstanceServer) CreateImage(instanceId, imageName, description string) (string, error) {
+	params := iaas.Params{"instance": instanceId}
+	params.Set("image_name", imageName)
+	var resp iaas.CaptureInstanceResponse
+	if err := i.client.Do(context.Background(), "CaptureInstance", params, &resp); err != nil {
+		return "", err
+	}
+	return resp.ImageId, nil
+}
+
+// CreateInstance 创建实例后立即绑定标签, 等待实例运行后申请并绑定公网IP
+// 青云创建时只能指定一个私有网络、一个安全组和一个密钥对, 其余密钥对在实例运行后绑定
+func (i *InstanceServer) CreateInstance(spec *cloud.InstanceSpec) ([]cloud.InstanceInfo, error) {
+	params, err := runInstancesParams(spec)
+	if err != nil {
+		return nil, err
+	}
+	var resp iaas.RunInstancesResponse
+	if err := i.client.Do(context.Background(), "RunInstances", params, &resp); err != nil {
+		return nil, err
+	}
+	ids := resp.Instances
+	// 先绑定标签再等待运行, 等待超时或创建失败遗留的实例仍可按标签找回或回收; 创建中的实例绑定失败时等待结束后重试
+	tagErr := i.attachTags(ids, spec.Tags)
+	waitErr := i.waitInstances(ids, cloud.InstanceStateRunning)
+	if tagErr != nil {
+		tagErr = i.attachTags(ids, spec.Tags)
+	}
+	if waitErr != nil && tagErr != nil {
+		return nil, fmt.Errorf("%w, attach tags to instances %v, %s", waitErr, ids, tagErr)
+	}
+	if waitErr != nil {
+		return nil, waitErr
+	}
+	if tagErr != nil {
+		return nil, tagErr
+	}
+	if len(spec.KeyPairIds) > 1 {
+		if err := i.BindKeyPairs(ids, spec.KeyPairIds[1:]...); err != nil {
+			return nil, err
+		}
+	}
+	if spec.InternetBandwidth > 0 {
+		for _, id := range ids {
+			address, err := i.AllocateAddress(spec.InternetBandwidth)
+			if err != nil {
+				return nil, err
+			}
+			if err := i.AssociateAddress(address.AddressId, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return i.describeInstances(context.Background(), iaas.Params{}.SetList("instances", ids))
+}
+
+// DescribeInstances 查询实例, 名称为模糊查询, 结果再按名称、标签精确过滤
+func (i *InstanceServer) DescribeInstances(filter *cloud.InstanceFilter) ([]cloud.InstanceInfo, error) {
+	params := iaas.Params{}
+	if filter == nil {
+		return i.describeInstances(context.Background(), params)
+	}
+	params.SetList("instances", filter.InstanceIds)
+	params.Set("zone", filter.Zone)
+	params.Set("search_word", filter.Name)
+	if len(filter.Tags) > 0 {
+		tagIds, err := i.tagIds(filter.Tags, false)
+		if err != nil {
+			return nil, err
+		}
+		// 标签不存在时不可能有匹配的实例
+		if len(tagIds) < len(filter.Tags) {
+			return nil, nil
+		}
+		params.SetList("tags", tagIds)
+	}
+	instances, err := i.describeInstances(context.Background(), params)
+	if err != nil {
+		return nil, err
+	}
+	var infos []cloud.InstanceInfo
+	for _, instance := range instances {
+		if matchInstance(&instance, filter) {
+			infos = append(infos, instance)
+		}
+	}
+	return infos, nil
+}
+
+// StartInstance 启动实例
+func (i *InstanceServer) StartInstance(instanceIds ...string) error {
+	return i.client.Do(context.Background(), "StartInstances", iaas.Params{}.SetList("instances", instanceIds), nil)
+}
+
+// StopInstance 停止实例, 优先软关机, 超过 softStopTimeout 仍未关机时强制关机
+func (i *InstanceServer) StopInstance(instanceIds ...string) error {
+	if err := i.stopInstances(instanceIds, false); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), softStopTimeout)
+	defer cancel()
+	if err := i.waitInstancesWithContext(ctx, instanceIds, cloud.InstanceStateStopped); err == nil {
+		return nil
+	}
+	return i.stopInstances(instanceIds, true)
+}
+
+// RestartInstance 重启实例
+func (i *InstanceServer) RestartInstance(instanceIds ...string) error {
+	return i.client.Do(context.Background(), "RestartInstances", iaas.Params{}.SetList("instances", instanceIds), nil)
+}
+
+// TerminateInstance 删除实例
+func (i *InstanceServer) TerminateInstance(instanceIds ...string) error {
+	return i.client.Do(context.Background(), "TerminateInstances", iaas.Params{}.SetList("instances", instanceIds), nil)
+}
+
+// DescribeInterruption 青云没有竞价实例
+func (i *InstanceServer) DescribeInterruption(instanceId string) (*cloud.Interruption, error) {
+	return nil, cloud.ErrNotSupported
+}
+
+// describeInstances 分页查询实例, verbose=1 时返回网络、标签等详细信息
+func (i *InstanceServer) describeInstances(ctx context.Context, params iaas.Params) ([]cloud.InstanceInfo, error) {
+	params["verbose"] = "1"
+	var infos []cloud.InstanceInfo
+	err := i.pages(params, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeInstancesResponse
+		if err := i.client.Do(ctx, "DescribeInstances", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, instance := range resp.InstanceSet {
+			infos = append(infos, toInstanceInfo(&instance))
+		}
+		return len(resp.InstanceSet), nil
+	})
+	return infos, err
+}
+
+// waitInstances 轮询直到实例全部达到指定状态
+func (i *InstanceServer) waitInstances(ids []string, state cloud.InstanceState) error {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	return i.waitInstancesWithContext(ctx, ids, state)
+}
+
+// waitInstancesWithContext 轮询直到实例均处于 state 或 ctx 结束
+func (i *InstanceServer) waitInstancesWithContext(ctx context.Context, ids []string, state cloud.InstanceState) error {
+	for {
+		instances, err := i.describeInstances(ctx, iaas.Params{}.SetList("instances", ids))
+		if err != nil {
+			return err
+		}
+		ready := len(instances) == len(ids)
+		for _, instance := range instances {
+			if instance.State != state {
+				ready = false
+			}
+		}
+		if ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait instances %v to be %s, %s", ids, state, ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// pages 按 offset、limit 分页查询, fetch 返回本页数量
+func (i *InstanceServer) pages(params iaas.Params, fetch func(params iaas.Params) (int, error)) error {
+	for offset := 0; ; offset += pageLimit {
+		p := iaas.Params{"offset": strconv.Itoa(offset), "limit": strconv.Itoa(pageLimit)}
+		for k, v := range params {
+			p[k] = v
+		}
+		n, err := fetch(p)
+		if err != nil {
+			return err
+		}
+		if n < pageLimit {
+			return nil
+		}
+	}
+}
+
+// runInstancesParams 创建实例参数, 青云实例只能通过密钥对登录
+func runInstancesParams(spec *cloud.InstanceSpec) (iaas.Params, error) {
+	if len(spec.KeyPairIds) == 0 {
+		return nil, errors.New("qingcloud instance requires at least one key pair")
+	}
+	if len(spec.DataDisks) > 0 {
+		return nil, errors.New("qingcloud instance does not support data disks at creation")
+	}
+	if spec.Spot != nil {
+		return nil, fmt.Errorf("qingcloud spot instance, %w", cloud.ErrNotSupported)
+	}
+	count := spec.Count
+	if count <= 0 {
+		count = 1
+	}
+	params := iaas.Params{
+		"image_id":      spec.ImageId,
+		"instance_type": spec.InstanceType,
+		"count":         strconv.FormatInt(count, 10),
+		"login_mode":    "keypair",
+		"login_keypair": spec.KeyPairIds[0],
+	}
+	params.Set("zone", spec.Zone)
+	params.Set("instance_name", spec.Name)
+	params.Set("hostname", spec.HostName)
+	params.Set("vxnets.1", spec.SubnetId)
+	if len(spec.SecurityGroupIds) > 0 {
+		params.Set("security_group", spec.SecurityGroupIds[0])
+	}
+	if spec.SystemDisk != nil {
+		params.SetInt("os_disk_size", spec.SystemDisk.SizeGB)
+	}
+	if spec.UserData != "" {
+		params.Set("need_userdata", "1")
+		params.Set("userdata_type", "plain")
+		params.Set("userdata_value", base64.StdEncoding.EncodeToString([]byte(spec.UserData)))
+	}
+	return params, nil
+}
+
+// instanceStates 实例状态映射
+var instanceStates = map[string]cloud.InstanceState{
+	iaas.InstanceStatusPending:    cloud.InstanceStatePending,
+	iaas.InstanceStatusRunning:    cloud.InstanceStateRunning,
+	iaas.InstanceStatusStopped:    cloud.InstanceStateStopped,
+	iaas.InstanceStatusSuspended:  cloud.InstanceStateStopped,
+	iaas.InstanceStatusTerminated: cloud.InstanceStateTerminated,
+	iaas.InstanceStatusCeased:     cloud.InstanceStateTerminated,
+}
+
+// transitionStates 实例过渡状态映射, 优先于实例状态
+var transitionStates = map[string]cloud.InstanceState{
+	"creating":    cloud.InstanceStatePending,
+	"starting":    cloud.InstanceStateStarting,
+	"stopping":    cloud.InstanceStateStopping,
+	"restarting":  cloud.InstanceStateRebooting,
+	"terminating": cloud.InstanceStateTerminating,
+}
+
+// imageProviders 镜像类型映射, 青云没有共享镜像类型
+var imageProviders = map[cloud.ImageType]string{
+	cloud.ImageTypePublic:  iaas.ImageProviderSystem,
+	cloud.ImageTypePrivate: iaas.ImageProviderSelf,
+}
+
+// toInstanceInfo 实例信息转换
+func toInstanceInfo(instance *iaas.Instance) cloud.InstanceInfo {
+	info := cloud.InstanceInfo{
+		InstanceId:   instance.InstanceId,
+		Name:         instance.InstanceName,
+		InstanceType: instance.InstanceType,
+		Zone:         instance.ZoneId,
+		ImageId:      instance.Image.ImageId,
+		State:        cloud.InstanceStateUnknown,
+		KeyPairIds:   instance.KeypairIds,
+		Tags:         fromTags(instance.Tags),
+		CreatedTime:  parseTime(instance.CreateTime),
+	}
+	if state, ok := instanceStates[instance.Status]; ok {
+		info.State = state
+	}
+	if state, ok := transitionStates[instance.TransitionStatus]; ok {
+		info.State = state
+	}
+	for _, vxnet := range instance.Vxnets {
+		if vxnet.PrivateIp != "" {
+			info.PrivateIps = append(info.PrivateIps, vxnet.PrivateIp)
+		}
+	}
+	if instance.Eip.EipAddr != "" {
+		info.PublicIps = []string{instance.Eip.EipAddr}
+	}
+	if instance.SecurityGroup.SecurityGroupId != "" {
+		info.SecurityGroupIds = []string{instance.SecurityGroup.SecurityGroupId}
+	}
+	return info
+}
+
+// matchInstance 实例是否满足过滤条件
+func matchInstance(info *cloud.InstanceInfo, filter *cloud.InstanceFilter) bool {
+	if filter.Name != "" && info.Name != filter.Name {
+		return false
+	}
+	if filter.Zone != "" && info.Zone != filter.Zone {
+		return false
+	}
+	for k, v := range filter.Tags {
+		if value, ok := info.Tags[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// toImageInfo 镜像信息转换, 镜像名称不以操作系统开头时补充操作系统以便匹配版本约束
+func toImageInfo(image *iaas.Image) cloud.ImageInfo {
+	info := cloud.ImageInfo{
+		ImageId:      image.ImageId,
+		Name:         image.ImageName,
+		OsName:       image.ImageName,
+		Platform:     image.Platform,
+		Architecture: image.ProcessorType,
+		SizeGB:       image.Size,
+		CreatedTime:  parseTime(image.CreateTime),
+	}
+	if image.OsFamily != "" && !strings.HasPrefix(strings.ToLower(image.ImageName), strings.ToLower(image.OsFamily)) {
+		info.OsName = image.OsFamily + " " + image.ImageName
+	}
+	for imageType, provider := range imageProviders {
+		if provider == image.Provider {
+			info.ImageType = imageType
+		}
+	}
+	return info
+}
+
+// parseTime 解析时间, 失败时返回零值
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// stopInstances 停止实例, force 为 true 时强制关机
+func (i *InstanceServer) stopInstances(instanceIds []string, force bool) error {
+	params := iaas.Params{"force": "0"}.SetList("instances", instanceIds)
+	if force {
+		params["force"] = "1"
+	}
+	return i.client.Do(context.Background(), "StopInstances", params, nil)
+}
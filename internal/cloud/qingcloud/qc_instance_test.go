@@ -0,0 +1,188 @@
+package qingcloud
+
+import (
+	"encoding/json"
+	"github.com/eadydb/k8s-aim/internal/cloud/qingcloud/iaas"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// iaasServer 按 action 分发的青云模拟服务, 记录收到的请求
+type iaasServer struct {
+	mu       sync.Mutex
+	handlers map[string]func(query url.Values) interface{}
+	queries  []url.Values
+}
+
+func (s *iaasServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	s.mu.Lock()
+	s.queries = append(s.queries, query)
+	handler, ok := s.handlers[query.Get("action")]
+	s.mu.Unlock()
+	var resp interface{} = map[string]interface{}{"ret_code": 0}
+	if ok {
+		resp = handler(query)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handle 注册 action 的处理函数
+func (s *iaasServer) handle(action string, handler func(query url.Values) interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[action] = handler
+}
+
+// actions 按顺序返回收到的 action
+func (s *iaasServer) actions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	actions := make([]string, 0, len(s.queries))
+	for _, query := range s.queries {
+		actions = append(actions, query.Get("action"))
+	}
+	return actions
+}
+
+// sent 返回 action 的全部请求参数
+func (s *iaasServer) sent(action string) []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var queries []url.Values
+	for _, query := range s.queries {
+		if query.Get("action") == action {
+			queries = append(queries, query)
+		}
+	}
+	return queries
+}
+
+// newTestServer 启动模拟服务, 返回连接该服务的 InstanceServer
+func newTestServer(t *testing.T) (*InstanceServer, *iaasServer) {
+	t.Helper()
+	s := &iaasServer{handlers: make(map[string]func(query url.Values) interface{})}
+	hs := httptest.NewServer(s)
+	t.Cleanup(hs.Close)
+	client := iaas.NewClient("key", "secret", "pek3", hs.URL+"/iaas/")
+	client.RetryDelay = time.Millisecond
+	s.handle("DescribeTags", func(url.Values) interface{} {
+		return map[string]interface{}{"ret_code": 0, "total_count": 0}
+	})
+	s.handle("CreateTag", func(url.Values) interface{} {
+		return map[string]interface{}{"ret_code": 0, "tag_id": "tag-1"}
+	})
+	return NewInstanceServer(client), s
+}
+
+// instancesWithStatus 返回指定状态实例的查询结果
+func instancesWithStatus(status string) func(url.Values) interface{} {
+	return func(url.Values) interface{} {
+		return map[string]interface{}{
+			"ret_code":     0,
+			"total_count":  1,
+			"instance_set": []map[string]interface{}{{"instance_id": "i-1", "status": status}},
+		}
+	}
+}
+
+func newSpec() *cloud.InstanceSpec {
+	return &cloud.InstanceSpec{
+		Name:         "node",
+		ImageId:      "img-1",
+		InstanceType: "s1.small.r1",
+		KeyPairIds:   []string{"kp-1"},
+		Tags:         map[string]string{"k8s-aim": "true"},
+	}
+}
+
+func TestCreateInstanceAttachesTagsBeforeWaiting(t *testing.T) {
+	i, s := newTestServer(t)
+	s.handle("RunInstances", func(url.Values) interface{} {
+		return map[string]interface{}{"ret_code": 0, "instances": []string{"i-1"}}
+	})
+	s.handle("DescribeInstances", instancesWithStatus(iaas.InstanceStatusRunning))
+	if _, err := i.CreateInstance(newSpec()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"RunInstances", "DescribeTags", "CreateTag", "AttachTags", "DescribeInstances", "DescribeInstances"}
+	if got := s.actions(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("actions %v, want %v", got, want)
+	}
+	attach := s.sent("AttachTags")[0]
+	if attach.Get("resource_tag_pairs.1.resource_id") != "i-1" || attach.Get("resource_tag_pairs.1.tag_id") != "tag-1" {
+		t.Fatalf("AttachTags params %v", attach)
+	}
+}
+
+func TestCreateInstanceAttachesTagsWhenWaitFails(t *testing.T) {
+	i, s := newTestServer(t)
+	s.handle("RunInstances", func(url.Values) interface{} {
+		return map[string]interface{}{"ret_code": 0, "instances": []string{"i-1"}}
+	})
+	s.handle("DescribeInstances", func(url.Values) interface{} {
+		return map[string]interface{}{"ret_code": 2100, "message": "resource not found"}
+	})
+	if _, err := i.CreateInstance(newSpec()); err == nil {
+		t.Fatal("expected wait error")
+	}
+	if n := len(s.sent("AttachTags")); n != 1 {
+		t.Fatalf("AttachTags sent %d times, want 1", n)
+	}
+}
+
+func TestCreateInstanceRetriesTagsAfterWaiting(t *testing.T) {
+	i, s := newTestServer(t)
+	s.handle("RunInstances", func(url.Values) interface{} {
+		return map[string]interface{}{"ret_code": 0, "instances": []string{"i-1"}}
+	})
+	s.handle("DescribeInstances", instancesWithStatus(iaas.InstanceStatusRunning))
+	var attempts int
+	s.handle("AttachTags", func(url.Values) interface{} {
+		attempts++
+		if attempts == 1 {
+			return map[string]interface{}{"ret_code": 1300, "message": "instance is creating"}
+		}
+		return map[string]interface{}{"ret_code": 0}
+	})
+	if _, err := i.CreateInstance(newSpec()); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Fatalf("AttachTags sent %d times, want 2", attempts)
+	}
+}
+
+func TestStopInstanceWithoutForce(t *testing.T) {
+	i, s := newTestServer(t)
+	s.handle("DescribeInstances", instancesWithStatus(iaas.InstanceStatusStopped))
+	if err := i.StopInstance("i-1"); err != nil {
+		t.Fatal(err)
+	}
+	stops := s.sent("StopInstances")
+	if len(stops) != 1 || stops[0].Get("force") != "0" {
+		t.Fatalf("StopInstances %v, want a single soft stop", stops)
+	}
+}
+
+func TestStopInstanceForcesAfterTimeout(t *testing.T) {
+	timeout := softStopTimeout
+	softStopTimeout = 10 * time.Millisecond
+	defer func() { softStopTimeout = timeout }()
+
+	i, s := newTestServer(t)
+	s.handle("DescribeInstances", instancesWithStatus(iaas.InstanceStatusRunning))
+	if err := i.StopInstance("i-1"); err != nil {
+		t.Fatal(err)
+	}
+	stops := s.sent("StopInstances")
+	if len(stops) != 2 || stops[0].Get("force") != "0" || stops[1].Get("force") != "1" {
+		t.Fatalf("StopInstances %v, want a soft stop followed by a forced stop", stops)
+	}
+}
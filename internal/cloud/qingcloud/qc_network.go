@@ -0,0 +1,208 @@
+package qingcloud
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/qingcloud/iaas"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"net"
+	"time"
+)
+
+// 基础网络, 不属于任何 VPC, 由青云分配网段
+const baseVxnet = "vxnet-0"
+
+// reservedIps 私有网络中保留的地址: 网络地址、网关、广播地址及 DHCP 使用的地址
+const reservedIps = 4
+
+// DescribeVpcs 查询 VPC 网络, 青云 VPC 网络即 VPC 类型的路由器
+func (i *InstanceServer) DescribeVpcs() ([]cloud.Vpc, error) {
+	var vpcs []cloud.Vpc
+	err := i.pages(iaas.Params{}, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeRoutersResponse
+		if err := i.client.Do(context.Background(), "DescribeRouters", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, r := range resp.RouterSet {
+			vpcs = append(vpcs, cloud.Vpc{VpcId: r.RouterId, Name: r.RouterName, CidrBlock: r.VpcNetwork})
+		}
+		return len(resp.RouterSet), nil
+	})
+	return vpcs, err
+}
+
+// DescribeSubnets 查询私有网络, 可用IP数根据网段大小及已有实例数估算
+func (i *InstanceServer) DescribeSubnets(vpcId, zone string) ([]cloud.Subnet, error) {
+	params := iaas.Params{"verbose": "1"}
+	params.Set("zone", zone)
+	var subnets []cloud.Subnet
+	err := i.pages(params, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeVxnetsResponse
+		if err := i.client.Do(context.Background(), "DescribeVxnets", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, v := range resp.VxnetSet {
+			if vpcId != "" && v.VpcRouterId != vpcId {
+				continue
+			}
+			subnet := cloud.Subnet{
+				SubnetId:         v.VxnetId,
+				VpcId:            v.VpcRouterId,
+				Name:             v.VxnetName,
+				CidrBlock:        v.Router.IpNetwork,
+				Zone:             v.AvailabilityZone,
+				AvailableIpCount: availableIps(v.Router.IpNetwork, len(v.InstanceIds)),
+				IsDefault:        v.VxnetId == baseVxnet,
+			}
+			if subnet.Zone == "" {
+				subnet.Zone = i.zone(zone)
+			}
+			subnets = append(subnets, subnet)
+		}
+		return len(resp.VxnetSet), nil
+	})
+	return subnets, err
+}
+
+// DescribeAddresses 查询弹性公网IP
+func (i *InstanceServer) DescribeAddresses(addressIds ...string) ([]cloud.Address, error) {
+	params := iaas.Params{}.SetList("eips", addressIds)
+	var addresses []cloud.Address
+	err := i.pages(params, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeEipsResponse
+		if err := i.client.Do(context.Background(), "DescribeEips", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, e := range resp.EipSet {
+			address := cloud.Address{AddressId: e.EipId, Ip: e.EipAddr, Status: e.Status}
+			if e.Resource.ResourceType == "instance" {
+				address.InstanceId = e.Resource.ResourceId
+			}
+			addresses = append(addresses, address)
+		}
+		return len(resp.EipSet), nil
+	})
+	return addresses, err
+}
+
+// AllocateAddress 申请按带宽计费的弹性公网IP, 等待分配完成
+func (i *InstanceServer) AllocateAddress(bandwidth int64) (*cloud.Address, error) {
+	params := iaas.Params{"count": "1", "billing_mode": "bandwidth"}
+	params.SetInt("bandwidth", bandwidth)
+	var resp iaas.AllocateEipsResponse
+	if err := i.client.Do(context.Background(), "AllocateEips", params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Eips) == 0 {
+		return nil, fmt.Errorf("allocate eips returned no eip")
+	}
+	return i.waitAddress(resp.Eips[0], iaas.EipStatusAvailable)
+}
+
+// AssociateAddress 绑定弹性公网IP到实例, 等待任务完成
+func (i *InstanceServer) AssociateAddress(addressId, instanceId string) error {
+	var resp iaas.Response
+	if err := i.client.Do(context.Background(), "AssociateEip", iaas.Params{"eip": addressId, "instance": instanceId}, &resp); err != nil {
+		return err
+	}
+	return i.waitJob(resp.JobId)
+}
+
+// DisassociateAddress 解绑弹性公网IP, 等待任务完成
+func (i *InstanceServer) DisassociateAddress(addressId string) error {
+	var resp iaas.Response
+	if err := i.client.Do(context.Background(), "DissociateEips", iaas.Params{"eips.1": addressId}, &resp); err != nil {
+		return err
+	}
+	return i.waitJob(resp.JobId)
+}
+
+// ReleaseAddress 释放弹性公网IP
+func (i *InstanceServer) ReleaseAddress(addressId string) error {
+	return i.client.Do(context.Background(), "ReleaseEips", iaas.Params{"eips.1": addressId}, nil)
+}
+
+// DescribeNetworkInterfaces 查询实例绑定的网卡, 青云网卡ID即 MAC 地址
+func (i *InstanceServer) DescribeNetworkInterfaces(instanceId string) ([]cloud.NetworkInterface, error) {
+	var nics []cloud.NetworkInterface
+	err := i.pages(iaas.Params{"instances.1": instanceId}, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeNicsResponse
+		if err := i.client.Do(context.Background(), "DescribeNics", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, n := range resp.NicSet {
+			nic := cloud.NetworkInterface{
+				NetworkInterfaceId: n.NicId,
+				SubnetId:           n.VxnetId,
+				InstanceId:         n.InstanceId,
+				MacAddress:         n.NicId,
+				Primary:            n.Role == iaas.NicRolePrimary,
+				State:              n.Status,
+			}
+			if n.PrivateIp != "" {
+				nic.PrivateIps = []string{n.PrivateIp}
+			}
+			nics = append(nics, nic)
+		}
+		return len(resp.NicSet), nil
+	})
+	return nics, err
+}
+
+// PickSubnet 在可用区内选择可用IP数不少于 need 的私有网络
+func (i *InstanceServer) PickSubnet(vpcId, zone string, need int64) (*cloud.Subnet, error) {
+	subnets, err := i.DescribeSubnets(vpcId, zone)
+	if err != nil {
+		return nil, err
+	}
+	return cloud.PickSubnet(subnets, zone, need)
+}
+
+// waitAddress 轮询直到弹性公网IP达到指定状态
+func (i *InstanceServer) waitAddress(addressId, status string) (*cloud.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	for {
+		addresses, err := i.DescribeAddresses(addressId)
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses) > 0 && addresses[0].Status == status {
+			return &addresses[0], nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait address %s to be %s, %s", addressId, status, ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// zone 查询区域, 为空时使用客户端区域
+func (i *InstanceServer) zone(zone string) string {
+	if zone != "" {
+		return zone
+	}
+	return i.client.Zone
+}
+
+// waitJob 等待异步任务完成
+func (i *InstanceServer) waitJob(jobId string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	return i.client.WaitJob(ctx, jobId, waitInterval)
+}
+
+// availableIps 估算网段中的可用IP数, 网段为空时(如基础网络)视为不限
+func availableIps(cidr string, used int) int64 {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return 1 << 16
+	}
+	ones, bits := ipNet.Mask.Size()
+	n := int64(1)<<uint(bits-ones) - reservedIps - int64(used)
+	if n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,302 @@
+package qingcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/qingcloud/iaas"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"strconv"
+	"strings"
+)
+
+// rulePriority 新增规则的优先级, 青云取值 0-100, 越小越优先
+const rulePriority = "50"
+
+// CreateSecurityGroup 创建安全组并添加规则, 返回安全组ID
+func (i *InstanceServer) CreateSecurityGroup(name, description string, rules []cloud.SecurityRule) (string, error) {
+	var resp iaas.CreateSecurityGroupResponse
+	if err := i.client.Do(context.Background(), "CreateSecurityGroup", iaas.Params{"security_group_name": name}, &resp); err != nil {
+		return "", err
+	}
+	if description != "" {
+		params := iaas.Params{"security_group": resp.SecurityGroupId, "description": description}
+		if err := i.client.Do(context.Background(), "ModifySecurityGroupAttributes", params, nil); err != nil {
+			return resp.SecurityGroupId, err
+		}
+	}
+	if len(rules) > 0 {
+		if err := i.AddRules(resp.SecurityGroupId, rules); err != nil {
+			return resp.SecurityGroupId, err
+		}
+	}
+	return resp.SecurityGroupId, nil
+}
+
+// DescribeSecurityGroups 查询安全组
+func (i *InstanceServer) DescribeSecurityGroups(groupIds ...string) ([]cloud.SecurityGroupInfo, error) {
+	params := iaas.Params{}.SetList("security_groups", groupIds)
+	var groups []cloud.SecurityGroupInfo
+	err := i.pages(params, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeSecurityGroupsResponse
+		if err := i.client.Do(context.Background(), "DescribeSecurityGroups", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, g := range resp.SecurityGroupSet {
+			groups = append(groups, cloud.SecurityGroupInfo{
+				SecurityGroupId: g.SecurityGroupId,
+				Name:            g.SecurityGroupName,
+				Description:     g.Description,
+				CreatedTime:     parseTime(g.CreateTime),
+			})
+		}
+		return len(resp.SecurityGroupSet), nil
+	})
+	return groups, err
+}
+
+// DeleteSecurityGroup 删除安全组
+func (i *InstanceServer) DeleteSecurityGroup(groupId string) error {
+	return i.client.Do(context.Background(), "DeleteSecurityGroups", iaas.Params{"security_groups.1": groupId}, nil)
+}
+
+// DescribeRules 查询安全组规则, 只返回允许访问的规则
+// 添加时协议为 ALL 的规则在青云拆分为 TCP、UDP、ICMP 三条, 查询结果同样为三条
+func (i *InstanceServer) DescribeRules(groupId string) ([]cloud.SecurityRule, error) {
+	list, err := i.describeRules(groupId)
+	if err != nil {
+		return nil, err
+	}
+	var rules []cloud.SecurityRule
+	for _, r := range list {
+		if r.Action != "" && r.Action != "accept" {
+			continue
+		}
+		rule := cloud.SecurityRule{
+			Direction:   cloud.RuleIngress,
+			Protocol:    strings.ToUpper(r.Protocol),
+			CidrBlock:   r.Val3,
+			Description: r.SecurityGroupRuleName,
+		}
+		if r.Direction == iaas.DirectionEgress {
+			rule.Direction = cloud.RuleEgress
+		}
+		if rule.Protocol != cloud.ProtocolICMP && r.Val1 != "" {
+			rule.Port = r.Val1
+			if r.Val2 != "" && r.Val2 != r.Val1 {
+				rule.Port = r.Val1 + "-" + r.Val2
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// AddRules 添加安全组规则, 青云需要应用安全组后规则才生效, 等待应用完成
+func (i *InstanceServer) AddRules(groupId string, rules []cloud.SecurityRule) error {
+	params := iaas.Params{"security_group": groupId}
+	n := 0
+	for _, rule := range rules {
+		for _, protocol := range protocols(rule.Protocol) {
+			n++
+			prefix := "rules." + strconv.Itoa(n) + "."
+			params[prefix+"protocol"] = protocol
+			params[prefix+"priority"] = rulePriority
+			params[prefix+"action"] = "accept"
+			params[prefix+"direction"] = strconv.Itoa(direction(rule.Direction))
+			from, to := portRange(protocol, rule.Port)
+			params.Set(prefix+"val1", from)
+			params.Set(prefix+"val2", to)
+			params.Set(prefix+"val3", rule.CidrBlock)
+			params.Set(prefix+"security_group_rule_name", rule.Description)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	if err := i.client.Do(context.Background(), "AddSecurityGroupRules", params, nil); err != nil {
+		return err
+	}
+	return i.applySecurityGroup(groupId, nil)
+}
+
+// RemoveRules 删除安全组规则, 按协议、方向、端口、网段匹配已有规则
+func (i *InstanceServer) RemoveRules(groupId string, rules []cloud.SecurityRule) error {
+	list, err := i.describeRules(groupId)
+	if err != nil {
+		return err
+	}
+	var ruleIds []string
+	for _, rule := range rules {
+		for _, protocol := range protocols(rule.Protocol) {
+			from, to := portRange(protocol, rule.Port)
+			for _, r := range list {
+				if r.Protocol == protocol && r.Direction == direction(rule.Direction) &&
+					r.Val1 == from && r.Val2 == to && r.Val3 == rule.CidrBlock {
+					ruleIds = append(ruleIds, r.SecurityGroupRuleId)
+				}
+			}
+		}
+	}
+	if len(ruleIds) == 0 {
+		return nil
+	}
+	params := iaas.Params{}.SetList("security_group_rules", ruleIds)
+	if err := i.client.Do(context.Background(), "DeleteSecurityGroupRules", params, nil); err != nil {
+		return err
+	}
+	return i.applySecurityGroup(groupId, nil)
+}
+
+// Bind 实例加入安全组, 青云实例只能属于一个安全组, 加入后自动离开原安全组
+func (i *InstanceServer) Bind(instanceIds []string, groupIds ...string) error {
+	if len(groupIds) != 1 {
+		return fmt.Errorf("qingcloud instance belongs to exactly one security group, got %d", len(groupIds))
+	}
+	return i.applySecurityGroup(groupIds[0], instanceIds)
+}
+
+// UnBind 青云实例必须属于一个安全组, 不支持解绑, 请使用 Bind 加入其他安全组
+func (i *InstanceServer) UnBind(instanceIds []string, groupIds ...string) error {
+	return errors.New("qingcloud instance must belong to a security group, bind another group instead")
+}
+
+// DescribeKeyPairs 查询密钥对, keyIds 为空时查询全部
+func (i *InstanceServer) DescribeKeyPairs(keyIds ...string) ([]cloud.KeyPair, error) {
+	params := iaas.Params{}.SetList("keypairs", keyIds)
+	var keyPairs []cloud.KeyPair
+	err := i.pages(params, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeKeyPairsResponse
+		if err := i.client.Do(context.Background(), "DescribeKeyPairs", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, k := range resp.KeypairSet {
+			keyPairs = append(keyPairs, cloud.KeyPair{
+				KeyId:       k.KeypairId,
+				Name:        k.KeypairName,
+				PublicKey:   k.PubKey,
+				InstanceIds: k.InstanceIds,
+				CreatedTime: parseTime(k.CreateTime),
+			})
+		}
+		return len(resp.KeypairSet), nil
+	})
+	return keyPairs, err
+}
+
+// CreateKeyPair 由青云生成密钥对, 私钥以密钥对ID为名称保存到 KeyStore
+func (i *InstanceServer) CreateKeyPair(name string) (*cloud.KeyPair, error) {
+	params := iaas.Params{"keypair_name": name, "mode": "system"}
+	var resp iaas.CreateKeyPairResponse
+	if err := i.client.Do(context.Background(), "CreateKeyPair", params, &resp); err != nil {
+		return nil, err
+	}
+	keyPair := &cloud.KeyPair{KeyId: resp.KeypairId, Name: name, PrivateKey: resp.PrivateKey}
+	if err := i.keyStore.Save(keyPair.KeyId, keyPair.PrivateKey); err != nil {
+		return keyPair, fmt.Errorf("save private key of %s, %s", keyPair.KeyId, err)
+	}
+	return keyPair, nil
+}
+
+// ImportKeyPair 导入已有公钥, privateKey 不为空时一并保存到 KeyStore
+func (i *InstanceServer) ImportKeyPair(name, publicKey, privateKey string) (*cloud.KeyPair, error) {
+	params := iaas.Params{"keypair_name": name, "mode": "user", "public_key": publicKey}
+	var resp iaas.CreateKeyPairResponse
+	if err := i.client.Do(context.Background(), "CreateKeyPair", params, &resp); err != nil {
+		return nil, err
+	}
+	keyPair := &cloud.KeyPair{KeyId: resp.KeypairId, Name: name, PublicKey: publicKey}
+	if privateKey != "" {
+		if err := i.keyStore.Save(keyPair.KeyId, privateKey); err != nil {
+			return keyPair, fmt.Errorf("save private key of %s, %s", keyPair.KeyId, err)
+		}
+	}
+	return keyPair, nil
+}
+
+// BindKeyPairs 绑定密钥对, 等待任务完成
+func (i *InstanceServer) BindKeyPairs(instanceIds []string, keyIds ...string) error {
+	return i.keyPairJob("AttachKeyPairs", instanceIds, keyIds)
+}
+
+// UnBindKeyPairs 解绑密钥对, 等待任务完成
+func (i *InstanceServer) UnBindKeyPairs(instanceIds []string, keyIds ...string) error {
+	return i.keyPairJob("DetachKeyPairs", instanceIds, keyIds)
+}
+
+// DeleteKeyPairs 删除密钥对及 KeyStore 中保存的私钥
+func (i *InstanceServer) DeleteKeyPairs(keyIds ...string) error {
+	if err := i.client.Do(context.Background(), "DeleteKeyPairs", iaas.Params{}.SetList("keypairs", keyIds), nil); err != nil {
+		return err
+	}
+	for _, id := range keyIds {
+		if err := i.keyStore.Delete(id); err != nil {
+			return fmt.Errorf("delete private key of %s, %s", id, err)
+		}
+	}
+	return nil
+}
+
+// describeRules 分页查询安全组规则
+func (i *InstanceServer) describeRules(groupId string) ([]iaas.SecurityGroupRule, error) {
+	var rules []iaas.SecurityGroupRule
+	err := i.pages(iaas.Params{"security_group": groupId}, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeSecurityGroupRulesResponse
+		if err := i.client.Do(context.Background(), "DescribeSecurityGroupRules", params, &resp); err != nil {
+			return 0, err
+		}
+		rules = append(rules, resp.SecurityGroupRuleSet...)
+		return len(resp.SecurityGroupRuleSet), nil
+	})
+	return rules, err
+}
+
+// applySecurityGroup 应用安全组, instanceIds 为空时应用到已加入的实例
+func (i *InstanceServer) applySecurityGroup(groupId string, instanceIds []string) error {
+	params := iaas.Params{"security_group": groupId}.SetList("instances", instanceIds)
+	var resp iaas.Response
+	if err := i.client.Do(context.Background(), "ApplySecurityGroup", params, &resp); err != nil {
+		return err
+	}
+	return i.waitJob(resp.JobId)
+}
+
+// keyPairJob 调用绑定或解绑密钥对接口并等待任务完成
+func (i *InstanceServer) keyPairJob(action string, instanceIds, keyIds []string) error {
+	params := iaas.Params{}.SetList("keypairs", keyIds).SetList("instances", instanceIds)
+	var resp iaas.Response
+	if err := i.client.Do(context.Background(), action, params, &resp); err != nil {
+		return err
+	}
+	return i.waitJob(resp.JobId)
+}
+
+// protocols 协议转换为青云格式, ALL 拆分为 tcp、udp、icmp
+func protocols(protocol string) []string {
+	if strings.EqualFold(protocol, cloud.ProtocolALL) {
+		return []string{"tcp", "udp", "icmp"}
+	}
+	return []string{strings.ToLower(protocol)}
+}
+
+// direction 规则方向转换为青云格式
+func direction(d cloud.RuleDirection) int {
+	if d == cloud.RuleEgress {
+		return iaas.DirectionEgress
+	}
+	return iaas.DirectionIngress
+}
+
+// portRange 端口转换为青云 val1、val2, ICMP 不限类型, TCP/UDP 不限端口时为 1-65535
+func portRange(protocol, port string) (string, string) {
+	if protocol == "icmp" {
+		return "", ""
+	}
+	if port == "" || strings.EqualFold(port, "ALL") {
+		return "1", "65535"
+	}
+	if n := strings.Index(port, "-"); n > 0 {
+		return port[:n], port[n+1:]
+	}
+	return port, port
+}
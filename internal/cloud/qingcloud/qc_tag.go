@@ -0,0 +1,93 @@
+package qingcloud
+
+import (
+	"context"
+	"github.com/eadydb/k8s-aim/internal/cloud/qingcloud/iaas"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 青云标签只有名称, 键值标签以 key=value 作为标签名称
+const tagSeparator = "="
+
+// tagIds 查询键值标签对应的标签ID, create 为 true 时创建不存在的标签, 否则跳过
+func (i *InstanceServer) tagIds(tags map[string]string, create bool) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	existing := make(map[string]string)
+	err := i.pages(iaas.Params{}, func(params iaas.Params) (int, error) {
+		var resp iaas.DescribeTagsResponse
+		if err := i.client.Do(context.Background(), "DescribeTags", params, &resp); err != nil {
+			return 0, err
+		}
+		for _, tag := range resp.TagSet {
+			existing[tag.TagName] = tag.TagId
+		}
+		return len(resp.TagSet), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, name := range tagNames(tags) {
+		if id, ok := existing[name]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		if !create {
+			continue
+		}
+		var resp iaas.CreateTagResponse
+		if err := i.client.Do(context.Background(), "CreateTag", iaas.Params{"tag_name": name}, &resp); err != nil {
+			return nil, err
+		}
+		ids = append(ids, resp.TagId)
+	}
+	return ids, nil
+}
+
+// attachTags 为实例绑定标签, 标签不存在时创建
+func (i *InstanceServer) attachTags(instanceIds []string, tags map[string]string) error {
+	tagIds, err := i.tagIds(tags, true)
+	if err != nil || len(tagIds) == 0 {
+		return err
+	}
+	params := iaas.Params{}
+	n := 0
+	for _, instanceId := range instanceIds {
+		for _, tagId := range tagIds {
+			n++
+			prefix := "resource_tag_pairs." + strconv.Itoa(n) + "."
+			params[prefix+"tag_id"] = tagId
+			params[prefix+"resource_type"] = "instance"
+			params[prefix+"resource_id"] = instanceId
+		}
+	}
+	return i.client.Do(context.Background(), "AttachTags", params, nil)
+}
+
+// tagNames 键值标签转换为排序后的标签名称
+func tagNames(tags map[string]string) []string {
+	names := make([]string, 0, len(tags))
+	for k, v := range tags {
+		names = append(names, k+tagSeparator+v)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fromTags 标签名称转换为键值标签, 不含分隔符的标签值为空
+func fromTags(tags []iaas.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		n := strings.Index(tag.TagName, tagSeparator)
+		if n < 0 {
+			m[tag.TagName] = ""
+			continue
+		}
+		m[tag.TagName[:n]] = tag.TagName[n+1:]
+	}
+	return m
+}
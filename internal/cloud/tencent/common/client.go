@@ -1,15 +1,15 @@
 package common
 
 import (
+	"context"
 	"encoding/hex"
-	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
 	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
 	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/profile"
 	"github.com/eadydb/k8s-aim/pkg/zlog"
 	"net/http"
-	"net/http/httputil"
 	"strconv"
 	"strings"
 	"time"
@@ -26,14 +26,21 @@ type Client struct {
 	httpClient      *http.Client           // httpClient
 	httpProfile     *profile.HttpProfile   // http请求设置
 	profile         *profile.ClientProfile // 客户端请求设置
-	credential      *Credential            // 凭证
+	credential      CredentialProvider     // 凭证提供者
 	signMethod      string                 // 签名方法
 	unsignedPayload bool                   // 是否开启签名方法 v3
 	debug           bool                   // 是否debug
+	limiter         *rateLimiter           // 按接口限流
+	middlewares     []Middleware           // 中间件, 包装每次请求
 }
 
 // Send 发送请求
 func (c *Client) Send(request tcHttp.Request, response tcHttp.Response) (err error) {
+	return c.SendWithContext(context.Background(), request, response)
+}
+
+// SendWithContext 发送请求, ctx 取消或超时后终止请求、重试及限流等待
+func (c *Client) SendWithContext(ctx context.Context, request tcHttp.Request, response tcHttp.Response) (err error) {
 	if request.GetScheme() == "" {
 		request.SetScheme(c.httpProfile.Scheme)
 	}
@@ -54,27 +61,93 @@ func (c *Client) Send(request tcHttp.Request, response tcHttp.Response) (err err
 		request.SetHttpMethod(c.httpProfile.ReqMethod)
 	}
 
-	tcHttp.CompleteCommonParams(request, c.GetRegion())
+	retry := c.profile.RetryProfile
+	if retry == nil {
+		retry = profile.NewRetryProfile()
+	}
+	for attempt := 1; ; attempt++ {
+		// 每次尝试都重新生成 Timestamp、Nonce 并重新签名
+		if err = c.waitRateLimit(ctx, request); err != nil {
+			return c.annotate(err, request)
+		}
+		tcHttp.CompleteCommonParams(request, c.GetRegion())
+		err = c.invoke(ctx, request, response, attempt)
+		if err == nil || ctx.Err() != nil || attempt >= retry.MaxAttempts || !isRetryable(retry, err) {
+			return err
+		}
+		delay := backoff(retry, attempt)
+		zlog.Warnf("request %s failed, retry %d/%d after %s, %s", request.GetAction(), attempt, retry.MaxAttempts-1, delay, err)
+		if sleepErr := sleepWithContext(ctx, delay); sleepErr != nil {
+			// 保留上次请求的错误, 便于调用方了解重试原因
+			return &interruptedError{cause: sleepErr, last: err}
+		}
+	}
+}
+
+// annotate 错误中补充接口名称及地域
+func (c *Client) annotate(err error, request tcHttp.Request) error {
+	var sdkErr *errors.TencentCloudSDKError
+	if stderrors.As(err, &sdkErr) {
+		if sdkErr.Action == "" {
+			sdkErr.Action = request.GetAction()
+		}
+		if sdkErr.Region == "" {
+			sdkErr.Region = c.GetRegion()
+		}
+	}
+	return err
+}
+
+// waitRateLimit 按 service/action 限流, 令牌不足时阻塞等待
+func (c *Client) waitRateLimit(ctx context.Context, request tcHttp.Request) error {
+	if c.limiter == nil {
+		return nil
+	}
+	key := request.GetService() + "/" + request.GetAction()
+	waited, err := c.limiter.wait(ctx, key)
+	if waited > 0 {
+		zlog.Debugf("request %s waited %s for rate limit", key, waited)
+	}
+	return err
+}
 
+// RateLimitStats 各接口限流排队统计, key 为 service/action
+func (c *Client) RateLimitStats() map[string]RateLimitStat {
+	if c.limiter == nil {
+		return map[string]RateLimitStat{}
+	}
+	return c.limiter.snapshot()
+}
+
+// send 按签名方法发送一次请求
+func (c *Client) send(ctx context.Context, request tcHttp.Request, response tcHttp.Response) (err error) {
+	// 每次请求都从提供者获取凭证, 以便临时凭证过期前自动轮换
+	if c.credential == nil {
+		return errors.NewTencentCloudSDKError("ClientError.CredentialError", "credential provider is not set", "")
+	}
+	credential, err := getCredential(ctx, c.credential)
+	if err != nil {
+		return errors.WrapError("ClientError.CredentialError", "Fail to get credential", err)
+	}
 	if c.signMethod == HmacSHA1 || c.signMethod == HmacSHA256 {
-		return c.sendWithSignatureV1(request, response)
+		return c.sendWithSignatureV1(ctx, credential, request, response)
 	} else {
-		return c.sendWithSignatureV3(request, response)
+		return c.sendWithSignatureV3(ctx, credential, request, response)
 	}
 }
 
 // sendWithSignatureV1 签名方法V1
-func (c *Client) sendWithSignatureV1(request tcHttp.Request, response tcHttp.Response) (err error) {
+func (c *Client) sendWithSignatureV1(ctx context.Context, credential *Credential, request tcHttp.Request, response tcHttp.Response) (err error) {
 	request.GetParams()["Language"] = c.profile.Language
 	err = tcHttp.ConstructParams(request)
 	if err != nil {
 		return err
 	}
-	err = signRequest(request, c.credential, c.signMethod)
+	err = signRequest(request, credential, c.signMethod)
 	if err != nil {
 		return err
 	}
-	httpRequest, err := http.NewRequest(request.GetHttpMethod(), request.GetUrl(), request.GetBodyReader())
+	httpRequest, err := http.NewRequestWithContext(ctx, request.GetHttpMethod(), request.GetUrl(), request.GetBodyReader())
 	if err != nil {
 		return err
 	}
@@ -82,24 +155,25 @@ func (c *Client) sendWithSignatureV1(request tcHttp.Request, response tcHttp.Res
 		httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	}
 	if c.debug {
-		outputs, err := httputil.DumpRequest(httpRequest, true)
-		if err != nil {
-			zlog.Errorf("dump request failed because %s", err)
-			return err
+		body := ""
+		if request.GetHttpMethod() == "POST" {
+			body = tcHttp.GetUrlQueriesEncoded(request.GetParams())
 		}
-		zlog.Debugf("http request = %s", outputs)
+		dumpRequest(httpRequest, body)
 	}
 	httpResponse, err := c.httpClient.Do(httpRequest)
 	if err != nil {
-		msg := fmt.Sprintf("Fail to get response because %s", err)
-		return errors.NewTencentCloudSDKError("ClientError.NetworkError", msg, "")
+		return errors.WrapError("ClientError.NetworkError", "Fail to get response", err)
+	}
+	if c.debug {
+		dumpResponse(httpResponse)
 	}
 	err = tcHttp.ParseFromHttpResponse(httpResponse, response)
 	return err
 }
 
 // sendWithSignatureV3 签名方法 v3
-func (c *Client) sendWithSignatureV3(request tcHttp.Request, response tcHttp.Response) (err error) {
+func (c *Client) sendWithSignatureV3(ctx context.Context, credential *Credential, request tcHttp.Request, response tcHttp.Response) (err error) {
 	headers := map[string]string{
 		"Host":               request.GetDomain(),
 		"X-TC-Action":        request.GetAction(),
@@ -111,8 +185,8 @@ func (c *Client) sendWithSignatureV3(request tcHttp.Request, response tcHttp.Res
 	if c.region != "" {
 		headers["X-TC-Region"] = c.region
 	}
-	if c.credential.Token != "" {
-		headers["X-TC-Token"] = c.credential.Token
+	if credential.Token != "" {
+		headers["X-TC-Token"] = credential.Token
 	}
 	if request.GetHttpMethod() == "GET" {
 		headers["Content-Type"] = "application/x-www-form-urlencoded"
@@ -147,7 +221,7 @@ func (c *Client) sendWithSignatureV3(request tcHttp.Request, response tcHttp.Res
 	signedHeaders := "content-type;host"
 	requestPayload := ""
 	if httpRequestMethod == "POST" {
-		b, err := json.Marshal(request)
+		b, err := tcHttp.EncodeJSON(request)
 		if err != nil {
 			return err
 		}
@@ -186,7 +260,7 @@ func (c *Client) sendWithSignatureV3(request tcHttp.Request, response tcHttp.Res
 	//log.Println("string2sign", string2sign)
 
 	// sign string
-	secretDate := hmacsha256(date, "TC3"+c.credential.SecretKey)
+	secretDate := hmacsha256(date, "TC3"+credential.SecretKey)
 	secretService := hmacsha256(request.GetService(), secretDate)
 	secretKey := hmacsha256("tc3_request", secretService)
 	signature := hex.EncodeToString([]byte(hmacsha256(string2sign, secretKey)))
@@ -195,7 +269,7 @@ func (c *Client) sendWithSignatureV3(request tcHttp.Request, response tcHttp.Res
 	// build authorization
 	authorization := fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
 		algorithm,
-		c.credential.SecretId,
+		credential.SecretId,
 		credentialScope,
 		signedHeaders,
 		signature)
@@ -206,7 +280,7 @@ func (c *Client) sendWithSignatureV3(request tcHttp.Request, response tcHttp.Res
 	if canonicalQueryString != "" {
 		url = url + "?" + canonicalQueryString
 	}
-	httpRequest, err := http.NewRequest(httpRequestMethod, url, strings.NewReader(requestPayload))
+	httpRequest, err := http.NewRequestWithContext(ctx, httpRequestMethod, url, strings.NewReader(requestPayload))
 	if err != nil {
 		return err
 	}
@@ -214,17 +288,14 @@ func (c *Client) sendWithSignatureV3(request tcHttp.Request, response tcHttp.Res
 		httpRequest.Header[k] = []string{v}
 	}
 	if c.debug {
-		outbytes, err := httputil.DumpRequest(httpRequest, true)
-		if err != nil {
-			zlog.Errorf("dump request failed because %s", err)
-			return err
-		}
-		zlog.Debugf("http request = %s", outbytes)
+		dumpRequest(httpRequest, requestPayload)
 	}
 	httpResponse, err := c.httpClient.Do(httpRequest)
 	if err != nil {
-		msg := fmt.Sprintf("Fail to get response because %s", err)
-		return errors.NewTencentCloudSDKError("ClientError.NetworkError", msg, "")
+		return errors.WrapError("ClientError.NetworkError", "Fail to get response", err)
+	}
+	if c.debug {
+		dumpResponse(httpResponse)
 	}
 	err = tcHttp.ParseFromHttpResponse(httpResponse, response)
 	return err
@@ -239,8 +310,7 @@ func (c *Client) GetRegion() string {
 func (c *Client) Init(region string) *Client {
 	c.httpClient = &http.Client{}
 	c.region = region
-	c.signMethod = "TC3-HMAC-SHA256"
-	c.debug = false
+	c.WithProfile(profile.NewClientProfile())
 	return c
 }
 
@@ -256,6 +326,12 @@ func (c *Client) WithCredential(cred *Credential) *Client {
 	return c
 }
 
+// WithProvider 凭证提供者, 每次请求前获取凭证
+func (c *Client) WithProvider(provider CredentialProvider) *Client {
+	c.credential = provider
+	return c
+}
+
 // WithProfile 客户端参数设置
 func (c *Client) WithProfile(clientProfile *profile.ClientProfile) *Client {
 	c.profile = clientProfile
@@ -264,6 +340,10 @@ func (c *Client) WithProfile(clientProfile *profile.ClientProfile) *Client {
 	c.httpProfile = clientProfile.HttpProfile
 	c.debug = clientProfile.Debug
 	c.httpClient.Timeout = time.Duration(c.httpProfile.ReqTimeout) * time.Second
+	c.limiter = nil
+	if clientProfile.RateLimitProfile != nil {
+		c.limiter = newRateLimiter(clientProfile.RateLimitProfile)
+	}
 	return c
 }
 
@@ -291,3 +371,10 @@ func NewClientWithSecretId(secretId, secretKey, region string) (client *Client,
 	client.Init(region).WithSecretId(secretId, secretKey)
 	return
 }
+
+// NewClientWithProvider 使用凭证提供者实例化客户端
+func NewClientWithProvider(provider CredentialProvider, region string) (client *Client, err error) {
+	client = &Client{}
+	client.Init(region).WithProvider(provider)
+	return
+}
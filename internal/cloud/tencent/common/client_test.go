@@ -0,0 +1,91 @@
+package common_test
+
+import (
+	"context"
+	"errors"
+	tcerr "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/emulator"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newRetryClient 访问模拟服务的客户端, 最多尝试 maxAttempts 次, 重试等待 delay
+func newRetryClient(t *testing.T, maxAttempts int, delay time.Duration) (*emulator.Server, *cvm.Client) {
+	t.Helper()
+	s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+	t.Cleanup(s.Close)
+	cpf := s.ClientProfile("")
+	cpf.RetryProfile.MaxAttempts = maxAttempts
+	cpf.RetryProfile.BaseDelay = delay
+	cpf.RetryProfile.MaxDelay = delay
+	cpf.RetryProfile.Jitter = 0
+	client, err := cvm.NewClient(s.Credential(), "ap-guangzhou", cpf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, client
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		failure  *emulator.Error
+		times    int
+		ok       bool
+		requests int
+	}{
+		{"throttled", &emulator.Error{Code: "RequestLimitExceeded", Message: "too many requests"}, 2, true, 3},
+		{"server error", &emulator.Error{HttpStatus: http.StatusBadGateway, Message: "bad gateway"}, 1, true, 2},
+		{"internal error", &emulator.Error{Code: "InternalError", Message: "internal error"}, 1, true, 2},
+		{"client error", &emulator.Error{Code: "InvalidParameterValue", Message: "invalid zone"}, 1, false, 1},
+		{"max attempts", &emulator.Error{Code: "InternalError", Message: "internal error"}, 5, false, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, client := newRetryClient(t, 3, time.Millisecond)
+			s.Fail("DescribeZones", tt.failure, tt.times)
+			_, err := client.DescribeZones(nil)
+			if (err == nil) != tt.ok {
+				t.Fatalf("err = %v, want ok %v", err, tt.ok)
+			}
+			if n := len(s.Requests()); n != tt.requests {
+				t.Fatalf("sent %d requests, want %d", n, tt.requests)
+			}
+			if !tt.ok {
+				var sdkErr *tcerr.TencentCloudSDKError
+				if !errors.As(err, &sdkErr) || sdkErr.Code != tt.failure.Code {
+					t.Fatalf("err = %v, want code %s", err, tt.failure.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	s, client := newRetryClient(t, 3, time.Hour)
+	s.Fail("DescribeZones", &emulator.Error{Code: "RequestLimitExceeded", Message: "too many requests"}, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.DescribeZonesWithContext(ctx, nil)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("returned after %s, want to stop waiting when the context is done", elapsed)
+	}
+	if n := len(s.Requests()); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+	// ctx 错误及上次请求的错误均可匹配
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if !errors.Is(err, tcerr.ErrThrottled) || tcerr.CategoryOf(err) != tcerr.CategoryThrottled {
+		t.Fatalf("err = %v, want throttled", err)
+	}
+	var sdkErr *tcerr.TencentCloudSDKError
+	if !errors.As(err, &sdkErr) || sdkErr.Code != "RequestLimitExceeded" {
+		t.Fatalf("err = %v, want RequestLimitExceeded", err)
+	}
+}
@@ -1,5 +1,29 @@
 package common
 
+import "context"
+
+// CredentialProvider 凭证提供者
+type CredentialProvider interface {
+	// GetCredential 获取凭证, 临时凭证需在过期前返回刷新后的凭证
+	GetCredential() (*Credential, error)
+}
+
+// ContextCredentialProvider 刷新凭证需要请求远端服务的凭证提供者, 可选实现
+type ContextCredentialProvider interface {
+	CredentialProvider
+
+	// GetCredentialWithContext 获取凭证, ctx 取消或超时后终止刷新
+	GetCredentialWithContext(ctx context.Context) (*Credential, error)
+}
+
+// getCredential 获取凭证, 提供者支持时传入 ctx
+func getCredential(ctx context.Context, provider CredentialProvider) (*Credential, error) {
+	if p, ok := provider.(ContextCredentialProvider); ok {
+		return p.GetCredentialWithContext(ctx)
+	}
+	return provider.GetCredential()
+}
+
 // Credential 凭证
 type Credential struct {
 	SecretId  string
@@ -35,3 +59,8 @@ func (c *Credential) GetCredentialParams() map[string]string {
 
 	return p
 }
+
+// GetCredential 固定凭证
+func (c *Credential) GetCredential() (*Credential, error) {
+	return c, nil
+}
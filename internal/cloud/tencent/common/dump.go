@@ -0,0 +1,104 @@
+package common
+
+import (
+	"github.com/eadydb/k8s-aim/pkg/zlog"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted = "***"
+
+var (
+	signaturePattern  = regexp.MustCompile(`Signature=[^,\s]+`)
+	credentialPattern = regexp.MustCompile(`Credential=([^/,\s]+)`)
+	privateKeyPattern = regexp.MustCompile(`"PrivateKey"\s*:\s*"(?:[^"\\]|\\.)*"`)
+)
+
+// dumpRequest 调试模式下输出请求, 签名、临时证书 Token 均脱敏, SecretId 仅保留前缀
+func dumpRequest(httpRequest *http.Request, body string) {
+	b, err := redactRequest(httpRequest, body)
+	if err != nil {
+		zlog.Errorf("dump request failed because %s", err)
+		return
+	}
+	zlog.Debugf("http request = %s", b)
+}
+
+// dumpResponse 调试模式下输出响应, 私钥脱敏
+func dumpResponse(httpResponse *http.Response) {
+	b, err := redactResponse(httpResponse)
+	if err != nil {
+		zlog.Errorf("dump response failed because %s", err)
+		return
+	}
+	zlog.Debugf("http response = %s", b)
+}
+
+// redactRequest 脱敏后的请求内容
+func redactRequest(httpRequest *http.Request, body string) ([]byte, error) {
+	dump := httpRequest.Clone(httpRequest.Context())
+	for key, values := range dump.Header {
+		switch {
+		case strings.EqualFold(key, "Authorization"):
+			dump.Header[key] = []string{redactAuthorization(values[0])}
+		case strings.EqualFold(key, "X-TC-Token"):
+			dump.Header[key] = []string{redacted}
+		}
+	}
+	dump.URL.RawQuery = redactValues(dump.URL.RawQuery)
+	if strings.HasPrefix(dump.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		body = redactValues(body)
+	}
+	dump.Body = ioutil.NopCloser(strings.NewReader(body))
+	dump.ContentLength = int64(len(body))
+	return httputil.DumpRequest(dump, true)
+}
+
+// redactResponse 脱敏后的响应内容
+func redactResponse(httpResponse *http.Response) ([]byte, error) {
+	b, err := httputil.DumpResponse(httpResponse, true)
+	if err != nil {
+		return nil, err
+	}
+	return privateKeyPattern.ReplaceAll(b, []byte(`"PrivateKey":"`+redacted+`"`)), nil
+}
+
+// redactAuthorization 脱敏签名方法V3的 Authorization 请求头
+func redactAuthorization(authorization string) string {
+	authorization = signaturePattern.ReplaceAllString(authorization, "Signature="+redacted)
+	return credentialPattern.ReplaceAllStringFunc(authorization, func(s string) string {
+		return "Credential=" + maskSecretId(strings.TrimPrefix(s, "Credential="))
+	})
+}
+
+// redactValues 脱敏签名方法V1的表单或 URL 参数
+func redactValues(query string) string {
+	if query == "" {
+		return query
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return redacted
+	}
+	for _, key := range []string{"Signature", "Token"} {
+		if values.Get(key) != "" {
+			values.Set(key, redacted)
+		}
+	}
+	if secretId := values.Get("SecretId"); secretId != "" {
+		values.Set("SecretId", maskSecretId(secretId))
+	}
+	return values.Encode()
+}
+
+// maskSecretId SecretId 仅保留前8位
+func maskSecretId(secretId string) string {
+	if len(secretId) <= 8 {
+		return redacted
+	}
+	return secretId[:8] + redacted
+}
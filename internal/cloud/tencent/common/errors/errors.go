@@ -1,15 +1,122 @@
 package errors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Category 错误类别, 调用方据此决定重试、切换可用区或放弃
+type Category string
+
+const (
+	CategoryUnknown           Category = "Unknown"           // 未知
+	CategoryAuth              Category = "Auth"              // 认证、鉴权失败
+	CategoryThrottled         Category = "Throttled"         // 请求频率超限, 可重试
+	CategoryQuotaExceeded     Category = "QuotaExceeded"     // 配额不足
+	CategoryInsufficientStock Category = "InsufficientStock" // 资源售罄, 可切换可用区或机型
+	CategoryInvalidParameter  Category = "InvalidParameter"  // 参数错误
+	CategoryResourceNotFound  Category = "ResourceNotFound"  // 资源不存在
+	CategoryNetwork           Category = "Network"           // 网络错误, 可重试
+	CategoryServer            Category = "Server"            // 服务端内部错误, 可重试
+)
+
+// 错误类别哨兵, 配合 errors.Is 使用, 如 errors.Is(err, ErrThrottled)
+var (
+	ErrAuth              = errors.New("tencentcloud: auth failure")
+	ErrThrottled         = errors.New("tencentcloud: request throttled")
+	ErrQuotaExceeded     = errors.New("tencentcloud: quota exceeded")
+	ErrInsufficientStock = errors.New("tencentcloud: insufficient stock")
+	ErrInvalidParameter  = errors.New("tencentcloud: invalid parameter")
+	ErrResourceNotFound  = errors.New("tencentcloud: resource not found")
+	ErrNetwork           = errors.New("tencentcloud: network error")
+	ErrServer            = errors.New("tencentcloud: server error")
+)
+
+var sentinels = map[Category]error{
+	CategoryAuth:              ErrAuth,
+	CategoryThrottled:         ErrThrottled,
+	CategoryQuotaExceeded:     ErrQuotaExceeded,
+	CategoryInsufficientStock: ErrInsufficientStock,
+	CategoryInvalidParameter:  ErrInvalidParameter,
+	CategoryResourceNotFound:  ErrResourceNotFound,
+	CategoryNetwork:           ErrNetwork,
+	CategoryServer:            ErrServer,
+}
+
+// classifications 错误码前缀与类别, 按顺序匹配, 前缀同时匹配其子错误码
+var classifications = []struct {
+	category Category
+	prefixes []string
+}{
+	{CategoryAuth, []string{"AuthFailure", "UnauthorizedOperation", "ClientError.CredentialError"}},
+	{CategoryThrottled, []string{"RequestLimitExceeded", "ClientError.RateLimitWaitTimeout"}},
+	{CategoryInsufficientStock, []string{"ResourceInsufficient", "ResourcesSoldOut", "ResourceUnavailable"}},
+	{CategoryQuotaExceeded, []string{"LimitExceeded"}},
+	{CategoryResourceNotFound, []string{"ResourceNotFound"}},
+	{CategoryInvalidParameter, []string{"InvalidParameter", "InvalidParameterValue", "MissingParameter", "UnknownParameter", "InvalidAction", "InvalidFilter", "UnsupportedOperation"}},
+	{CategoryNetwork, []string{"ClientError.NetworkError", "ClientError.IOError"}},
+	{CategoryServer, []string{"InternalError", "InternalServerError", "ServiceUnavailable"}},
+}
 
 type TencentCloudSDKError struct {
-	Code      string
-	Message   string
-	RequestId string
+	Code       string
+	Message    string
+	RequestId  string
+	HttpStatus int    // http 状态码, 仅在服务端返回非 200 时设置
+	Action     string // 接口名称
+	Region     string // 地域
+	Err        error  // 底层错误, 如网络错误
 }
 
 func (e *TencentCloudSDKError) Error() string {
-	return fmt.Sprintf("[TencentCloudSDKError] Code=%s, Message=%s, RequestId=%s", e.Code, e.Message, e.RequestId)
+	var b strings.Builder
+	fmt.Fprintf(&b, "[TencentCloudSDKError] Code=%s, Message=%s, RequestId=%s", e.Code, e.Message, e.RequestId)
+	if e.Action != "" {
+		fmt.Fprintf(&b, ", Action=%s", e.Action)
+	}
+	if e.Region != "" {
+		fmt.Fprintf(&b, ", Region=%s", e.Region)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ", Cause=%s", e.Err)
+	}
+	return b.String()
+}
+
+// Unwrap 返回底层错误
+func (e *TencentCloudSDKError) Unwrap() error {
+	return e.Err
+}
+
+// Is 按错误类别匹配哨兵错误
+func (e *TencentCloudSDKError) Is(target error) bool {
+	sentinel, ok := sentinels[e.Category()]
+	return ok && target == sentinel
+}
+
+// Category 根据错误码及 http 状态码判断错误类别
+func (e *TencentCloudSDKError) Category() Category {
+	// 资源不存在的错误码多为 InvalidXxxId.NotFound 形式
+	if strings.HasSuffix(e.Code, ".NotFound") {
+		return CategoryResourceNotFound
+	}
+	for _, c := range classifications {
+		for _, prefix := range c.prefixes {
+			if e.Code == prefix || strings.HasPrefix(e.Code, prefix+".") {
+				return c.category
+			}
+		}
+	}
+	switch {
+	case strings.HasPrefix(e.Code, "Invalid"):
+		return CategoryInvalidParameter
+	case e.HttpStatus == 429:
+		return CategoryThrottled
+	case e.HttpStatus >= 500:
+		return CategoryServer
+	}
+	return CategoryUnknown
 }
 
 func NewTencentCloudSDKError(code, message, requestId string) error {
@@ -20,6 +127,33 @@ func NewTencentCloudSDKError(code, message, requestId string) error {
 	}
 }
 
+// WrapError 包装底层错误
+func WrapError(code, message string, err error) error {
+	return &TencentCloudSDKError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// NewHttpStatusError http 状态码错误
+func NewHttpStatusError(status int, message string) error {
+	return &TencentCloudSDKError{
+		Code:       "ClientError.HttpStatusCodeError",
+		Message:    message,
+		HttpStatus: status,
+	}
+}
+
+// CategoryOf 错误类别, 非 TencentCloudSDKError 时返回 CategoryUnknown
+func CategoryOf(err error) Category {
+	var sdkErr *TencentCloudSDKError
+	if errors.As(err, &sdkErr) {
+		return sdkErr.Category()
+	}
+	return CategoryUnknown
+}
+
 func (e *TencentCloudSDKError) GetCode() string {
 	return e.Code
 }
@@ -30,4 +164,4 @@ func (e *TencentCloudSDKError) GetMessage() string {
 
 func (e *TencentCloudSDKError) GetRequestId() string {
 	return e.RequestId
-}
\ No newline at end of file
+}
@@ -0,0 +1,83 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+	tcerr "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"net/http"
+	"testing"
+)
+
+func TestCategoryOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category tcerr.Category
+	}{
+		{"auth", tcerr.NewTencentCloudSDKError("AuthFailure.SignatureFailure", "", ""), tcerr.CategoryAuth},
+		{"unauthorized", tcerr.NewTencentCloudSDKError("UnauthorizedOperation", "", ""), tcerr.CategoryAuth},
+		{"credential", tcerr.NewTencentCloudSDKError("ClientError.CredentialError", "", ""), tcerr.CategoryAuth},
+		{"throttled", tcerr.NewTencentCloudSDKError("RequestLimitExceeded", "", ""), tcerr.CategoryThrottled},
+		{"throttled subcode", tcerr.NewTencentCloudSDKError("RequestLimitExceeded.UinLimitExceeded", "", ""), tcerr.CategoryThrottled},
+		{"rate limit wait", tcerr.NewTencentCloudSDKError("ClientError.RateLimitWaitTimeout", "", ""), tcerr.CategoryThrottled},
+		{"sold out", tcerr.NewTencentCloudSDKError("ResourcesSoldOut.SpecifiedInstanceType", "", ""), tcerr.CategoryInsufficientStock},
+		{"insufficient", tcerr.NewTencentCloudSDKError("ResourceInsufficient.AvailabilityZoneSoldOut", "", ""), tcerr.CategoryInsufficientStock},
+		{"unavailable", tcerr.NewTencentCloudSDKError("ResourceUnavailable.InstanceType", "", ""), tcerr.CategoryInsufficientStock},
+		{"quota", tcerr.NewTencentCloudSDKError("LimitExceeded.InstanceQuota", "", ""), tcerr.CategoryQuotaExceeded},
+		{"not found", tcerr.NewTencentCloudSDKError("ResourceNotFound.NoDefaultVpc", "", ""), tcerr.CategoryResourceNotFound},
+		{"id not found", tcerr.NewTencentCloudSDKError("InvalidInstanceId.NotFound", "", ""), tcerr.CategoryResourceNotFound},
+		{"invalid parameter", tcerr.NewTencentCloudSDKError("InvalidParameterValue.Range", "", ""), tcerr.CategoryInvalidParameter},
+		{"unsupported", tcerr.NewTencentCloudSDKError("UnsupportedOperation.InstanceStateStopped", "", ""), tcerr.CategoryInvalidParameter},
+		{"other invalid code", tcerr.NewTencentCloudSDKError("InvalidZone.MismatchRegion", "", ""), tcerr.CategoryInvalidParameter},
+		{"network", tcerr.WrapError("ClientError.NetworkError", "", errors.New("connection reset")), tcerr.CategoryNetwork},
+		{"internal", tcerr.NewTencentCloudSDKError("InternalError", "", ""), tcerr.CategoryServer},
+		{"internal subcode", tcerr.NewTencentCloudSDKError("InternalError.UnknownError", "", ""), tcerr.CategoryServer},
+		{"service unavailable", tcerr.NewTencentCloudSDKError("ServiceUnavailable", "", ""), tcerr.CategoryServer},
+		{"prefix without separator", tcerr.NewTencentCloudSDKError("LimitExceededFoo", "", ""), tcerr.CategoryUnknown},
+		{"http 429", tcerr.NewHttpStatusError(http.StatusTooManyRequests, ""), tcerr.CategoryThrottled},
+		{"http 502", tcerr.NewHttpStatusError(http.StatusBadGateway, ""), tcerr.CategoryServer},
+		{"http 404", tcerr.NewHttpStatusError(http.StatusNotFound, ""), tcerr.CategoryUnknown},
+		{"code before http status", &tcerr.TencentCloudSDKError{Code: "AuthFailure", HttpStatus: http.StatusServiceUnavailable}, tcerr.CategoryAuth},
+		{"wrapped", fmt.Errorf("create instance: %w", tcerr.NewTencentCloudSDKError("RequestLimitExceeded", "", "")), tcerr.CategoryThrottled},
+		{"unknown code", tcerr.NewTencentCloudSDKError("FailedOperation", "", ""), tcerr.CategoryUnknown},
+		{"not sdk error", errors.New("boom"), tcerr.CategoryUnknown},
+		{"nil", nil, tcerr.CategoryUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tcerr.CategoryOf(tt.err); got != tt.category {
+				t.Fatalf("CategoryOf(%v) = %s, want %s", tt.err, got, tt.category)
+			}
+		})
+	}
+}
+
+func TestCategorySentinels(t *testing.T) {
+	tests := []struct {
+		err      error
+		sentinel error
+	}{
+		{tcerr.NewTencentCloudSDKError("AuthFailure", "", ""), tcerr.ErrAuth},
+		{tcerr.NewTencentCloudSDKError("RequestLimitExceeded", "", ""), tcerr.ErrThrottled},
+		{tcerr.NewTencentCloudSDKError("LimitExceeded", "", ""), tcerr.ErrQuotaExceeded},
+		{tcerr.NewTencentCloudSDKError("ResourcesSoldOut", "", ""), tcerr.ErrInsufficientStock},
+		{tcerr.NewTencentCloudSDKError("InvalidParameter", "", ""), tcerr.ErrInvalidParameter},
+		{tcerr.NewTencentCloudSDKError("InvalidVpcId.NotFound", "", ""), tcerr.ErrResourceNotFound},
+		{tcerr.NewTencentCloudSDKError("ClientError.IOError", "", ""), tcerr.ErrNetwork},
+		{tcerr.NewHttpStatusError(http.StatusInternalServerError, ""), tcerr.ErrServer},
+	}
+	all := []error{tcerr.ErrAuth, tcerr.ErrThrottled, tcerr.ErrQuotaExceeded, tcerr.ErrInsufficientStock,
+		tcerr.ErrInvalidParameter, tcerr.ErrResourceNotFound, tcerr.ErrNetwork, tcerr.ErrServer}
+	for _, tt := range tests {
+		for _, sentinel := range all {
+			if got, want := errors.Is(tt.err, sentinel), sentinel == tt.sentinel; got != want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, sentinel, got, want)
+			}
+		}
+	}
+	// 底层错误仍可匹配
+	cause := errors.New("connection reset")
+	if err := tcerr.WrapError("ClientError.NetworkError", "", cause); !errors.Is(err, cause) {
+		t.Fatalf("errors.Is(%v, cause) = false", err)
+	}
+}
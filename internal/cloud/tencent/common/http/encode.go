@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// 请求参数统一按字段的 name 标签编码, 同一个请求结构体既可生成签名方法V1的表单参数, 如 Filters.0.Values.1,
+// 也可生成签名方法V3的 JSON 请求体, 没有 name 标签的字段(如 BaseRequest)、nil 指针、空字符串及空数组均忽略
+
+// EncodeParams 编码为表单参数, 嵌套字段以 . 连接, 数组下标从0开始
+func EncodeParams(req Request) (map[string]string, error) {
+	tree, err := encodeValue(reflect.ValueOf(req))
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	flatten(tree, "", params)
+	return params, nil
+}
+
+// EncodeJSON 编码为 JSON 请求体
+func EncodeJSON(req Request) ([]byte, error) {
+	tree, err := encodeValue(reflect.ValueOf(req))
+	if err != nil {
+		return nil, err
+	}
+	if tree == nil {
+		tree = map[string]interface{}{}
+	}
+	return json.Marshal(tree)
+}
+
+// encodeValue 按 name 标签将请求转换为由 map、数组及基本类型组成的参数树, 值为空时返回 nil
+func encodeValue(value reflect.Value) (interface{}, error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.String:
+		if value.String() == "" {
+			return nil, nil
+		}
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.Slice, reflect.Array:
+		var list []interface{}
+		for i := 0; i < value.Len(); i++ {
+			item, err := encodeValue(value.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			// 保留空元素, 避免后续元素下标错位
+			list = append(list, item)
+		}
+		if len(list) == 0 {
+			return nil, nil
+		}
+		return list, nil
+	case reflect.Struct:
+		fields := make(map[string]interface{})
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			name, ok := valueType.Field(i).Tag.Lookup("name")
+			if !ok {
+				continue
+			}
+			field, err := encodeValue(value.Field(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", valueType.Name(), name, err)
+			}
+			if field != nil {
+				fields[name] = field
+			}
+		}
+		if len(fields) == 0 {
+			return nil, nil
+		}
+		return fields, nil
+	}
+	return nil, fmt.Errorf("unsupported parameter type %s", value.Type())
+}
+
+// flatten 将参数树展开为表单参数
+func flatten(tree interface{}, key string, params map[string]string) {
+	switch v := tree.(type) {
+	case map[string]interface{}:
+		for name, item := range v {
+			flatten(item, join(key, name), params)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flatten(item, join(key, strconv.Itoa(i)), params)
+		}
+	case string:
+		params[key] = v
+	case bool:
+		params[key] = strconv.FormatBool(v)
+	case int64:
+		params[key] = strconv.FormatInt(v, 10)
+	case uint64:
+		params[key] = strconv.FormatUint(v, 10)
+	case float64:
+		params[key] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,118 @@
+package http_test
+
+import (
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/emulator"
+	"github.com/eadydb/k8s-aim/pkg/utils"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// newRunInstancesRequest 包含嵌套结构体、结构体数组、字符串数组及布尔、整数参数的请求
+func newRunInstancesRequest() *cvm.RunInstancesRequest {
+	req := cvm.NewRunInstancesRequest()
+	req.InstanceChargeType = utils.StringPtr("SPOTPAID")
+	req.InstanceMarketOptions = &cvm.InstanceMarketOptionsRequest{
+		MarketType:  utils.StringPtr("spot"),
+		SpotOptions: &cvm.SpotMarketOptions{MaxPrice: utils.StringPtr("0.25"), SpotInstanceType: utils.StringPtr("one-time")},
+	}
+	req.Placement = &cvm.Placement{Zone: utils.StringPtr("ap-guangzhou-3")}
+	req.InstanceType = utils.StringPtr("S5.MEDIUM4")
+	req.ImageId = utils.StringPtr("img-22trbn9x")
+	req.SystemDisk = &cvm.SystemDisk{DiskType: utils.StringPtr("CLOUD_PREMIUM"), DiskSize: utils.Int64Ptr(50)}
+	req.DataDisks = []*cvm.DataDisk{
+		{DiskType: utils.StringPtr("CLOUD_SSD"), DiskSize: utils.Int64Ptr(100), DeleteWithInstance: utils.BoolPtr(true)},
+		{DiskType: utils.StringPtr("CLOUD_PREMIUM"), DiskSize: utils.Int64Ptr(200), DeleteWithInstance: utils.BoolPtr(false)},
+	}
+	req.VirtualPrivateCloud = &cvm.VirtualPrivateCloud{VpcId: utils.StringPtr("vpc-2at5y1pn"), SubnetId: utils.StringPtr("subnet-hhi88a58")}
+	req.InstanceCount = utils.Int64Ptr(2)
+	req.InstanceName = utils.StringPtr("k8s worker/中文")
+	req.LoginSettings = &cvm.LoginSettings{KeyIds: utils.StringPtrs([]string{"skey-1", "skey-2"})}
+	req.SecurityGroupIds = utils.StringPtrs([]string{"sg-5275dorp"})
+	req.ClientToken = utils.StringPtr("token-1")
+	req.TagSpecification = []*cvm.TagSpecification{{
+		ResourceType: utils.StringPtr("instance"),
+		Tags:         []*cvm.Tag{{Key: utils.StringPtr("k8s-aim/cluster"), Value: utils.StringPtr("a=b&c")}},
+	}}
+	return req
+}
+
+func TestEncodeParams(t *testing.T) {
+	params, err := tcHttp.EncodeParams(newRunInstancesRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"InstanceMarketOptions.SpotOptions.MaxPrice": "0.25",
+		"Placement.Zone":                  "ap-guangzhou-3",
+		"SystemDisk.DiskSize":             "50",
+		"DataDisks.1.DiskSize":            "200",
+		"DataDisks.1.DeleteWithInstance":  "false",
+		"LoginSettings.KeyIds.1":          "skey-2",
+		"TagSpecification.0.Tags.0.Value": "a=b&c",
+		"InstanceCount":                   "2",
+	}
+	for key, value := range want {
+		if params[key] != value {
+			t.Errorf("params[%s] = %q, want %q", key, params[key], value)
+		}
+	}
+	// 未设置的字段不编码
+	for _, key := range []string{"UserData", "DryRun", "SystemDisk.DiskId", "HostName"} {
+		if _, ok := params[key]; ok {
+			t.Errorf("params contains unset %s", key)
+		}
+	}
+}
+
+// TestEncodeRoundTrip 同一请求分别以签名方法V1表单参数及V3 JSON 请求体发送到模拟服务, 服务端解析结果与原请求一致
+func TestEncodeRoundTrip(t *testing.T) {
+	cases := []struct {
+		signMethod string
+		reqMethod  string
+	}{
+		{"TC3-HMAC-SHA256", http.MethodPost},
+		{common.HmacSHA256, http.MethodPost},
+		{common.HmacSHA1, http.MethodGet},
+	}
+	for _, c := range cases {
+		t.Run(c.signMethod+"/"+c.reqMethod, func(t *testing.T) {
+			s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+			defer s.Close()
+			cpf := s.ClientProfile(c.signMethod)
+			cpf.HttpProfile.ReqMethod = c.reqMethod
+			client, err := cvm.NewClient(s.Credential(), "ap-guangzhou", cpf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sent := newRunInstancesRequest()
+			if _, err := client.RunInstances(sent); err != nil {
+				t.Fatal(err)
+			}
+			requests := s.Requests()
+			if len(requests) != 1 {
+				t.Fatalf("recorded %d requests, want 1", len(requests))
+			}
+			received := &cvm.RunInstancesRequest{}
+			if err := requests[0].Decode(received); err != nil {
+				t.Fatal(err)
+			}
+			sent.BaseRequest = nil
+			if !reflect.DeepEqual(sent, received) {
+				t.Fatalf("received request differs\nsent:     %s\nreceived: %s", dump(t, sent), dump(t, received))
+			}
+		})
+	}
+}
+
+func dump(t *testing.T, req tcHttp.Request) string {
+	t.Helper()
+	b, err := tcHttp.EncodeJSON(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
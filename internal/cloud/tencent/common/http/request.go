@@ -1,11 +1,9 @@
 package http
 
 import (
-	"github.com/eadydb/k8s-aim/pkg/zlog"
 	"io"
 	"math/rand"
 	"net/url"
-	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -213,79 +211,14 @@ func CompleteCommonParams(request Request, region string) {
 	params["Nonce"] = strconv.Itoa(rand.Int())
 }
 
-func ConstructParams(req Request) (err error) {
-	value := reflect.ValueOf(req).Elem()
-	err = flatStructure(value, req, "")
-	zlog.Debugf("[DEBUG] params=%s", req.GetParams())
-	return
-}
-
-func flatStructure(value reflect.Value, request Request, prefix string) (err error) {
-	zlog.Debugf("[DEBUG] reflect value: %v", value.Type())
-	valueType := value.Type()
-	for i := 0; i < valueType.NumField(); i++ {
-		tag := valueType.Field(i).Tag
-		nameTag, hasNameTag := tag.Lookup("name")
-		if !hasNameTag {
-			continue
-		}
-		field := value.Field(i)
-		kind := field.Kind()
-		if kind == reflect.Ptr && field.IsNil() {
-			continue
-		}
-		if kind == reflect.Ptr {
-			field = field.Elem()
-			kind = field.Kind()
-		}
-		key := prefix + nameTag
-		if kind == reflect.String {
-			s := field.String()
-			if s != "" {
-				request.GetParams()[key] = s
-			}
-		} else if kind == reflect.Bool {
-			request.GetParams()[key] = strconv.FormatBool(field.Bool())
-		} else if kind == reflect.Int || kind == reflect.Int64 {
-			request.GetParams()[key] = strconv.FormatInt(field.Int(), 10)
-		} else if kind == reflect.Uint || kind == reflect.Uint64 {
-			request.GetParams()[key] = strconv.FormatUint(field.Uint(), 10)
-		} else if kind == reflect.Float64 {
-			request.GetParams()[key] = strconv.FormatFloat(field.Float(), 'f', -1, 64)
-		} else if kind == reflect.Slice {
-			list := value.Field(i)
-			for j := 0; j < list.Len(); j++ {
-				vj := list.Index(j)
-				key := prefix + nameTag + "." + strconv.Itoa(j)
-				kind = vj.Kind()
-				if kind == reflect.Ptr && vj.IsNil() {
-					continue
-				}
-				if kind == reflect.Ptr {
-					vj = vj.Elem()
-					kind = vj.Kind()
-				}
-				if kind == reflect.String {
-					request.GetParams()[key] = vj.String()
-				} else if kind == reflect.Bool {
-					request.GetParams()[key] = strconv.FormatBool(vj.Bool())
-				} else if kind == reflect.Int || kind == reflect.Int64 {
-					request.GetParams()[key] = strconv.FormatInt(vj.Int(), 10)
-				} else if kind == reflect.Uint || kind == reflect.Uint64 {
-					request.GetParams()[key] = strconv.FormatUint(vj.Uint(), 10)
-				} else if kind == reflect.Float64 {
-					request.GetParams()[key] = strconv.FormatFloat(vj.Float(), 'f', -1, 64)
-				} else {
-					if err = flatStructure(vj, request, key+"."); err != nil {
-						return
-					}
-				}
-			}
-		} else {
-			if err = flatStructure(reflect.ValueOf(field.Interface()), request, prefix+nameTag+"."); err != nil {
-				return
-			}
-		}
+// ConstructParams 按 name 标签将请求字段编码到请求参数
+func ConstructParams(req Request) error {
+	params, err := EncodeParams(req)
+	if err != nil {
+		return err
 	}
-	return
+	for key, value := range params {
+		req.GetParams()[key] = value
+	}
+	return nil
 }
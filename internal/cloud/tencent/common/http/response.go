@@ -10,9 +10,36 @@ import (
 
 type Response interface {
 	ParseErrorFromHTTPResponse(body []byte) error
+	GetRequestId() string // 唯一请求ID, 解析响应后可用
+	GetHttpStatus() int   // http 状态码, 解析响应后可用
+	setHttpStatus(status int)
 }
 
 type BaseResponse struct {
+	requestId  string // 唯一请求ID
+	httpStatus int    // http 状态码
+}
+
+// GetRequestId 唯一请求ID, 向腾讯云提交工单时需提供
+func (r *BaseResponse) GetRequestId() string {
+	if r == nil {
+		return ""
+	}
+	return r.requestId
+}
+
+// GetHttpStatus http 状态码
+func (r *BaseResponse) GetHttpStatus() int {
+	if r == nil {
+		return 0
+	}
+	return r.httpStatus
+}
+
+func (r *BaseResponse) setHttpStatus(status int) {
+	if r != nil {
+		r.httpStatus = status
+	}
 }
 
 type ErrorResponse struct {
@@ -38,6 +65,9 @@ func (r *BaseResponse) ParseErrorFromHTTPResponse(body []byte) (err error) {
 		msg := fmt.Sprintf("Fail to parse json content: %s, because: %s", body, err)
 		return errors2.NewTencentCloudSDKError("ClientError.ParseJsonError", msg, "")
 	}
+	if r != nil {
+		r.requestId = resp.Response.RequestId
+	}
 	if resp.Response.Error.Code != "" {
 		return errors2.NewTencentCloudSDKError(resp.Response.Error.Code, resp.Response.Error.Message, resp.Response.RequestId)
 	}
@@ -56,14 +86,14 @@ func (r *BaseResponse) ParseErrorFromHTTPResponse(body []byte) (err error) {
 
 func ParseFromHttpResponse(hr *http.Response, response Response) (err error) {
 	defer hr.Body.Close()
+	response.setHttpStatus(hr.StatusCode)
 	body, err := ioutil.ReadAll(hr.Body)
 	if err != nil {
-		msg := fmt.Sprintf("Fail to read response body because %s", err)
-		return errors2.NewTencentCloudSDKError("ClientError.IOError", msg, "")
+		return errors2.WrapError("ClientError.IOError", "Fail to read response body", err)
 	}
 	if hr.StatusCode != 200 {
 		msg := fmt.Sprintf("Request fail with http status code: %s, with body: %s", hr.Status, body)
-		return errors2.NewTencentCloudSDKError("ClientError.HttpStatusCodeError", msg, "")
+		return errors2.NewHttpStatusError(hr.StatusCode, msg)
 	}
 	//log.Printf("[DEBUG] Response Body=%s", body)
 	err = response.ParseErrorFromHTTPResponse(body)
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 竞价实例回收时间格式, 北京时间, 如 2018-08-18 12:05:33
+const terminationTimeLayout = "2006-01-02 15:04:05"
+
+var beijing = time.FixedZone("CST", 8*3600)
+
+// MetadataClient 实例元数据客户端, 元数据服务只能在 CVM 实例内访问
+type MetadataClient struct {
+	Endpoint   string // 元数据服务地址, 默认 http://metadata.tencentyun.com, 可指向本地模拟服务
+	httpClient *http.Client
+}
+
+// NewMetadataClient 实例化, endpoint 为空时使用 DefaultMetadataEndpoint
+func NewMetadataClient(endpoint string) *MetadataClient {
+	if endpoint == "" {
+		endpoint = DefaultMetadataEndpoint
+	}
+	return &MetadataClient{Endpoint: endpoint, httpClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// InstanceId 当前实例ID
+func (c *MetadataClient) InstanceId(ctx context.Context) (string, error) {
+	value, _, err := c.get(ctx, "instance-id")
+	return value, err
+}
+
+// TerminationTime 竞价实例的预计回收时间, 未收到回收通知时 ok 为 false
+func (c *MetadataClient) TerminationTime(ctx context.Context) (t time.Time, ok bool, err error) {
+	value, found, err := c.get(ctx, "spot/termination-time")
+	if err != nil || !found {
+		return time.Time{}, false, err
+	}
+	if t, err = time.ParseInLocation(terminationTimeLayout, value, beijing); err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid spot termination time %q, %s", value, err)
+	}
+	return t, true, nil
+}
+
+// get 读取元数据, 不存在时 found 为 false
+func (c *MetadataClient) get(ctx context.Context, name string) (value string, found bool, err error) {
+	url := strings.TrimRight(c.Endpoint, "/") + "/latest/meta-data/" + name
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", false, err
+	}
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return strings.TrimSpace(string(body)), true, nil
+	case http.StatusNotFound:
+		return "", false, nil
+	}
+	return "", false, fmt.Errorf("request %s failed with http status: %s", url, resp.Status)
+}
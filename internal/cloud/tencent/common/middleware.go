@@ -0,0 +1,138 @@
+package common
+
+import (
+	"context"
+	stderrors "errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+	"github.com/eadydb/k8s-aim/pkg/zlog"
+	"strconv"
+	"time"
+)
+
+// Invocation 一次接口调用, 重试时每次尝试各为一次调用
+type Invocation struct {
+	Service  string          // 服务, 如 cvm、vpc
+	Version  string          // 接口版本
+	Action   string          // 接口名称
+	Region   string          // 地域
+	Attempt  int             // 第几次尝试, 从1开始
+	Request  tcHttp.Request  // 请求
+	Response tcHttp.Response // 响应, 调用成功后可读取 RequestId 及 http 状态码
+}
+
+// SendFunc 发送一次请求
+type SendFunc func(ctx context.Context, inv *Invocation) error
+
+// Middleware 包装 SendFunc, 在请求前后执行日志、追踪等逻辑, 类似 http.RoundTripper
+type Middleware func(next SendFunc) SendFunc
+
+// Use 追加中间件, 先添加的在外层
+func (c *Client) Use(middlewares ...Middleware) *Client {
+	c.middlewares = append(c.middlewares, middlewares...)
+	return c
+}
+
+// invoke 经过中间件发送一次请求
+func (c *Client) invoke(ctx context.Context, request tcHttp.Request, response tcHttp.Response, attempt int) error {
+	inv := &Invocation{
+		Service:  request.GetService(),
+		Version:  request.GetVersion(),
+		Action:   request.GetAction(),
+		Region:   c.GetRegion(),
+		Attempt:  attempt,
+		Request:  request,
+		Response: response,
+	}
+	send := func(ctx context.Context, inv *Invocation) error {
+		return c.annotate(c.send(ctx, inv.Request, inv.Response), inv.Request)
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		send = c.middlewares[i](send)
+	}
+	return send(ctx, inv)
+}
+
+// LoggingMiddleware 以结构化字段记录每次调用的接口、地域、耗时、RequestId、http 状态码及错误码,
+// 成功时为 Debug 级别, 失败时为 Warn 级别
+func LoggingMiddleware() Middleware {
+	return func(next SendFunc) SendFunc {
+		return func(ctx context.Context, inv *Invocation) error {
+			start := time.Now()
+			err := next(ctx, inv)
+			fields := append([]interface{}{"latency", time.Since(start).String()}, invocationFields(inv, err)...)
+			if err != nil {
+				zlog.Warnw("tencentcloud api call failed", append(fields, "error", err.Error())...)
+			} else {
+				zlog.Debugw("tencentcloud api call", fields...)
+			}
+			return err
+		}
+	}
+}
+
+// Tracer 链路追踪, 由调用方适配所用的追踪实现, 为每次调用创建一个 span
+type Tracer interface {
+	// Start 创建 span, 返回的 ctx 会用于发送请求
+	Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+}
+
+// Span 一次调用的追踪
+type Span interface {
+	// End 结束 span, attributes 为调用结果, 调用失败时 err 非空
+	End(attributes map[string]string, err error)
+}
+
+// TracingMiddleware 为每次调用创建名称为 tencentcloud.<service>/<action> 的 span
+func TracingMiddleware(tracer Tracer) Middleware {
+	return func(next SendFunc) SendFunc {
+		return func(ctx context.Context, inv *Invocation) error {
+			ctx, span := tracer.Start(ctx, "tencentcloud."+inv.Service+"/"+inv.Action, map[string]string{
+				"tencentcloud.service": inv.Service,
+				"tencentcloud.version": inv.Version,
+				"tencentcloud.action":  inv.Action,
+				"tencentcloud.region":  inv.Region,
+				"tencentcloud.attempt": strconv.Itoa(inv.Attempt),
+			})
+			err := next(ctx, inv)
+			fields := invocationFields(inv, err)
+			attributes := make(map[string]string, len(fields)/2)
+			for i := 0; i+1 < len(fields); i += 2 {
+				attributes["tencentcloud."+fields[i].(string)] = fields[i+1].(string)
+			}
+			span.End(attributes, err)
+			return err
+		}
+	}
+}
+
+// invocationFields 调用的结构化字段, 失败时 RequestId 及 http 状态码取自错误
+func invocationFields(inv *Invocation, err error) []interface{} {
+	fields := []interface{}{
+		"service", inv.Service,
+		"action", inv.Action,
+		"region", inv.Region,
+		"attempt", strconv.Itoa(inv.Attempt),
+	}
+	requestId, status := "", 0
+	if inv.Response != nil {
+		requestId, status = inv.Response.GetRequestId(), inv.Response.GetHttpStatus()
+	}
+	var sdkErr *errors.TencentCloudSDKError
+	if stderrors.As(err, &sdkErr) {
+		if sdkErr.RequestId != "" {
+			requestId = sdkErr.RequestId
+		}
+		if sdkErr.HttpStatus != 0 {
+			status = sdkErr.HttpStatus
+		}
+		fields = append(fields, "error_code", sdkErr.Code, "error_category", string(sdkErr.Category()))
+	}
+	if requestId != "" {
+		fields = append(fields, "request_id", requestId)
+	}
+	if status != 0 {
+		fields = append(fields, "http_status", strconv.Itoa(status))
+	}
+	return fields
+}
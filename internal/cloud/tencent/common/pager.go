@@ -0,0 +1,95 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultPageLimit 默认每页数量, Describe* 接口单页最多返回100条
+const DefaultPageLimit = 100
+
+// ErrTooManyItems 分页查询的数据超出 Pager.MaxItems
+var ErrTooManyItems = errors.New("pager: too many items")
+
+// PageFunc 查询 offset 起最多 limit 条数据并自行保存, 返回本页条数及总数, 接口不返回总数时 total 为 -1
+type PageFunc func(ctx context.Context, offset, limit int64) (count int, total int64, err error)
+
+// Pager Offset/Limit 分页迭代器, 按页调用 PageFunc 直至取完全部数据
+//
+//	var instances []*cvm.Instance
+//	pager := common.NewPager(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+//		...
+//		instances = append(instances, resp.Response.InstanceSet...)
+//		return len(resp.Response.InstanceSet), resp.Response.TotalCount, nil
+//	})
+//	err := pager.All(ctx)
+type Pager struct {
+	Limit    int64 // 每页数量, 为0时使用 DefaultPageLimit
+	MaxItems int64 // 最多查询条数, 超出时返回 ErrTooManyItems, 为0时不限
+
+	fetch  PageFunc
+	offset int64
+	done   bool
+	err    error
+}
+
+// NewPager 实例化
+func NewPager(fetch PageFunc) *Pager {
+	return &Pager{fetch: fetch}
+}
+
+// Next 查询下一页, 已取完全部数据或出错时返回 false
+func (p *Pager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		return p.fail(err)
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	count, total, err := p.fetch(ctx, p.offset, limit)
+	if err != nil {
+		return p.fail(err)
+	}
+	if count == 0 {
+		p.done = true
+		return false
+	}
+	p.offset += int64(count)
+	if p.MaxItems > 0 && (p.offset > p.MaxItems || total > p.MaxItems) {
+		return p.fail(fmt.Errorf("%w: more than %d", ErrTooManyItems, p.MaxItems))
+	}
+	// 接口不返回总数时以不满一页作为结束
+	if total >= 0 {
+		p.done = p.offset >= total
+	} else {
+		p.done = int64(count) < limit
+	}
+	return true
+}
+
+// Err 查询失败的错误, 正常结束时为 nil
+func (p *Pager) Err() error {
+	return p.err
+}
+
+// Offset 已查询的条数
+func (p *Pager) Offset() int64 {
+	return p.offset
+}
+
+// All 查询全部分页
+func (p *Pager) All(ctx context.Context) error {
+	for p.Next(ctx) {
+	}
+	return p.Err()
+}
+
+func (p *Pager) fail(err error) bool {
+	p.done, p.err = true, err
+	return false
+}
@@ -0,0 +1,131 @@
+package common_test
+
+import (
+	"context"
+	"errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	"reflect"
+	"testing"
+)
+
+// page 一次分页查询的参数
+type page struct {
+	offset, limit int64
+}
+
+// source 共 n 条数据的分页查询, withTotal 为 false 时不返回总数, 记录每次查询
+func source(n int64, withTotal bool, pages *[]page) common.PageFunc {
+	return func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		*pages = append(*pages, page{offset, limit})
+		count := n - offset
+		if count > limit {
+			count = limit
+		}
+		if count < 0 {
+			count = 0
+		}
+		total := n
+		if !withTotal {
+			total = -1
+		}
+		return int(count), total, nil
+	}
+}
+
+func TestPager(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int64
+		withTotal bool
+		limit     int64
+		pages     []page
+	}{
+		{"last partial page", 25, true, 10, []page{{0, 10}, {10, 10}, {20, 10}}},
+		{"stops at total count", 20, true, 10, []page{{0, 10}, {10, 10}}},
+		{"partial page without total", 25, false, 10, []page{{0, 10}, {10, 10}, {20, 10}}},
+		{"empty page without total", 20, false, 10, []page{{0, 10}, {10, 10}, {20, 10}}},
+		{"no items", 0, true, 10, []page{{0, 10}}},
+		{"default limit", 150, true, 0, []page{{0, common.DefaultPageLimit}, {100, common.DefaultPageLimit}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages []page
+			pager := common.NewPager(source(tt.n, tt.withTotal, &pages))
+			pager.Limit = tt.limit
+			if err := pager.All(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(pages, tt.pages) {
+				t.Fatalf("pages = %v, want %v", pages, tt.pages)
+			}
+			if pager.Offset() != tt.n {
+				t.Fatalf("offset = %d, want %d", pager.Offset(), tt.n)
+			}
+			if pager.Next(context.Background()) {
+				t.Fatal("Next after the last page returned true")
+			}
+		})
+	}
+}
+
+func TestPagerMaxItems(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int64
+		withTotal bool
+		maxItems  int64
+		pages     int
+		err       error
+	}{
+		{"within limit", 20, true, 20, 2, nil},
+		{"total exceeds limit", 25, true, 20, 1, common.ErrTooManyItems},
+		{"items exceed limit without total", 25, false, 20, 3, common.ErrTooManyItems},
+		{"unlimited", 25, true, 0, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages []page
+			pager := common.NewPager(source(tt.n, tt.withTotal, &pages))
+			pager.Limit = 10
+			pager.MaxItems = tt.maxItems
+			err := pager.All(context.Background())
+			if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if len(pages) != tt.pages {
+				t.Fatalf("queried %d pages, want %d", len(pages), tt.pages)
+			}
+		})
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	failure := errors.New("request failed")
+	calls := 0
+	pager := common.NewPager(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		calls++
+		if offset > 0 {
+			return 0, 0, failure
+		}
+		return int(limit), -1, nil
+	})
+	pager.Limit = 10
+	if err := pager.All(context.Background()); !errors.Is(err, failure) {
+		t.Fatalf("err = %v, want %v", err, failure)
+	}
+	if calls != 2 || pager.Offset() != 10 {
+		t.Fatalf("calls = %d, offset = %d, want to stop at the failed page", calls, pager.Offset())
+	}
+
+	// ctx 结束后不再查询
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	pager = common.NewPager(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		calls++
+		return int(limit), -1, nil
+	})
+	if err := pager.All(ctx); !errors.Is(err, context.Canceled) || calls != 0 {
+		t.Fatalf("err = %v, calls = %d, want Canceled without query", err, calls)
+	}
+}
@@ -1,7 +1,9 @@
 package profile
 
 type ClientProfile struct {
-	HttpProfile *HttpProfile
+	HttpProfile      *HttpProfile
+	RetryProfile     *RetryProfile
+	RateLimitProfile *RateLimitProfile
 
 	// Valid choices: HmacSHA1, HmacSHA256, TC3-HMAC-SHA256.
 	// Default value is TC3-HMAC-SHA256.
@@ -15,10 +17,12 @@ type ClientProfile struct {
 
 func NewClientProfile() *ClientProfile {
 	return &ClientProfile{
-		HttpProfile:     NewHttpProfile(),
-		SignMethod:      "TC3-HMAC-SHA256",
-		UnsignedPayload: false,
-		Language:        "zh-CN",
-		Debug:           false,
+		HttpProfile:      NewHttpProfile(),
+		RetryProfile:     NewRetryProfile(),
+		RateLimitProfile: NewRateLimitProfile(),
+		SignMethod:       "TC3-HMAC-SHA256",
+		UnsignedPayload:  false,
+		Language:         "zh-CN",
+		Debug:            false,
 	}
 }
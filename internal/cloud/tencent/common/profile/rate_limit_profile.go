@@ -0,0 +1,35 @@
+package profile
+
+import "time"
+
+// RateLimit 令牌桶限流参数
+type RateLimit struct {
+	QPS   float64 // 每秒请求数, 小于等于0表示不限流
+	Burst int     // 突发请求数
+}
+
+// RateLimitProfile 客户端限流设置, 按 service/action 分别限流
+type RateLimitProfile struct {
+	Default   RateLimit            // 默认限流参数
+	Overrides map[string]RateLimit // 按接口覆盖的限流参数, key 为 service/action, 如 cvm/RunInstances
+	MaxWait   time.Duration        // 单次请求最长排队时间, 小于等于0表示不限制
+}
+
+func NewRateLimitProfile() *RateLimitProfile {
+	return &RateLimitProfile{
+		Default: RateLimit{QPS: 20, Burst: 20},
+		Overrides: map[string]RateLimit{
+			"cvm/RunInstances":       {QPS: 10, Burst: 10},
+			"cvm/TerminateInstances": {QPS: 10, Burst: 10},
+		},
+		MaxWait: time.Minute,
+	}
+}
+
+// Limit 获取 service/action 对应的限流参数
+func (p *RateLimitProfile) Limit(key string) RateLimit {
+	if limit, ok := p.Overrides[key]; ok {
+		return limit
+	}
+	return p.Default
+}
@@ -0,0 +1,34 @@
+package profile
+
+import (
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"time"
+)
+
+// RetryProfile 请求重试设置
+type RetryProfile struct {
+	MaxAttempts         int               // 最大尝试次数(包含首次请求), 小于等于1表示不重试
+	BaseDelay           time.Duration     // 首次重试等待时间, 之后按指数递增
+	MaxDelay            time.Duration     // 单次重试最大等待时间
+	Jitter              float64           // 抖动比例 0~1, 等待时间会在 [delay*(1-Jitter), delay] 内随机
+	RetryableCodes      []string          // 可重试的错误码, 同时匹配其子错误码, 如 RequestLimitExceeded 匹配 RequestLimitExceeded.UinLimitExceeded
+	RetryHttp5xx        bool              // http 状态码为 5xx 时是否重试
+	RetryableCategories []errors.Category // 可重试的错误类别
+}
+
+func NewRetryProfile() *RetryProfile {
+	return &RetryProfile{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+		RetryableCodes: []string{
+			"ClientError.NetworkError",
+			"RequestLimitExceeded",
+			"InternalError",
+			"InternalServerError",
+		},
+		RetryHttp5xx:        true,
+		RetryableCategories: []errors.Category{errors.CategoryThrottled, errors.CategoryNetwork},
+	}
+}
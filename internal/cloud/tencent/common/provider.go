@@ -0,0 +1,357 @@
+package common
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/profile"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EnvSecretId        = "TENCENTCLOUD_SECRET_ID"
+	EnvSecretKey       = "TENCENTCLOUD_SECRET_KEY"
+	EnvSessionToken    = "TENCENTCLOUD_SESSION_TOKEN"
+	EnvCredentialsFile = "TENCENTCLOUD_CREDENTIALS_FILE"
+	EnvProfile         = "TENCENTCLOUD_PROFILE"
+
+	DefaultMetadataEndpoint = "http://metadata.tencentyun.com"
+	DefaultStsEndpoint      = "sts.tencentcloudapi.com"
+	DefaultRefreshBefore    = 5 * time.Minute
+)
+
+// EnvProvider 从环境变量获取凭证
+type EnvProvider struct{}
+
+// NewEnvProvider 实例化
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{}
+}
+
+// GetCredential 读取 TENCENTCLOUD_SECRET_ID、TENCENTCLOUD_SECRET_KEY、TENCENTCLOUD_SESSION_TOKEN
+func (p *EnvProvider) GetCredential() (*Credential, error) {
+	secretId, secretKey := os.Getenv(EnvSecretId), os.Getenv(EnvSecretKey)
+	if secretId == "" || secretKey == "" {
+		return nil, fmt.Errorf("environment variable %s or %s is empty", EnvSecretId, EnvSecretKey)
+	}
+	return NewTokenCredential(secretId, secretKey, os.Getenv(EnvSessionToken)), nil
+}
+
+// ProfileProvider 从凭证文件获取凭证, 文件格式:
+//
+//	[default]
+//	secret_id = xxx
+//	secret_key = xxx
+type ProfileProvider struct {
+	File    string // 凭证文件路径, 为空时读取 TENCENTCLOUD_CREDENTIALS_FILE 或 ~/.tencentcloud/credentials
+	Profile string // 配置名称, 为空时读取 TENCENTCLOUD_PROFILE 或 default
+}
+
+// NewProfileProvider 实例化
+func NewProfileProvider(file, profile string) *ProfileProvider {
+	return &ProfileProvider{File: file, Profile: profile}
+}
+
+// GetCredential 读取凭证文件中对应配置的凭证
+func (p *ProfileProvider) GetCredential() (*Credential, error) {
+	file := p.File
+	if file == "" {
+		file = os.Getenv(EnvCredentialsFile)
+	}
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		file = filepath.Join(home, ".tencentcloud", "credentials")
+	}
+	name := p.Profile
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = "default"
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section != name {
+			continue
+		}
+		if i := strings.Index(line, "="); i > 0 {
+			values[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if values["secret_id"] == "" || values["secret_key"] == "" {
+		return nil, fmt.Errorf("secret_id or secret_key of profile %s is empty in %s", name, file)
+	}
+	return NewTokenCredential(values["secret_id"], values["secret_key"], values["token"]), nil
+}
+
+// refreshableCredential 带过期时间的临时凭证缓存
+type refreshableCredential struct {
+	mu            sync.Mutex
+	credential    *Credential
+	expiredTime   time.Time
+	refreshBefore time.Duration
+}
+
+// get 凭证未临近过期时返回缓存, 否则调用 fetch 刷新
+func (r *refreshableCredential) get(fetch func() (*Credential, time.Time, error)) (*Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	refreshBefore := r.refreshBefore
+	if refreshBefore <= 0 {
+		refreshBefore = DefaultRefreshBefore
+	}
+	if r.credential != nil && time.Now().Add(refreshBefore).Before(r.expiredTime) {
+		return r.credential, nil
+	}
+	credential, expiredTime, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	r.credential, r.expiredTime = credential, expiredTime
+	return credential, nil
+}
+
+// CvmRoleProvider 从 CVM 实例元数据获取角色临时凭证
+type CvmRoleProvider struct {
+	Endpoint      string        // 元数据服务地址, 默认 http://metadata.tencentyun.com
+	RoleName      string        // 角色名称, 为空时从元数据服务查询实例绑定的角色
+	RefreshBefore time.Duration // 过期前多久刷新
+	httpClient    *http.Client
+	cache         refreshableCredential
+}
+
+// NewCvmRoleProvider 实例化
+func NewCvmRoleProvider(roleName string) *CvmRoleProvider {
+	return &CvmRoleProvider{
+		Endpoint:   DefaultMetadataEndpoint,
+		RoleName:   roleName,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// GetCredential 获取角色临时凭证, 过期前自动刷新
+func (p *CvmRoleProvider) GetCredential() (*Credential, error) {
+	return p.GetCredentialWithContext(context.Background())
+}
+
+// GetCredentialWithContext 获取角色临时凭证, 过期前自动刷新, ctx 取消时终止请求元数据服务
+func (p *CvmRoleProvider) GetCredentialWithContext(ctx context.Context) (*Credential, error) {
+	p.cache.refreshBefore = p.RefreshBefore
+	return p.cache.get(func() (*Credential, time.Time, error) {
+		return p.fetch(ctx)
+	})
+}
+
+// fetch 请求元数据服务
+func (p *CvmRoleProvider) fetch(ctx context.Context) (*Credential, time.Time, error) {
+	base := strings.TrimRight(p.Endpoint, "/") + "/latest/meta-data/cam/security-credentials/"
+	roleName := p.RoleName
+	if roleName == "" {
+		body, err := p.get(ctx, base)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		roleName = strings.TrimSpace(strings.SplitN(string(body), "\n", 2)[0])
+		if roleName == "" {
+			return nil, time.Time{}, fmt.Errorf("no cam role is bound to this instance")
+		}
+	}
+	body, err := p.get(ctx, base+roleName)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	resp := &struct {
+		TmpSecretId  string `json:"TmpSecretId"`
+		TmpSecretKey string `json:"TmpSecretKey"`
+		Token        string `json:"Token"`
+		ExpiredTime  int64  `json:"ExpiredTime"`
+		Code         string `json:"Code"`
+	}{}
+	if err = json.Unmarshal(body, resp); err != nil {
+		return nil, time.Time{}, fmt.Errorf("parse role credential failed, %s", err)
+	}
+	if resp.Code != "" && resp.Code != "Success" {
+		return nil, time.Time{}, fmt.Errorf("get role credential failed, code: %s", resp.Code)
+	}
+	return NewTokenCredential(resp.TmpSecretId, resp.TmpSecretKey, resp.Token), time.Unix(resp.ExpiredTime, 0), nil
+}
+
+func (p *CvmRoleProvider) get(ctx context.Context, url string) ([]byte, error) {
+	httpClient := p.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request %s failed with http status: %s", url, resp.Status)
+	}
+	return body, nil
+}
+
+// AssumeRoleRequest STS 申请扮演角色请求参数
+type AssumeRoleRequest struct {
+	*tcHttp.BaseRequest
+	RoleArn         *string `json:"RoleArn,omitempty" name:"RoleArn"`                 // 角色资源描述
+	RoleSessionName *string `json:"RoleSessionName,omitempty" name:"RoleSessionName"` // 临时会话名称
+	DurationSeconds *uint64 `json:"DurationSeconds,omitempty" name:"DurationSeconds"` // 临时凭证有效期, 单位秒
+}
+
+// AssumeRoleResponse STS 申请扮演角色响应结果
+type AssumeRoleResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		Credentials *struct {
+			Token        string `json:"Token"`
+			TmpSecretId  string `json:"TmpSecretId"`
+			TmpSecretKey string `json:"TmpSecretKey"`
+		} `json:"Credentials"`
+		ExpiredTime int64  `json:"ExpiredTime"` // 凭证过期时间, Unix 时间戳
+		Expiration  string `json:"Expiration"`  // 凭证过期时间, ISO8601 格式
+		RequestId   string `json:"RequestId"`
+	} `json:"Response"`
+}
+
+// NewAssumeRoleRequest 实例化
+func NewAssumeRoleRequest() *AssumeRoleRequest {
+	req := &AssumeRoleRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("sts", "2018-08-13", "AssumeRole")
+	return req
+}
+
+// StsRoleProvider 通过 STS AssumeRole 获取角色临时凭证
+type StsRoleProvider struct {
+	Source          CredentialProvider // 用于调用 STS 的源凭证
+	Region          string             // 地域
+	Endpoint        string             // STS 服务地址, 默认 sts.tencentcloudapi.com
+	Scheme          string             // HTTP/HTTPS, 默认 HTTPS
+	RoleArn         string             // 角色资源描述
+	RoleSessionName string             // 临时会话名称
+	DurationSeconds uint64             // 临时凭证有效期, 单位秒
+	RefreshBefore   time.Duration      // 过期前多久刷新
+	cache           refreshableCredential
+}
+
+// NewStsRoleProvider 实例化
+func NewStsRoleProvider(source CredentialProvider, region, roleArn, roleSessionName string) *StsRoleProvider {
+	return &StsRoleProvider{
+		Source:          source,
+		Region:          region,
+		Endpoint:        DefaultStsEndpoint,
+		RoleArn:         roleArn,
+		RoleSessionName: roleSessionName,
+		DurationSeconds: 7200,
+	}
+}
+
+// GetCredential 获取角色临时凭证, 过期前自动刷新
+func (p *StsRoleProvider) GetCredential() (*Credential, error) {
+	return p.GetCredentialWithContext(context.Background())
+}
+
+// GetCredentialWithContext 获取角色临时凭证, 过期前自动刷新, ctx 取消时终止调用 STS
+func (p *StsRoleProvider) GetCredentialWithContext(ctx context.Context) (*Credential, error) {
+	p.cache.refreshBefore = p.RefreshBefore
+	return p.cache.get(func() (*Credential, time.Time, error) {
+		return p.fetch(ctx)
+	})
+}
+
+// fetch 调用 STS AssumeRole
+func (p *StsRoleProvider) fetch(ctx context.Context) (*Credential, time.Time, error) {
+	clientProfile := profile.NewClientProfile()
+	clientProfile.HttpProfile.Endpoint = p.Endpoint
+	if p.Scheme != "" {
+		clientProfile.HttpProfile.Scheme = p.Scheme
+	}
+	client, _ := NewClientWithProvider(p.Source, p.Region)
+	client.WithProfile(clientProfile)
+
+	req := NewAssumeRoleRequest()
+	req.RoleArn = &p.RoleArn
+	req.RoleSessionName = &p.RoleSessionName
+	req.DurationSeconds = &p.DurationSeconds
+	resp := &AssumeRoleResponse{BaseResponse: &tcHttp.BaseResponse{}}
+	if err := client.SendWithContext(ctx, req, resp); err != nil {
+		return nil, time.Time{}, err
+	}
+	if resp.Response == nil || resp.Response.Credentials == nil {
+		return nil, time.Time{}, fmt.Errorf("assume role %s returned empty credentials", p.RoleArn)
+	}
+	c := resp.Response.Credentials
+	return NewTokenCredential(c.TmpSecretId, c.TmpSecretKey, c.Token), time.Unix(resp.Response.ExpiredTime, 0), nil
+}
+
+// ProviderChain 凭证提供者链, 依次尝试直至获取成功
+type ProviderChain struct {
+	Providers []CredentialProvider
+}
+
+// NewProviderChain 实例化
+func NewProviderChain(providers ...CredentialProvider) *ProviderChain {
+	return &ProviderChain{Providers: providers}
+}
+
+// NewDefaultProviderChain 默认凭证提供者链: 环境变量、凭证文件、CVM 实例角色
+func NewDefaultProviderChain() *ProviderChain {
+	return NewProviderChain(NewEnvProvider(), NewProfileProvider("", ""), NewCvmRoleProvider(""))
+}
+
+// GetCredential 返回第一个获取成功的凭证
+func (c *ProviderChain) GetCredential() (*Credential, error) {
+	return c.GetCredentialWithContext(context.Background())
+}
+
+// GetCredentialWithContext 返回第一个获取成功的凭证, ctx 传给支持的提供者
+func (c *ProviderChain) GetCredentialWithContext(ctx context.Context) (*Credential, error) {
+	var messages []string
+	for _, provider := range c.Providers {
+		credential, err := getCredential(ctx, provider)
+		if err == nil {
+			return credential, nil
+		}
+		messages = append(messages, fmt.Sprintf("%T: %s", provider, err))
+	}
+	return nil, fmt.Errorf("no valid credential in provider chain: [%s]", strings.Join(messages, "; "))
+}
@@ -0,0 +1,228 @@
+package common_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/emulator"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setenv 设置环境变量, 测试结束后恢复
+func setenv(t *testing.T, key, value string) {
+	t.Helper()
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// metadataServer 返回角色临时凭证的元数据服务, 凭证在 ttl 后过期, 返回服务及凭证请求次数
+func metadataServer(t *testing.T, role string, ttl time.Duration) (*httptest.Server, *int32) {
+	t.Helper()
+	var fetched int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/meta-data/cam/security-credentials/":
+			fmt.Fprintln(w, role)
+		case "/latest/meta-data/cam/security-credentials/" + role:
+			n := atomic.AddInt32(&fetched, 1)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"TmpSecretId":  fmt.Sprintf("tmp-id-%d", n),
+				"TmpSecretKey": "tmp-key",
+				"Token":        "tmp-token",
+				"ExpiredTime":  time.Now().Add(ttl).Unix(),
+				"Code":         "Success",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s, &fetched
+}
+
+func TestEnvProvider(t *testing.T) {
+	setenv(t, common.EnvSecretId, "env-id")
+	setenv(t, common.EnvSecretKey, "env-key")
+	setenv(t, common.EnvSessionToken, "env-token")
+	credential, err := common.NewEnvProvider().GetCredential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *credential != (common.Credential{SecretId: "env-id", SecretKey: "env-key", Token: "env-token"}) {
+		t.Fatalf("credential = %+v", credential)
+	}
+	setenv(t, common.EnvSecretKey, "")
+	if _, err := common.NewEnvProvider().GetCredential(); err == nil {
+		t.Fatal("expected error without secret key")
+	}
+}
+
+func TestProfileProvider(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials")
+	content := "[default]\nsecret_id = default-id\nsecret_key = default-key\n\n# comment\n[prod]\nsecret_id = prod-id\nsecret_key = prod-key\ntoken = prod-token\n"
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	setenv(t, common.EnvProfile, "")
+	credential, err := common.NewProfileProvider(file, "").GetCredential()
+	if err != nil || credential.SecretId != "default-id" || credential.SecretKey != "default-key" {
+		t.Fatalf("default profile = %+v, %v", credential, err)
+	}
+	credential, err = common.NewProfileProvider(file, "prod").GetCredential()
+	if err != nil || credential.SecretId != "prod-id" || credential.Token != "prod-token" {
+		t.Fatalf("prod profile = %+v, %v", credential, err)
+	}
+	setenv(t, common.EnvCredentialsFile, file)
+	setenv(t, common.EnvProfile, "prod")
+	if credential, err = common.NewProfileProvider("", "").GetCredential(); err != nil || credential.SecretId != "prod-id" {
+		t.Fatalf("profile from environment = %+v, %v", credential, err)
+	}
+	if _, err := common.NewProfileProvider(file, "missing").GetCredential(); err == nil {
+		t.Fatal("expected error for missing profile")
+	}
+}
+
+func TestCvmRoleProviderRefreshesBeforeExpiry(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		fetched int32
+	}{
+		{"cached until 5m before expiry", time.Hour, 1},
+		{"refreshed within 5m of expiry", 4 * time.Minute, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, fetched := metadataServer(t, "node-role", tt.ttl)
+			provider := common.NewCvmRoleProvider("")
+			provider.Endpoint = s.URL
+			for i := 0; i < 2; i++ {
+				credential, err := provider.GetCredential()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if credential.SecretKey != "tmp-key" || credential.Token != "tmp-token" {
+					t.Fatalf("credential = %+v", credential)
+				}
+			}
+			if n := atomic.LoadInt32(fetched); n != tt.fetched {
+				t.Fatalf("fetched %d times, want %d", n, tt.fetched)
+			}
+		})
+	}
+}
+
+func TestStsRoleProvider(t *testing.T) {
+	s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+	defer s.Close()
+	expired := time.Now().Add(2 * time.Hour).Unix()
+	s.Handle("AssumeRole", func(req *emulator.Request) (interface{}, error) {
+		var params struct {
+			RoleArn         string `json:"RoleArn"`
+			RoleSessionName string `json:"RoleSessionName"`
+		}
+		if err := req.Decode(&params); err != nil {
+			return nil, err
+		}
+		if params.RoleArn != "qcs::cam::uin/100:roleName/k8s" || params.RoleSessionName != "k8s-aim" {
+			return nil, &emulator.Error{Code: "InvalidParameter", Message: "unexpected role"}
+		}
+		return map[string]interface{}{
+			"Credentials": map[string]string{"TmpSecretId": "sts-id", "TmpSecretKey": "sts-key", "Token": "sts-token"},
+			"ExpiredTime": expired,
+		}, nil
+	})
+	provider := common.NewStsRoleProvider(s.Credential(), "ap-guangzhou", "qcs::cam::uin/100:roleName/k8s", "k8s-aim")
+	provider.Endpoint = strings.TrimPrefix(s.URL, "http://")
+	provider.Scheme = "HTTP"
+	for i := 0; i < 2; i++ {
+		credential, err := provider.GetCredential()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *credential != (common.Credential{SecretId: "sts-id", SecretKey: "sts-key", Token: "sts-token"}) {
+			t.Fatalf("credential = %+v", credential)
+		}
+	}
+	requests := s.Requests()
+	if len(requests) != 1 || requests[0].Action != "AssumeRole" || requests[0].Service != "sts" {
+		t.Fatalf("requests = %+v, want a single sts AssumeRole", requests)
+	}
+}
+
+// failingProvider 始终失败并记录调用次数的提供者
+type failingProvider struct {
+	calls int
+}
+
+func (p *failingProvider) GetCredential() (*common.Credential, error) {
+	p.calls++
+	return nil, errors.New("no credential")
+}
+
+func TestProviderChainOrder(t *testing.T) {
+	first, last := &failingProvider{}, &failingProvider{}
+	chain := common.NewProviderChain(first, common.NewCredential("chain-id", "chain-key"), last)
+	credential, err := chain.GetCredential()
+	if err != nil || credential.SecretId != "chain-id" {
+		t.Fatalf("credential = %+v, %v", credential, err)
+	}
+	if first.calls != 1 || last.calls != 0 {
+		t.Fatalf("calls = %d, %d, want providers tried in order until one succeeds", first.calls, last.calls)
+	}
+	_, err = common.NewProviderChain(first, last).GetCredential()
+	if err == nil || !strings.Contains(err.Error(), "no credential") {
+		t.Fatalf("err = %v, want errors of every provider", err)
+	}
+}
+
+func TestSendCancelsCredentialRefresh(t *testing.T) {
+	s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+	defer s.Close()
+	// 元数据服务不响应, 只能随请求 ctx 结束
+	block := make(chan struct{})
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-block:
+		}
+	}))
+	defer metadata.Close()
+	defer close(block)
+	provider := &common.CvmRoleProvider{Endpoint: metadata.URL, RoleName: "node-role"}
+	client, err := cvm.NewClient(provider, "ap-guangzhou", s.ClientProfile(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.DescribeZonesWithContext(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("returned after %s, want credential refresh cancelled with the request", elapsed)
+	}
+	if n := len(s.Requests()); n != 0 {
+		t.Fatalf("sent %d requests without credential, want 0", n)
+	}
+}
@@ -0,0 +1,120 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/profile"
+	"golang.org/x/time/rate"
+	"sync"
+	"time"
+)
+
+// RateLimitStat 限流等待统计
+type RateLimitStat struct {
+	Calls     int64         // 请求次数
+	Waited    int64         // 发生排队的请求次数
+	TotalWait time.Duration // 累计排队时间
+	MaxWait   time.Duration // 最长排队时间
+}
+
+// rateLimiter 按 service/action 区分的令牌桶限流器
+type rateLimiter struct {
+	mu       sync.Mutex
+	profile  *profile.RateLimitProfile
+	limiters map[string]*rate.Limiter
+	stats    map[string]*RateLimitStat
+}
+
+func newRateLimiter(p *profile.RateLimitProfile) *rateLimiter {
+	return &rateLimiter{
+		profile:  p,
+		limiters: make(map[string]*rate.Limiter),
+		stats:    make(map[string]*RateLimitStat),
+	}
+}
+
+// limiter 获取 key 对应的令牌桶, 不限流时返回 nil
+func (l *rateLimiter) limiter(key string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if lim, ok := l.limiters[key]; ok {
+		return lim
+	}
+	var lim *rate.Limiter
+	if limit := l.profile.Limit(key); limit.QPS > 0 {
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		lim = rate.NewLimiter(rate.Limit(limit.QPS), burst)
+	}
+	l.limiters[key] = lim
+	return lim
+}
+
+// wait 阻塞直到获取令牌, 返回排队时间; ctx 取消或排队时间超过截止时间时返回错误
+func (l *rateLimiter) wait(ctx context.Context, key string) (time.Duration, error) {
+	lim := l.limiter(key)
+	if lim == nil {
+		l.record(key, 0)
+		return 0, nil
+	}
+	if l.profile.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.profile.MaxWait)
+		defer cancel()
+	}
+	r := lim.Reserve()
+	delay := r.Delay()
+	if delay == 0 {
+		l.record(key, 0)
+		return 0, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		r.Cancel()
+		msg := fmt.Sprintf("rate limit of %s requires waiting %s, exceeds deadline", key, delay)
+		return 0, errors.NewTencentCloudSDKError("ClientError.RateLimitWaitTimeout", msg, "")
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		l.record(key, delay)
+		return delay, nil
+	case <-ctx.Done():
+		r.Cancel()
+		msg := fmt.Sprintf("waiting for rate limit of %s canceled", key)
+		return 0, errors.WrapError("ClientError.RateLimitWaitTimeout", msg, ctx.Err())
+	}
+}
+
+// record 记录排队时间
+func (l *rateLimiter) record(key string, waited time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stat, ok := l.stats[key]
+	if !ok {
+		stat = &RateLimitStat{}
+		l.stats[key] = stat
+	}
+	stat.Calls++
+	if waited > 0 {
+		stat.Waited++
+		stat.TotalWait += waited
+		if waited > stat.MaxWait {
+			stat.MaxWait = waited
+		}
+	}
+}
+
+// snapshot 统计信息快照
+func (l *rateLimiter) snapshot() map[string]RateLimitStat {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := make(map[string]RateLimitStat, len(l.stats))
+	for key, stat := range l.stats {
+		stats[key] = *stat
+	}
+	return stats
+}
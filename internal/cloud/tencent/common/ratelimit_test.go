@@ -0,0 +1,176 @@
+package common_test
+
+import (
+	"context"
+	"errors"
+	tcerr "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/profile"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/emulator"
+	"testing"
+	"time"
+)
+
+// newLimitedClient 访问模拟服务的客户端, 使用指定的限流设置
+func newLimitedClient(t *testing.T, rpf *profile.RateLimitProfile) (*emulator.Server, *cvm.Client) {
+	t.Helper()
+	s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+	t.Cleanup(s.Close)
+	cpf := s.ClientProfile("")
+	cpf.RateLimitProfile = rpf
+	client, err := cvm.NewClient(s.Credential(), "ap-guangzhou", cpf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, client
+}
+
+// assertWaitTimeout 限流排队超时错误
+func assertWaitTimeout(t *testing.T, err error) {
+	t.Helper()
+	var sdkErr *tcerr.TencentCloudSDKError
+	if !errors.As(err, &sdkErr) || sdkErr.Code != "ClientError.RateLimitWaitTimeout" {
+		t.Fatalf("err = %v, want ClientError.RateLimitWaitTimeout", err)
+	}
+	if tcerr.CategoryOf(err) != tcerr.CategoryThrottled {
+		t.Fatalf("category = %s, want throttled", tcerr.CategoryOf(err))
+	}
+}
+
+// countRequests 模拟服务收到的各接口请求次数
+func countRequests(s *emulator.Server) map[string]int {
+	counts := make(map[string]int)
+	for _, req := range s.Requests() {
+		counts[req.Action]++
+	}
+	return counts
+}
+
+func TestDefaultRateLimits(t *testing.T) {
+	rpf := profile.NewRateLimitProfile()
+	tests := []struct {
+		key  string
+		want profile.RateLimit
+	}{
+		{"cvm/RunInstances", profile.RateLimit{QPS: 10, Burst: 10}},
+		{"cvm/TerminateInstances", profile.RateLimit{QPS: 10, Burst: 10}},
+		{"cvm/DescribeInstances", profile.RateLimit{QPS: 20, Burst: 20}},
+		{"vpc/DescribeVpcs", profile.RateLimit{QPS: 20, Burst: 20}},
+	}
+	for _, tt := range tests {
+		if got := rpf.Limit(tt.key); got != tt.want {
+			t.Errorf("Limit(%s) = %+v, want %+v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitPerAction(t *testing.T) {
+	s, client := newLimitedClient(t, &profile.RateLimitProfile{
+		Default: profile.RateLimit{QPS: 1000, Burst: 1000},
+		Overrides: map[string]profile.RateLimit{
+			"cvm/RunInstances":       {QPS: 0.1, Burst: 1},
+			"cvm/TerminateInstances": {QPS: 0.1, Burst: 1},
+		},
+	})
+	if _, err := client.RunInstances(nil); err != nil {
+		t.Fatal(err)
+	}
+	// 令牌用尽后需等待约10s, 超过 ctx 截止时间, 不发送请求
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := client.RunInstancesWithContext(ctx, nil)
+	assertWaitTimeout(t, err)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("returned after %s, want to fail without waiting", elapsed)
+	}
+	// 其它接口各自限流, 不受影响
+	if _, err := client.TerminateInstances(nil); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := client.DescribeZones(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts := countRequests(s)
+	if counts["RunInstances"] != 1 || counts["TerminateInstances"] != 1 || counts["DescribeZones"] != 5 {
+		t.Fatalf("requests = %v", counts)
+	}
+	stats := client.RateLimitStats()
+	if stats["cvm/RunInstances"].Calls != 1 || stats["cvm/TerminateInstances"].Calls != 1 || stats["cvm/DescribeZones"].Calls != 5 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestRateLimitMaxWait(t *testing.T) {
+	s, client := newLimitedClient(t, &profile.RateLimitProfile{
+		Default: profile.RateLimit{QPS: 0.1, Burst: 1},
+		MaxWait: 50 * time.Millisecond,
+	})
+	if _, err := client.DescribeZones(nil); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err := client.DescribeZones(nil)
+	assertWaitTimeout(t, err)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("returned after %s, want to fail without waiting", elapsed)
+	}
+	if n := len(s.Requests()); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+}
+
+func TestRateLimitWaitCanceled(t *testing.T) {
+	s, client := newLimitedClient(t, &profile.RateLimitProfile{
+		Default: profile.RateLimit{QPS: 0.1, Burst: 1},
+	})
+	if _, err := client.DescribeZones(nil); err != nil {
+		t.Fatal(err)
+	}
+	// 无截止时间的 ctx 在排队期间取消
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err := client.DescribeZonesWithContext(ctx, nil)
+	assertWaitTimeout(t, err)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want Canceled", err)
+	}
+	if n := len(s.Requests()); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+	// 取消的排队归还令牌, 不计入统计
+	if stat := client.RateLimitStats()["cvm/DescribeZones"]; stat.Calls != 1 || stat.Waited != 0 {
+		t.Fatalf("stat = %+v, want 1 call without waiting", stat)
+	}
+}
+
+func TestRateLimitStats(t *testing.T) {
+	_, client := newLimitedClient(t, &profile.RateLimitProfile{
+		Default: profile.RateLimit{QPS: 5, Burst: 1},
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := client.DescribeZones(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stat := client.RateLimitStats()["cvm/DescribeZones"]
+	if stat.Calls != 2 || stat.Waited != 1 {
+		t.Fatalf("stat = %+v, want 2 calls and 1 waited", stat)
+	}
+	if stat.MaxWait <= 0 || stat.MaxWait > 200*time.Millisecond || stat.TotalWait != stat.MaxWait {
+		t.Fatalf("stat = %+v, want one wait of at most 200ms", stat)
+	}
+
+	// 不限流时返回空统计
+	_, unlimited := newLimitedClient(t, nil)
+	if _, err := unlimited.DescribeZones(nil); err != nil {
+		t.Fatal(err)
+	}
+	if stats := unlimited.RateLimitStats(); len(stats) != 0 {
+		t.Fatalf("stats = %+v, want empty without rate limit", stats)
+	}
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/profile"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// isRetryable 判断错误是否可以重试, 限流、网络及服务端错误按类别重试, 其余按错误码匹配
+func isRetryable(retry *profile.RetryProfile, err error) bool {
+	var sdkErr *errors.TencentCloudSDKError
+	if !stderrors.As(err, &sdkErr) {
+		return false
+	}
+	if retry.RetryHttp5xx && sdkErr.HttpStatus >= 500 {
+		return true
+	}
+	for _, category := range retry.RetryableCategories {
+		if sdkErr.Category() == category {
+			return true
+		}
+	}
+	for _, code := range retry.RetryableCodes {
+		if sdkErr.Code == code || strings.HasPrefix(sdkErr.Code, code+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff 第 attempt 次失败后的等待时间, 指数退避并加入随机抖动
+func backoff(retry *profile.RetryProfile, attempt int) time.Duration {
+	delay := retry.BaseDelay
+	for i := 1; i < attempt && delay < retry.MaxDelay; i++ {
+		delay *= 2
+	}
+	if retry.MaxDelay > 0 && delay > retry.MaxDelay {
+		delay = retry.MaxDelay
+	}
+	if retry.Jitter > 0 {
+		jitter := retry.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+	return delay
+}
+
+// interruptedError 重试等待期间 ctx 取消或超时的错误, errors.Is 同时匹配 ctx 错误及上次请求的错误,
+// errors.As 可取得上次请求的 *errors.TencentCloudSDKError
+type interruptedError struct {
+	cause error // ctx.Err()
+	last  error // 上次请求的错误
+}
+
+func (e *interruptedError) Error() string {
+	return fmt.Sprintf("%s: last error: %s", e.cause, e.last)
+}
+
+// Unwrap 返回上次请求的错误
+func (e *interruptedError) Unwrap() error {
+	return e.last
+}
+
+// Is 匹配 ctx 错误, 如 context.Canceled、context.DeadlineExceeded
+func (e *interruptedError) Is(target error) bool {
+	return stderrors.Is(e.cause, target)
+}
+
+// sleepWithContext 等待 delay 时间, ctx 取消时提前返回
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
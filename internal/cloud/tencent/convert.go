@@ -0,0 +1,313 @@
+package tencent
+
+import (
+	"encoding/base64"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/utils"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// imageTypes 镜像类型映射
+var imageTypes = map[cloud.ImageType]string{
+	cloud.ImageTypePublic:  cvm.ImageTypePublic,
+	cloud.ImageTypePrivate: cvm.ImageTypePrivate,
+	cloud.ImageTypeShared:  cvm.ImageTypeShared,
+}
+
+// instanceStates 实例状态映射
+var instanceStates = map[string]cloud.InstanceState{
+	cvm.InstanceStatePending:     cloud.InstanceStatePending,
+	cvm.InstanceStateLaunchFail:  cloud.InstanceStateFailed,
+	cvm.InstanceStateRunning:     cloud.InstanceStateRunning,
+	cvm.InstanceStateStopped:     cloud.InstanceStateStopped,
+	cvm.InstanceStateStarting:    cloud.InstanceStateStarting,
+	cvm.InstanceStateStopping:    cloud.InstanceStateStopping,
+	cvm.InstanceStateRebooting:   cloud.InstanceStateRebooting,
+	cvm.InstanceStateShutdown:    cloud.InstanceStateTerminated,
+	cvm.InstanceStateTerminating: cloud.InstanceStateTerminating,
+}
+
+// 竞价实例市场选项
+const (
+	marketTypeSpot  = "spot"     // 竞价市场
+	spotTypeOneTime = "one-time" // 一次性竞价请求
+)
+
+// runInstancesRequest 创建实例参数转换为 RunInstances 请求
+func runInstancesRequest(spec *cloud.InstanceSpec) *cvm.RunInstancesRequest {
+	req := cvm.NewRunInstancesRequest()
+	req.Placement = &cvm.Placement{Zone: utils.StringPtr(spec.Zone)}
+	req.InstanceType = utils.StringPtr(spec.InstanceType)
+	req.ImageId = utils.StringPtr(spec.ImageId)
+	count := spec.Count
+	if count <= 0 {
+		count = 1
+	}
+	req.InstanceCount = &count
+	if spec.Name != "" {
+		req.InstanceName = utils.StringPtr(spec.Name)
+	}
+	if spec.HostName != "" {
+		req.HostName = utils.StringPtr(spec.HostName)
+	}
+	if spec.SystemDisk != nil {
+		req.SystemDisk = &cvm.SystemDisk{DiskSize: utils.Int64Ptr(spec.SystemDisk.SizeGB)}
+		if spec.SystemDisk.Type != "" {
+			req.SystemDisk.DiskType = utils.StringPtr(spec.SystemDisk.Type)
+		}
+	}
+	for _, disk := range spec.DataDisks {
+		dataDisk := &cvm.DataDisk{DiskSize: utils.Int64Ptr(disk.SizeGB), DeleteWithInstance: utils.BoolPtr(true)}
+		if disk.Type != "" {
+			dataDisk.DiskType = utils.StringPtr(disk.Type)
+		}
+		req.DataDisks = append(req.DataDisks, dataDisk)
+	}
+	if spec.VpcId != "" || spec.SubnetId != "" {
+		req.VirtualPrivateCloud = &cvm.VirtualPrivateCloud{VpcId: utils.StringPtr(spec.VpcId), SubnetId: utils.StringPtr(spec.SubnetId)}
+	}
+	if spec.InternetBandwidth > 0 {
+		req.InternetAccessible = &cvm.InternetAccessible{
+			InternetMaxBandwidthOut: utils.Int64Ptr(spec.InternetBandwidth),
+			PublicIpAssigned:        utils.BoolPtr(true),
+		}
+	}
+	if len(spec.SecurityGroupIds) > 0 {
+		req.SecurityGroupIds = utils.StringPtrs(spec.SecurityGroupIds)
+	}
+	if len(spec.KeyPairIds) > 0 {
+		req.LoginSettings = &cvm.LoginSettings{KeyIds: utils.StringPtrs(spec.KeyPairIds)}
+	}
+	// instance 类型的标签同时绑定到实例的系统盘及数据盘
+	if len(spec.Tags) > 0 {
+		req.TagSpecification = []*cvm.TagSpecification{{ResourceType: utils.StringPtr("instance"), Tags: toTags(spec.Tags)}}
+	}
+	if spec.UserData != "" {
+		req.UserData = utils.StringPtr(base64.StdEncoding.EncodeToString([]byte(spec.UserData)))
+	}
+	if spec.ClientToken != "" {
+		req.ClientToken = utils.StringPtr(spec.ClientToken)
+	}
+	if spec.Spot != nil {
+		spotType := spec.Spot.SpotType
+		if spotType == "" {
+			spotType = spotTypeOneTime
+		}
+		req.InstanceChargeType = utils.StringPtr(cvm.InstanceChargeTypeSpotpaid)
+		req.InstanceMarketOptions = &cvm.InstanceMarketOptionsRequest{
+			MarketType:  utils.StringPtr(marketTypeSpot),
+			SpotOptions: &cvm.SpotMarketOptions{SpotInstanceType: utils.StringPtr(spotType)},
+		}
+		if spec.Spot.MaxPrice > 0 {
+			req.InstanceMarketOptions.SpotOptions.MaxPrice = utils.StringPtr(strconv.FormatFloat(spec.Spot.MaxPrice, 'f', -1, 64))
+		}
+	}
+	return req
+}
+
+// toInstanceInfo 实例信息转换
+func toInstanceInfo(instance *cvm.Instance) cloud.InstanceInfo {
+	info := cloud.InstanceInfo{
+		InstanceId:       instance.InstanceId,
+		Name:             instance.InstanceName,
+		InstanceType:     instance.InstanceType,
+		ImageId:          instance.ImageId,
+		State:            cloud.InstanceStateUnknown,
+		PrivateIps:       instance.PrivateIpAddresses,
+		PublicIps:        instance.PublicIpAddresses,
+		SecurityGroupIds: instance.SecurityGroupIds,
+		Tags:             fromTags(instance.Tags),
+		Spot:             instance.InstanceChargeType == cvm.InstanceChargeTypeSpotpaid,
+		CreatedTime:      parseTime(instance.CreatedTime),
+	}
+	if state, ok := instanceStates[instance.InstanceState]; ok {
+		info.State = state
+	}
+	if instance.Placement != nil && instance.Placement.Zone != nil {
+		info.Zone = *instance.Placement.Zone
+	}
+	if instance.LoginSettings != nil {
+		info.KeyPairIds = utils.StringValues(instance.LoginSettings.KeyIds)
+	}
+	return info
+}
+
+// toInstanceInfos 实例信息批量转换
+func toInstanceInfos(instances []*cvm.Instance) []cloud.InstanceInfo {
+	infos := make([]cloud.InstanceInfo, 0, len(instances))
+	for _, instance := range instances {
+		infos = append(infos, toInstanceInfo(instance))
+	}
+	return infos
+}
+
+// matchInstance 实例是否满足查询条件
+func matchInstance(info *cloud.InstanceInfo, filter *cloud.InstanceFilter) bool {
+	if filter.Zone != "" && info.Zone != filter.Zone {
+		return false
+	}
+	if filter.Name != "" && info.Name != filter.Name {
+		return false
+	}
+	for key, value := range filter.Tags {
+		if v, ok := info.Tags[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// toImageInfo 镜像信息转换
+func toImageInfo(image *cvm.Image) *cloud.ImageInfo {
+	info := &cloud.ImageInfo{
+		ImageId:      image.ImageId,
+		Name:         image.ImageName,
+		OsName:       image.OsName,
+		Platform:     image.Platform,
+		Architecture: image.Architecture,
+		SizeGB:       image.ImageSize,
+		CreatedTime:  parseTime(image.CreatedTime),
+	}
+	for imageType, value := range imageTypes {
+		if value == image.ImageType {
+			info.ImageType = imageType
+		}
+	}
+	return info
+}
+
+// toInstanceTypeOffering 机型售卖信息转换
+func toInstanceTypeOffering(item *cvm.InstanceTypeQuotaItem) cloud.InstanceTypeOffering {
+	offering := cloud.InstanceTypeOffering{
+		Zone:         item.Zone,
+		InstanceType: item.InstanceType,
+		Family:       item.InstanceFamily,
+		CPU:          item.Cpu,
+		MemoryGB:     item.Memory,
+		GPU:          item.Gpu,
+		InStock:      item.Status == cvm.InstanceTypeStatusSell,
+	}
+	if item.Price != nil {
+		offering.Price = item.Price.UnitPrice
+		if item.Price.UnitPriceDiscount > 0 {
+			offering.Price = item.Price.UnitPriceDiscount
+		}
+	}
+	return offering
+}
+
+// toPolicySet 安全组规则转换, 按方向分组
+func toPolicySet(rules []cloud.SecurityRule) *cvm.SecurityGroupPolicySet {
+	set := &cvm.SecurityGroupPolicySet{}
+	for _, rule := range rules {
+		port := rule.Port
+		if port == "" && rule.Protocol != cloud.ProtocolICMP {
+			port = "ALL"
+		}
+		policy := cvm.NewSecurityGroupPolicy(rule.Protocol, port, rule.CidrBlock, rule.Description)
+		if rule.Direction == cloud.RuleEgress {
+			set.Egress = append(set.Egress, policy)
+		} else {
+			set.Ingress = append(set.Ingress, policy)
+		}
+	}
+	return set
+}
+
+// toRules 安全组规则转换, 只保留允许访问的规则
+func toRules(set *cvm.SecurityGroupPolicySet) []cloud.SecurityRule {
+	if set == nil {
+		return nil
+	}
+	var rules []cloud.SecurityRule
+	convert := func(direction cloud.RuleDirection, policies []*cvm.SecurityGroupPolicy) {
+		for _, p := range policies {
+			if p.Action != nil && *p.Action != cvm.PolicyActionAccept {
+				continue
+			}
+			rule := cloud.SecurityRule{Direction: direction}
+			if p.Protocol != nil {
+				rule.Protocol = *p.Protocol
+			}
+			if p.Port != nil && *p.Port != "ALL" {
+				rule.Port = *p.Port
+			}
+			if p.CidrBlock != nil {
+				rule.CidrBlock = *p.CidrBlock
+			}
+			if p.PolicyDescription != nil {
+				rule.Description = *p.PolicyDescription
+			}
+			rules = append(rules, rule)
+		}
+	}
+	convert(cloud.RuleIngress, set.Ingress)
+	convert(cloud.RuleEgress, set.Egress)
+	return rules
+}
+
+// toSecurityGroupInfo 安全组信息转换
+func toSecurityGroupInfo(group *cvm.SecurityGroup) cloud.SecurityGroupInfo {
+	return cloud.SecurityGroupInfo{
+		SecurityGroupId: group.SecurityGroupId,
+		Name:            group.SecurityGroupName,
+		Description:     group.SecurityGroupDesc,
+		CreatedTime:     parseTime(group.CreatedTime),
+		Tags:            fromTags(group.TagSet),
+	}
+}
+
+// toKeyPair 密钥对转换
+func toKeyPair(keyPair *cvm.KeyPair) *cloud.KeyPair {
+	return &cloud.KeyPair{
+		KeyId:       keyPair.KeyId,
+		Name:        keyPair.KeyName,
+		PublicKey:   keyPair.PublicKey,
+		PrivateKey:  keyPair.PrivateKey,
+		InstanceIds: keyPair.AssociatedInstanceIds,
+		CreatedTime: parseTime(keyPair.CreatedTime),
+		Tags:        fromTags(keyPair.Tags),
+	}
+}
+
+// toTags 标签转换, 按键排序保证请求稳定
+func toTags(tags map[string]string) []*cvm.Tag {
+	var result []*cvm.Tag
+	for _, key := range sortedKeys(tags) {
+		result = append(result, &cvm.Tag{Key: utils.StringPtr(key), Value: utils.StringPtr(tags[key])})
+	}
+	return result
+}
+
+// fromTags 标签转换
+func fromTags(tags []*cvm.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil {
+			result[*tag.Key] = *tag.Value
+		}
+	}
+	return result
+}
+
+// sortedKeys 排序后的键
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseTime 解析 ISO8601 时间, 失败时返回零值
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
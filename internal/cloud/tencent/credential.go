@@ -0,0 +1,37 @@
+package tencent
+
+import (
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+)
+
+// NewCredentialProvider 根据配置构建凭证提供者链
+// 依次尝试配置文件中的密钥、环境变量、凭证文件、CVM 实例角色, 配置了 role_arn 时再通过 STS 扮演角色
+func NewCredentialProvider(c *config.Tencent) common.CredentialProvider {
+	chain := common.NewProviderChain()
+	if c.SecretId != "" && c.SecretKey != "" {
+		chain.Providers = append(chain.Providers, common.NewTokenCredential(c.SecretId, c.SecretKey, c.Token))
+	}
+	roleProvider := common.NewCvmRoleProvider(c.RoleName)
+	if c.MetadataEndpoint != "" {
+		roleProvider.Endpoint = c.MetadataEndpoint
+	}
+	chain.Providers = append(chain.Providers,
+		common.NewEnvProvider(),
+		common.NewProfileProvider(c.CredentialsFile, c.Profile),
+		roleProvider,
+	)
+	if c.RoleArn == "" {
+		return chain
+	}
+
+	sessionName := c.RoleSessionName
+	if sessionName == "" {
+		sessionName = "k8s-aim"
+	}
+	stsProvider := common.NewStsRoleProvider(chain, c.Region, c.RoleArn, sessionName)
+	if c.StsEndpoint != "" {
+		stsProvider.Endpoint = c.StsEndpoint
+	}
+	return stsProvider
+}
@@ -1 +1,172 @@
 package cvm
+
+import (
+	"context"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+)
+
+// 镜像类型
+const (
+	ImageTypePublic  = "PUBLIC_IMAGE"  // 公共镜像
+	ImageTypePrivate = "PRIVATE_IMAGE" // 自定义镜像
+	ImageTypeShared  = "SHARED_IMAGE"  // 共享镜像
+)
+
+// 镜像状态
+const (
+	ImageStateNormal   = "NORMAL"   // 正常
+	ImageStateCreating = "CREATING" // 创建中
+)
+
+// Image 镜像信息
+type Image struct {
+	ImageId          string `json:"ImageId"`          // 镜像ID
+	ImageName        string `json:"ImageName"`        // 镜像名称
+	ImageDescription string `json:"ImageDescription"` // 镜像描述
+	ImageType        string `json:"ImageType"`        // 镜像类型
+	ImageState       string `json:"ImageState"`       // 镜像状态
+	ImageSize        int64  `json:"ImageSize"`        // 镜像大小, 单位GB
+	OsName           string `json:"OsName"`           // 操作系统名称, 如 Ubuntu Server 20.04 LTS 64位
+	Platform         string `json:"Platform"`         // 操作系统平台, 如 Ubuntu、CentOS
+	Architecture     string `json:"Architecture"`     // 操作系统架构
+	ImageCreator     string `json:"ImageCreator"`     // 镜像创建者
+	ImageSource      string `json:"ImageSource"`      // 镜像来源
+	CreatedTime      string `json:"CreatedTime"`      // 创建时间, ISO8601 格式
+	Tags             []*Tag `json:"Tags"`             // 标签
+}
+
+// DescribeImagesRequest 查询镜像请求参数
+type DescribeImagesRequest struct {
+	*tcHttp.BaseRequest
+	ImageIds     []*string `json:"ImageIds,omitempty" name:"ImageIds"`         // 镜像ID, 不能与 Filters 同时指定
+	Filters      []*Filter `json:"Filters,omitempty" name:"Filters"`           // 过滤条件, 如 image-id、image-type、image-name、platform
+	Offset       *uint64   `json:"Offset,omitempty" name:"Offset"`             // 偏移量, 默认为0
+	Limit        *uint64   `json:"Limit,omitempty" name:"Limit"`               // 返回数量, 默认为20, 最大值为100
+	InstanceType *string   `json:"InstanceType,omitempty" name:"InstanceType"` // 实例机型, 只返回该机型支持的镜像
+}
+
+// DescribeImagesResponse 查询镜像响应结果
+type DescribeImagesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		ImageSet   []*Image `json:"ImageSet,omitempty"`   // 镜像列表
+		TotalCount int64    `json:"TotalCount,omitempty"` // 符合条件的镜像数量
+		RequestId  string   `json:"RequestId,omitempty"`  // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeImagesRequest 实例化
+func NewDescribeImagesRequest() *DescribeImagesRequest {
+	req := &DescribeImagesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DescribeImages")
+	return req
+}
+
+// NewDescribeImagesResponse 实例化
+func NewDescribeImagesResponse() *DescribeImagesResponse {
+	return &DescribeImagesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeImages 查询镜像
+func (c *Client) DescribeImages(req *DescribeImagesRequest) (*DescribeImagesResponse, error) {
+	return c.DescribeImagesWithContext(context.Background(), req)
+}
+
+// DescribeImagesWithContext 查询镜像
+func (c *Client) DescribeImagesWithContext(ctx context.Context, req *DescribeImagesRequest) (*DescribeImagesResponse, error) {
+	if req == nil {
+		req = NewDescribeImagesRequest()
+	}
+	resp := NewDescribeImagesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// CreateImageRequest 制作自定义镜像请求参数
+type CreateImageRequest struct {
+	*tcHttp.BaseRequest
+	ImageName        *string             `json:"ImageName,omitempty" name:"ImageName"`               // 镜像名称
+	InstanceId       *string             `json:"InstanceId,omitempty" name:"InstanceId"`             // 用于制作镜像的实例ID
+	ImageDescription *string             `json:"ImageDescription,omitempty" name:"ImageDescription"` // 镜像描述
+	ForcePoweroff    *string             `json:"ForcePoweroff,omitempty" name:"ForcePoweroff"`       // 软关机失败时是否强制关机, TRUE/FALSE
+	Sysprep          *string             `json:"Sysprep,omitempty" name:"Sysprep"`                   // 创建 Windows 镜像时是否启用 Sysprep
+	DataDiskIds      []*string           `json:"DataDiskIds,omitempty" name:"DataDiskIds"`           // 包含的数据盘ID
+	TagSpecification []*TagSpecification `json:"TagSpecification,omitempty" name:"TagSpecification"` // 标签
+	DryRun           *bool               `json:"DryRun,omitempty" name:"DryRun"`                     // 是否只预检此次请求
+}
+
+// CreateImageResponse 制作自定义镜像响应结果
+type CreateImageResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		ImageId   string `json:"ImageId,omitempty"`   // 镜像ID
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewCreateImageRequest 实例化
+func NewCreateImageRequest() *CreateImageRequest {
+	req := &CreateImageRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "CreateImage")
+	return req
+}
+
+// NewCreateImageResponse 实例化
+func NewCreateImageResponse() *CreateImageResponse {
+	return &CreateImageResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// CreateImage 制作自定义镜像
+func (c *Client) CreateImage(req *CreateImageRequest) (*CreateImageResponse, error) {
+	return c.CreateImageWithContext(context.Background(), req)
+}
+
+// CreateImageWithContext 制作自定义镜像
+func (c *Client) CreateImageWithContext(ctx context.Context, req *CreateImageRequest) (*CreateImageResponse, error) {
+	if req == nil {
+		req = NewCreateImageRequest()
+	}
+	resp := NewCreateImageResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// ImageSelector 镜像查询条件
+type ImageSelector struct {
+	ImageType    string // 镜像类型 PUBLIC_IMAGE、PRIVATE_IMAGE、SHARED_IMAGE, 为空时不限
+	Platform     string // 操作系统平台, 如 Ubuntu
+	InstanceType string // 实例机型, 只返回该机型支持的镜像
+}
+
+// ListImages 分页查询符合条件的全部镜像
+func (c *Client) ListImages(ctx context.Context, selector *ImageSelector) ([]*Image, error) {
+	var filters []*Filter
+	if selector.ImageType != "" {
+		filters = append(filters, NewFilter("image-type", selector.ImageType))
+	}
+	if selector.Platform != "" {
+		filters = append(filters, NewFilter("platform", selector.Platform))
+	}
+
+	var images []*Image
+	pager := common.NewPager(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		req := NewDescribeImagesRequest()
+		req.Filters = filters
+		pageOffset, pageLimit := uint64(offset), uint64(limit)
+		req.Offset, req.Limit = &pageOffset, &pageLimit
+		if selector.InstanceType != "" {
+			req.InstanceType = &selector.InstanceType
+		}
+		resp, err := c.DescribeImagesWithContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		images = append(images, resp.Response.ImageSet...)
+		return len(resp.Response.ImageSet), resp.Response.TotalCount, nil
+	})
+	if err := pager.All(ctx); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
@@ -1 +1,490 @@
 package cvm
+
+import (
+	"context"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+)
+
+// 实例状态
+const (
+	InstanceStatePending     = "PENDING"       // 创建中
+	InstanceStateLaunchFail  = "LAUNCH_FAILED" // 创建失败
+	InstanceStateRunning     = "RUNNING"       // 运行中
+	InstanceStateStopped     = "STOPPED"       // 关机
+	InstanceStateStarting    = "STARTING"      // 开机中
+	InstanceStateStopping    = "STOPPING"      // 关机中
+	InstanceStateRebooting   = "REBOOTING"     // 重启中
+	InstanceStateShutdown    = "SHUTDOWN"      // 停止待销毁
+	InstanceStateTerminating = "TERMINATING"   // 销毁中
+)
+
+// 关机类型
+const (
+	StopTypeSoft      = "SOFT"       // 软关机
+	StopTypeHard      = "HARD"       // 硬关机
+	StopTypeSoftFirst = "SOFT_FIRST" // 优先软关机, 失败再执行硬关机
+)
+
+// 按量计费实例关机收费模式
+const (
+	StoppedModeKeepCharging = "KEEP_CHARGING" // 关机继续收费
+	StoppedModeStopCharging = "STOP_CHARGING" // 关机停止收费
+)
+
+// Filter 过滤条件
+type Filter struct {
+	Name   *string   `json:"Name,omitempty" name:"Name"`     // 过滤键
+	Values []*string `json:"Values,omitempty" name:"Values"` // 过滤值
+}
+
+// NewFilter 实例化过滤条件
+func NewFilter(name string, values ...string) *Filter {
+	f := &Filter{Name: &name}
+	for i := range values {
+		f.Values = append(f.Values, &values[i])
+	}
+	return f
+}
+
+// Placement 实例位置
+type Placement struct {
+	Zone      *string   `json:"Zone,omitempty" name:"Zone"`           // 可用区
+	ProjectId *int64    `json:"ProjectId,omitempty" name:"ProjectId"` // 项目ID
+	HostIds   []*string `json:"HostIds,omitempty" name:"HostIds"`     // 专用宿主机ID
+}
+
+// SystemDisk 系统盘
+type SystemDisk struct {
+	DiskType *string `json:"DiskType,omitempty" name:"DiskType"` // 系统盘类型, 如 CLOUD_PREMIUM、CLOUD_SSD
+	DiskId   *string `json:"DiskId,omitempty" name:"DiskId"`     // 系统盘ID
+	DiskSize *int64  `json:"DiskSize,omitempty" name:"DiskSize"` // 系统盘大小, 单位GB
+}
+
+// DataDisk 数据盘
+type DataDisk struct {
+	DiskSize           *int64  `json:"DiskSize,omitempty" name:"DiskSize"`                     // 数据盘大小, 单位GB
+	DiskType           *string `json:"DiskType,omitempty" name:"DiskType"`                     // 数据盘类型
+	DiskId             *string `json:"DiskId,omitempty" name:"DiskId"`                         // 数据盘ID
+	DeleteWithInstance *bool   `json:"DeleteWithInstance,omitempty" name:"DeleteWithInstance"` // 是否随实例销毁
+	SnapshotId         *string `json:"SnapshotId,omitempty" name:"SnapshotId"`                 // 快照ID
+	Encrypt            *bool   `json:"Encrypt,omitempty" name:"Encrypt"`                       // 是否加密
+}
+
+// VirtualPrivateCloud 私有网络
+type VirtualPrivateCloud struct {
+	VpcId              *string   `json:"VpcId,omitempty" name:"VpcId"`                           // 私有网络ID
+	SubnetId           *string   `json:"SubnetId,omitempty" name:"SubnetId"`                     // 子网ID
+	AsVpcGateway       *bool     `json:"AsVpcGateway,omitempty" name:"AsVpcGateway"`             // 是否用作公网网关
+	PrivateIpAddresses []*string `json:"PrivateIpAddresses,omitempty" name:"PrivateIpAddresses"` // 私有网络子网 IP 数组
+}
+
+// InternetAccessible 公网带宽
+type InternetAccessible struct {
+	InternetChargeType      *string `json:"InternetChargeType,omitempty" name:"InternetChargeType"`           // 网络计费类型
+	InternetMaxBandwidthOut *int64  `json:"InternetMaxBandwidthOut,omitempty" name:"InternetMaxBandwidthOut"` // 公网出带宽上限, 单位 Mbps
+	PublicIpAssigned        *bool   `json:"PublicIpAssigned,omitempty" name:"PublicIpAssigned"`               // 是否分配公网IP
+}
+
+// LoginSettings 登录设置
+type LoginSettings struct {
+	Password       *string   `json:"Password,omitempty" name:"Password"`             // 登录密码
+	KeyIds         []*string `json:"KeyIds,omitempty" name:"KeyIds"`                 // 密钥ID列表
+	KeepImageLogin *string   `json:"KeepImageLogin,omitempty" name:"KeepImageLogin"` // 保持镜像的原始设置
+}
+
+// Tag 标签
+type Tag struct {
+	Key   *string `json:"Key,omitempty" name:"Key"`     // 标签键
+	Value *string `json:"Value,omitempty" name:"Value"` // 标签值
+}
+
+// TagSpecification 创建资源时绑定的标签
+type TagSpecification struct {
+	ResourceType *string `json:"ResourceType,omitempty" name:"ResourceType"` // 资源类型, 如 instance、keypair
+	Tags         []*Tag  `json:"Tags,omitempty" name:"Tags"`                 // 标签列表
+}
+
+// SpotMarketOptions 竞价相关选项
+type SpotMarketOptions struct {
+	MaxPrice         *string `json:"MaxPrice,omitempty" name:"MaxPrice"`                 // 竞价出价, 单位元/小时
+	SpotInstanceType *string `json:"SpotInstanceType,omitempty" name:"SpotInstanceType"` // 竞价请求类型, 当前仅支持 one-time
+}
+
+// InstanceMarketOptionsRequest 实例的市场相关选项
+type InstanceMarketOptionsRequest struct {
+	MarketType  *string            `json:"MarketType,omitempty" name:"MarketType"`   // 市场选项类型, 当前只支持 spot
+	SpotOptions *SpotMarketOptions `json:"SpotOptions,omitempty" name:"SpotOptions"` // 竞价相关选项
+}
+
+// Instance 实例信息
+type Instance struct {
+	InstanceId           string               `json:"InstanceId"`           // 实例ID
+	InstanceName         string               `json:"InstanceName"`         // 实例名称
+	InstanceType         string               `json:"InstanceType"`         // 实例机型
+	InstanceState        string               `json:"InstanceState"`        // 实例状态
+	InstanceChargeType   string               `json:"InstanceChargeType"`   // 实例计费模式
+	CPU                  int64                `json:"CPU"`                  // CPU核数, 单位:核
+	Memory               int64                `json:"Memory"`               // 内存容量, 单位:GB
+	Placement            *Placement           `json:"Placement"`            // 实例位置
+	ImageId              string               `json:"ImageId"`              // 镜像ID
+	OsName               string               `json:"OsName"`               // 操作系统名称
+	SystemDisk           *SystemDisk          `json:"SystemDisk"`           // 系统盘
+	DataDisks            []*DataDisk          `json:"DataDisks"`            // 数据盘
+	PrivateIpAddresses   []string             `json:"PrivateIpAddresses"`   // 内网IP
+	PublicIpAddresses    []string             `json:"PublicIpAddresses"`    // 公网IP
+	InternetAccessible   *InternetAccessible  `json:"InternetAccessible"`   // 公网带宽
+	VirtualPrivateCloud  *VirtualPrivateCloud `json:"VirtualPrivateCloud"`  // 私有网络
+	SecurityGroupIds     []string             `json:"SecurityGroupIds"`     // 安全组ID
+	LoginSettings        *LoginSettings       `json:"LoginSettings"`        // 登录设置
+	Tags                 []*Tag               `json:"Tags"`                 // 标签
+	CreatedTime          string               `json:"CreatedTime"`          // 创建时间, ISO8601 格式
+	ExpiredTime          string               `json:"ExpiredTime"`          // 到期时间
+	LatestOperation      string               `json:"LatestOperation"`      // 最新操作
+	LatestOperationState string               `json:"LatestOperationState"` // 最新操作状态
+	Uuid                 string               `json:"Uuid"`                 // 实例全局唯一ID
+}
+
+// InstanceStatus 实例状态
+type InstanceStatus struct {
+	InstanceId    string `json:"InstanceId"`    // 实例ID
+	InstanceState string `json:"InstanceState"` // 实例状态
+}
+
+// RunInstancesRequest 创建实例请求参数
+type RunInstancesRequest struct {
+	*tcHttp.BaseRequest
+	InstanceChargeType    *string                       `json:"InstanceChargeType,omitempty" name:"InstanceChargeType"`       // 实例计费类型, 默认 POSTPAID_BY_HOUR
+	InstanceMarketOptions *InstanceMarketOptionsRequest `json:"InstanceMarketOptions,omitempty" name:"InstanceMarketOptions"` // 实例的市场相关选项, 如竞价实例
+	Placement             *Placement                    `json:"Placement,omitempty" name:"Placement"`                         // 实例位置
+	InstanceType          *string                       `json:"InstanceType,omitempty" name:"InstanceType"`                   // 实例机型
+	ImageId               *string                       `json:"ImageId,omitempty" name:"ImageId"`                             // 镜像ID
+	SystemDisk            *SystemDisk                   `json:"SystemDisk,omitempty" name:"SystemDisk"`                       // 系统盘
+	DataDisks             []*DataDisk                   `json:"DataDisks,omitempty" name:"DataDisks"`                         // 数据盘
+	VirtualPrivateCloud   *VirtualPrivateCloud          `json:"VirtualPrivateCloud,omitempty" name:"VirtualPrivateCloud"`     // 私有网络
+	InternetAccessible    *InternetAccessible           `json:"InternetAccessible,omitempty" name:"InternetAccessible"`       // 公网带宽
+	InstanceCount         *int64                        `json:"InstanceCount,omitempty" name:"InstanceCount"`                 // 购买实例数量
+	InstanceName          *string                       `json:"InstanceName,omitempty" name:"InstanceName"`                   // 实例名称
+	LoginSettings         *LoginSettings                `json:"LoginSettings,omitempty" name:"LoginSettings"`                 // 登录设置
+	SecurityGroupIds      []*string                     `json:"SecurityGroupIds,omitempty" name:"SecurityGroupIds"`           // 安全组ID
+	ClientToken           *string                       `json:"ClientToken,omitempty" name:"ClientToken"`                     // 保证请求幂等性的字符串
+	HostName              *string                       `json:"HostName,omitempty" name:"HostName"`                           // 主机名
+	TagSpecification      []*TagSpecification           `json:"TagSpecification,omitempty" name:"TagSpecification"`           // 标签
+	UserData              *string                       `json:"UserData,omitempty" name:"UserData"`                           // 自定义数据, Base64 编码
+	DryRun                *bool                         `json:"DryRun,omitempty" name:"DryRun"`                               // 是否只预检此次请求
+}
+
+// RunInstancesResponse 创建实例响应结果
+type RunInstancesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		InstanceIdSet []string `json:"InstanceIdSet,omitempty"` // 实例ID列表
+		RequestId     string   `json:"RequestId,omitempty"`     // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewRunInstancesRequest 实例化
+func NewRunInstancesRequest() *RunInstancesRequest {
+	req := &RunInstancesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "RunInstances")
+	return req
+}
+
+// NewRunInstancesResponse 实例化
+func NewRunInstancesResponse() *RunInstancesResponse {
+	return &RunInstancesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// RunInstances 创建实例
+func (c *Client) RunInstances(req *RunInstancesRequest) (*RunInstancesResponse, error) {
+	return c.RunInstancesWithContext(context.Background(), req)
+}
+
+// RunInstancesWithContext 创建实例
+func (c *Client) RunInstancesWithContext(ctx context.Context, req *RunInstancesRequest) (*RunInstancesResponse, error) {
+	if req == nil {
+		req = NewRunInstancesRequest()
+	}
+	resp := NewRunInstancesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DescribeInstancesRequest 查询实例请求参数
+type DescribeInstancesRequest struct {
+	*tcHttp.BaseRequest
+	InstanceIds []*string `json:"InstanceIds,omitempty" name:"InstanceIds"` // 实例ID, 每次最多100个, 不能与 Filters 同时指定
+	Filters     []*Filter `json:"Filters,omitempty" name:"Filters"`         // 过滤条件, 如 zone、instance-name、instance-state、tag-key、tag:tag-key
+	Offset      *int64    `json:"Offset,omitempty" name:"Offset"`           // 偏移量, 默认为0
+	Limit       *int64    `json:"Limit,omitempty" name:"Limit"`             // 返回数量, 默认为20, 最大值为100
+}
+
+// DescribeInstancesResponse 查询实例响应结果
+type DescribeInstancesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TotalCount  int64       `json:"TotalCount,omitempty"`  // 符合条件的实例数量
+		InstanceSet []*Instance `json:"InstanceSet,omitempty"` // 实例列表
+		RequestId   string      `json:"RequestId,omitempty"`   // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeInstancesRequest 实例化
+func NewDescribeInstancesRequest() *DescribeInstancesRequest {
+	req := &DescribeInstancesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DescribeInstances")
+	return req
+}
+
+// NewDescribeInstancesResponse 实例化
+func NewDescribeInstancesResponse() *DescribeInstancesResponse {
+	return &DescribeInstancesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeInstances 查询实例
+func (c *Client) DescribeInstances(req *DescribeInstancesRequest) (*DescribeInstancesResponse, error) {
+	return c.DescribeInstancesWithContext(context.Background(), req)
+}
+
+// DescribeInstancesWithContext 查询实例
+func (c *Client) DescribeInstancesWithContext(ctx context.Context, req *DescribeInstancesRequest) (*DescribeInstancesResponse, error) {
+	if req == nil {
+		req = NewDescribeInstancesRequest()
+	}
+	resp := NewDescribeInstancesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// ListInstances 分页查询符合条件的全部实例, 超过 maxItems 条时返回 common.ErrTooManyItems, maxItems 为0时不限
+func (c *Client) ListInstances(ctx context.Context, instanceIds []*string, filters []*Filter, maxItems int64) ([]*Instance, error) {
+	var instances []*Instance
+	pager := common.NewPager(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		req := NewDescribeInstancesRequest()
+		req.InstanceIds, req.Filters = instanceIds, filters
+		req.Offset, req.Limit = &offset, &limit
+		resp, err := c.DescribeInstancesWithContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		instances = append(instances, resp.Response.InstanceSet...)
+		return len(resp.Response.InstanceSet), resp.Response.TotalCount, nil
+	})
+	pager.MaxItems = maxItems
+	if err := pager.All(ctx); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+// DescribeInstancesStatusRequest 查询实例状态请求参数
+type DescribeInstancesStatusRequest struct {
+	*tcHttp.BaseRequest
+	InstanceIds []*string `json:"InstanceIds,omitempty" name:"InstanceIds"` // 实例ID, 每次最多100个
+	Offset      *int64    `json:"Offset,omitempty" name:"Offset"`           // 偏移量, 默认为0
+	Limit       *int64    `json:"Limit,omitempty" name:"Limit"`             // 返回数量, 默认为20, 最大值为100
+}
+
+// DescribeInstancesStatusResponse 查询实例状态响应结果
+type DescribeInstancesStatusResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TotalCount        int64             `json:"TotalCount,omitempty"`        // 符合条件的实例数量
+		InstanceStatusSet []*InstanceStatus `json:"InstanceStatusSet,omitempty"` // 实例状态列表
+		RequestId         string            `json:"RequestId,omitempty"`         // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeInstancesStatusRequest 实例化
+func NewDescribeInstancesStatusRequest() *DescribeInstancesStatusRequest {
+	req := &DescribeInstancesStatusRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DescribeInstancesStatus")
+	return req
+}
+
+// NewDescribeInstancesStatusResponse 实例化
+func NewDescribeInstancesStatusResponse() *DescribeInstancesStatusResponse {
+	return &DescribeInstancesStatusResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeInstancesStatus 查询实例状态
+func (c *Client) DescribeInstancesStatus(req *DescribeInstancesStatusRequest) (*DescribeInstancesStatusResponse, error) {
+	return c.DescribeInstancesStatusWithContext(context.Background(), req)
+}
+
+// DescribeInstancesStatusWithContext 查询实例状态
+func (c *Client) DescribeInstancesStatusWithContext(ctx context.Context, req *DescribeInstancesStatusRequest) (*DescribeInstancesStatusResponse, error) {
+	if req == nil {
+		req = NewDescribeInstancesStatusRequest()
+	}
+	resp := NewDescribeInstancesStatusResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// StartInstancesRequest 启动实例请求参数
+type StartInstancesRequest struct {
+	*tcHttp.BaseRequest
+	InstanceIds []*string `json:"InstanceIds,omitempty" name:"InstanceIds"` // 实例ID, 每次最多100个
+}
+
+// StartInstancesResponse 启动实例响应结果
+type StartInstancesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewStartInstancesRequest 实例化
+func NewStartInstancesRequest() *StartInstancesRequest {
+	req := &StartInstancesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "StartInstances")
+	return req
+}
+
+// NewStartInstancesResponse 实例化
+func NewStartInstancesResponse() *StartInstancesResponse {
+	return &StartInstancesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// StartInstances 启动实例
+func (c *Client) StartInstances(req *StartInstancesRequest) (*StartInstancesResponse, error) {
+	return c.StartInstancesWithContext(context.Background(), req)
+}
+
+// StartInstancesWithContext 启动实例
+func (c *Client) StartInstancesWithContext(ctx context.Context, req *StartInstancesRequest) (*StartInstancesResponse, error) {
+	if req == nil {
+		req = NewStartInstancesRequest()
+	}
+	resp := NewStartInstancesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// StopInstancesRequest 关闭实例请求参数
+type StopInstancesRequest struct {
+	*tcHttp.BaseRequest
+	InstanceIds []*string `json:"InstanceIds,omitempty" name:"InstanceIds"` // 实例ID, 每次最多100个
+	StopType    *string   `json:"StopType,omitempty" name:"StopType"`       // 关机类型, SOFT、HARD、SOFT_FIRST, 默认 SOFT
+	StoppedMode *string   `json:"StoppedMode,omitempty" name:"StoppedMode"` // 按量计费实例关机收费模式, 默认 KEEP_CHARGING
+}
+
+// StopInstancesResponse 关闭实例响应结果
+type StopInstancesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewStopInstancesRequest 实例化
+func NewStopInstancesRequest() *StopInstancesRequest {
+	req := &StopInstancesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "StopInstances")
+	return req
+}
+
+// NewStopInstancesResponse 实例化
+func NewStopInstancesResponse() *StopInstancesResponse {
+	return &StopInstancesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// StopInstances 关闭实例
+func (c *Client) StopInstances(req *StopInstancesRequest) (*StopInstancesResponse, error) {
+	return c.StopInstancesWithContext(context.Background(), req)
+}
+
+// StopInstancesWithContext 关闭实例
+func (c *Client) StopInstancesWithContext(ctx context.Context, req *StopInstancesRequest) (*StopInstancesResponse, error) {
+	if req == nil {
+		req = NewStopInstancesRequest()
+	}
+	resp := NewStopInstancesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// RebootInstancesRequest 重启实例请求参数
+type RebootInstancesRequest struct {
+	*tcHttp.BaseRequest
+	InstanceIds []*string `json:"InstanceIds,omitempty" name:"InstanceIds"` // 实例ID, 每次最多100个
+	StopType    *string   `json:"StopType,omitempty" name:"StopType"`       // 关机类型, SOFT、HARD、SOFT_FIRST, 默认 SOFT
+}
+
+// RebootInstancesResponse 重启实例响应结果
+type RebootInstancesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewRebootInstancesRequest 实例化
+func NewRebootInstancesRequest() *RebootInstancesRequest {
+	req := &RebootInstancesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "RebootInstances")
+	return req
+}
+
+// NewRebootInstancesResponse 实例化
+func NewRebootInstancesResponse() *RebootInstancesResponse {
+	return &RebootInstancesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// RebootInstances 重启实例
+func (c *Client) RebootInstances(req *RebootInstancesRequest) (*RebootInstancesResponse, error) {
+	return c.RebootInstancesWithContext(context.Background(), req)
+}
+
+// RebootInstancesWithContext 重启实例
+func (c *Client) RebootInstancesWithContext(ctx context.Context, req *RebootInstancesRequest) (*RebootInstancesResponse, error) {
+	if req == nil {
+		req = NewRebootInstancesRequest()
+	}
+	resp := NewRebootInstancesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// TerminateInstancesRequest 退还实例请求参数
+type TerminateInstancesRequest struct {
+	*tcHttp.BaseRequest
+	InstanceIds []*string `json:"InstanceIds,omitempty" name:"InstanceIds"` // 实例ID, 每次最多100个
+}
+
+// TerminateInstancesResponse 退还实例响应结果
+type TerminateInstancesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewTerminateInstancesRequest 实例化
+func NewTerminateInstancesRequest() *TerminateInstancesRequest {
+	req := &TerminateInstancesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "TerminateInstances")
+	return req
+}
+
+// NewTerminateInstancesResponse 实例化
+func NewTerminateInstancesResponse() *TerminateInstancesResponse {
+	return &TerminateInstancesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// TerminateInstances 退还实例
+func (c *Client) TerminateInstances(req *TerminateInstancesRequest) (*TerminateInstancesResponse, error) {
+	return c.TerminateInstancesWithContext(context.Background(), req)
+}
+
+// TerminateInstancesWithContext 退还实例
+func (c *Client) TerminateInstancesWithContext(ctx context.Context, req *TerminateInstancesRequest) (*TerminateInstancesResponse, error) {
+	if req == nil {
+		req = NewTerminateInstancesRequest()
+	}
+	resp := NewTerminateInstancesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
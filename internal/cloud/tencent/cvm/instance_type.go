@@ -0,0 +1,144 @@
+package cvm
+
+import (
+	"context"
+	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+)
+
+// 机型售卖状态
+const (
+	InstanceTypeStatusSell    = "SELL"     // 售卖中
+	InstanceTypeStatusSoldOut = "SOLD_OUT" // 已售罄
+)
+
+// 实例计费类型
+const (
+	InstanceChargeTypePostpaidByHour = "POSTPAID_BY_HOUR" // 按小时后付费
+	InstanceChargeTypePrepaid        = "PREPAID"          // 预付费, 即包年包月
+	InstanceChargeTypeSpotpaid       = "SPOTPAID"         // 竞价付费
+)
+
+// InstanceTypeConfig 机型配置
+type InstanceTypeConfig struct {
+	Zone           string `json:"Zone"`           // 可用区
+	InstanceType   string `json:"InstanceType"`   // 实例机型, 如 S5.MEDIUM4
+	InstanceFamily string `json:"InstanceFamily"` // 实例机型系列, 如 S5
+	GPU            int64  `json:"GPU"`            // GPU核数
+	CPU            int64  `json:"CPU"`            // CPU核数
+	Memory         int64  `json:"Memory"`         // 内存容量, 单位GB
+	FPGA           int64  `json:"FPGA"`           // FPGA核数
+}
+
+// ItemPrice 价格
+type ItemPrice struct {
+	UnitPrice         float64 `json:"UnitPrice,omitempty"`         // 后付费原价, 单位元
+	ChargeUnit        string  `json:"ChargeUnit,omitempty"`        // 后付费计价单元, 如 HOUR
+	OriginalPrice     float64 `json:"OriginalPrice,omitempty"`     // 预付费原价, 单位元
+	DiscountPrice     float64 `json:"DiscountPrice,omitempty"`     // 预付费折扣价, 单位元
+	UnitPriceDiscount float64 `json:"UnitPriceDiscount,omitempty"` // 后付费折扣价, 单位元
+}
+
+// InstanceTypeQuotaItem 可用区内机型的售卖信息
+type InstanceTypeQuotaItem struct {
+	Zone               string     `json:"Zone"`               // 可用区
+	InstanceType       string     `json:"InstanceType"`       // 实例机型
+	InstanceChargeType string     `json:"InstanceChargeType"` // 计费类型
+	NetworkCard        int64      `json:"NetworkCard"`        // 网卡类型, 如 25 代表25G网卡
+	Cpu                int64      `json:"Cpu"`                // CPU核数
+	Memory             int64      `json:"Memory"`             // 内存容量, 单位GB
+	InstanceFamily     string     `json:"InstanceFamily"`     // 实例机型系列
+	TypeName           string     `json:"TypeName"`           // 机型名称
+	Status             string     `json:"Status"`             // 售卖状态, SELL、SOLD_OUT
+	Price              *ItemPrice `json:"Price"`              // 价格
+	SoldOutReason      string     `json:"SoldOutReason"`      // 售罄原因
+	InstanceBandwidth  float64    `json:"InstanceBandwidth"`  // 内网带宽, 单位Gbps
+	InstancePps        int64      `json:"InstancePps"`        // 网络收发包能力, 单位万PPS
+	CpuType            string     `json:"CpuType"`            // 处理器型号
+	Gpu                int64      `json:"Gpu"`                // GPU数量
+	Fpga               int64      `json:"Fpga"`               // FPGA数量
+	Remark             string     `json:"Remark"`             // 机型描述
+}
+
+// DescribeInstanceTypeConfigsRequest 查询机型配置请求参数
+type DescribeInstanceTypeConfigsRequest struct {
+	*tcHttp.BaseRequest
+	Filters []*Filter `json:"Filters,omitempty" name:"Filters"` // 过滤条件, 如 zone、instance-family
+}
+
+// DescribeInstanceTypeConfigsResponse 查询机型配置响应结果
+type DescribeInstanceTypeConfigsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		InstanceTypeConfigSet []*InstanceTypeConfig `json:"InstanceTypeConfigSet,omitempty"` // 机型配置列表
+		RequestId             string                `json:"RequestId,omitempty"`             // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeInstanceTypeConfigsRequest 实例化
+func NewDescribeInstanceTypeConfigsRequest() *DescribeInstanceTypeConfigsRequest {
+	req := &DescribeInstanceTypeConfigsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DescribeInstanceTypeConfigs")
+	return req
+}
+
+// NewDescribeInstanceTypeConfigsResponse 实例化
+func NewDescribeInstanceTypeConfigsResponse() *DescribeInstanceTypeConfigsResponse {
+	return &DescribeInstanceTypeConfigsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeInstanceTypeConfigs 查询机型配置
+func (c *Client) DescribeInstanceTypeConfigs(req *DescribeInstanceTypeConfigsRequest) (*DescribeInstanceTypeConfigsResponse, error) {
+	return c.DescribeInstanceTypeConfigsWithContext(context.Background(), req)
+}
+
+// DescribeInstanceTypeConfigsWithContext 查询机型配置
+func (c *Client) DescribeInstanceTypeConfigsWithContext(ctx context.Context, req *DescribeInstanceTypeConfigsRequest) (*DescribeInstanceTypeConfigsResponse, error) {
+	if req == nil {
+		req = NewDescribeInstanceTypeConfigsRequest()
+	}
+	resp := NewDescribeInstanceTypeConfigsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DescribeZoneInstanceConfigInfosRequest 查询可用区机型售卖信息请求参数
+type DescribeZoneInstanceConfigInfosRequest struct {
+	*tcHttp.BaseRequest
+	Filters []*Filter `json:"Filters,omitempty" name:"Filters"` // 过滤条件, 如 zone、instance-family、instance-type、instance-charge-type
+}
+
+// DescribeZoneInstanceConfigInfosResponse 查询可用区机型售卖信息响应结果
+type DescribeZoneInstanceConfigInfosResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		InstanceTypeQuotaSet []*InstanceTypeQuotaItem `json:"InstanceTypeQuotaSet,omitempty"` // 机型售卖信息列表
+		RequestId            string                   `json:"RequestId,omitempty"`            // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeZoneInstanceConfigInfosRequest 实例化
+func NewDescribeZoneInstanceConfigInfosRequest() *DescribeZoneInstanceConfigInfosRequest {
+	req := &DescribeZoneInstanceConfigInfosRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DescribeZoneInstanceConfigInfos")
+	return req
+}
+
+// NewDescribeZoneInstanceConfigInfosResponse 实例化
+func NewDescribeZoneInstanceConfigInfosResponse() *DescribeZoneInstanceConfigInfosResponse {
+	return &DescribeZoneInstanceConfigInfosResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeZoneInstanceConfigInfos 查询可用区机型售卖信息, 包含库存状态及价格
+func (c *Client) DescribeZoneInstanceConfigInfos(req *DescribeZoneInstanceConfigInfosRequest) (*DescribeZoneInstanceConfigInfosResponse, error) {
+	return c.DescribeZoneInstanceConfigInfosWithContext(context.Background(), req)
+}
+
+// DescribeZoneInstanceConfigInfosWithContext 查询可用区机型售卖信息, 包含库存状态及价格
+func (c *Client) DescribeZoneInstanceConfigInfosWithContext(ctx context.Context, req *DescribeZoneInstanceConfigInfosRequest) (*DescribeZoneInstanceConfigInfosResponse, error) {
+	if req == nil {
+		req = NewDescribeZoneInstanceConfigInfosRequest()
+	}
+	resp := NewDescribeZoneInstanceConfigInfosResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
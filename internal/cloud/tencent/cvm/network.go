@@ -1 +1,446 @@
 package cvm
+
+import (
+	"context"
+	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+)
+
+const VpcAPIVersion = "2017-03-12"
+
+// 弹性公网IP状态
+const (
+	AddressStatusCreating  = "CREATING"  // 创建中
+	AddressStatusBinding   = "BINDING"   // 绑定中
+	AddressStatusBind      = "BIND"      // 已绑定
+	AddressStatusUnbinding = "UNBINDING" // 解绑中
+	AddressStatusUnbind    = "UNBIND"    // 未绑定
+	AddressStatusBindEni   = "BIND_ENI"  // 绑定弹性网卡
+)
+
+// Vpc 私有网络信息
+type Vpc struct {
+	VpcId       string `json:"VpcId"`       // 私有网络ID
+	VpcName     string `json:"VpcName"`     // 私有网络名称
+	CidrBlock   string `json:"CidrBlock"`   // 网段
+	IsDefault   bool   `json:"IsDefault"`   // 是否默认私有网络
+	CreatedTime string `json:"CreatedTime"` // 创建时间
+	TagSet      []*Tag `json:"TagSet"`      // 标签
+}
+
+// Subnet 子网信息
+type Subnet struct {
+	SubnetId                string `json:"SubnetId"`                // 子网ID
+	SubnetName              string `json:"SubnetName"`              // 子网名称
+	VpcId                   string `json:"VpcId"`                   // 私有网络ID
+	CidrBlock               string `json:"CidrBlock"`               // 网段
+	Zone                    string `json:"Zone"`                    // 可用区
+	IsDefault               bool   `json:"IsDefault"`               // 是否默认子网
+	AvailableIpAddressCount uint64 `json:"AvailableIpAddressCount"` // 可用IP数
+	TotalIpAddressCount     uint64 `json:"TotalIpAddressCount"`     // 总IP数
+	CreatedTime             string `json:"CreatedTime"`             // 创建时间
+	TagSet                  []*Tag `json:"TagSet"`                  // 标签
+}
+
+// Address 弹性公网IP信息
+type Address struct {
+	AddressId          string `json:"AddressId"`          // 弹性公网IP ID
+	AddressName        string `json:"AddressName"`        // 名称
+	AddressStatus      string `json:"AddressStatus"`      // 状态
+	AddressIp          string `json:"AddressIp"`          // 公网IP地址
+	InstanceId         string `json:"InstanceId"`         // 绑定的实例ID
+	NetworkInterfaceId string `json:"NetworkInterfaceId"` // 绑定的弹性网卡ID
+	PrivateAddressIp   string `json:"PrivateAddressIp"`   // 绑定的内网IP
+	CreatedTime        string `json:"CreatedTime"`        // 创建时间
+	TagSet             []*Tag `json:"TagSet"`             // 标签
+}
+
+// PrivateIpAddressSpecification 弹性网卡内网IP信息
+type PrivateIpAddressSpecification struct {
+	PrivateIpAddress string `json:"PrivateIpAddress"` // 内网IP
+	Primary          bool   `json:"Primary"`          // 是否主IP
+	PublicIpAddress  string `json:"PublicIpAddress"`  // 公网IP
+	AddressId        string `json:"AddressId"`        // 弹性公网IP ID
+}
+
+// NetworkInterfaceAttachment 弹性网卡绑定关系
+type NetworkInterfaceAttachment struct {
+	InstanceId  string `json:"InstanceId"`  // 实例ID
+	DeviceIndex uint64 `json:"DeviceIndex"` // 网卡在主机中的序号
+}
+
+// NetworkInterface 弹性网卡信息
+type NetworkInterface struct {
+	NetworkInterfaceId   string                           `json:"NetworkInterfaceId"`   // 弹性网卡ID
+	NetworkInterfaceName string                           `json:"NetworkInterfaceName"` // 名称
+	VpcId                string                           `json:"VpcId"`                // 私有网络ID
+	SubnetId             string                           `json:"SubnetId"`             // 子网ID
+	MacAddress           string                           `json:"MacAddress"`           // MAC地址
+	State                string                           `json:"State"`                // 状态
+	Primary              bool                             `json:"Primary"`              // 是否主网卡
+	Zone                 string                           `json:"Zone"`                 // 可用区
+	Attachment           *NetworkInterfaceAttachment      `json:"Attachment"`           // 绑定关系
+	PrivateIpAddressSet  []*PrivateIpAddressSpecification `json:"PrivateIpAddressSet"`  // 内网IP信息
+	GroupSet             []string                         `json:"GroupSet"`             // 安全组
+	CreatedTime          string                           `json:"CreatedTime"`          // 创建时间
+}
+
+// DescribeVpcsRequest 查询私有网络请求参数
+type DescribeVpcsRequest struct {
+	*tcHttp.BaseRequest
+	VpcIds  []*string `json:"VpcIds,omitempty" name:"VpcIds"`   // 私有网络ID
+	Filters []*Filter `json:"Filters,omitempty" name:"Filters"` // 过滤条件, 如 vpc-name、is-default、tag-key
+	Offset  *string   `json:"Offset,omitempty" name:"Offset"`   // 偏移量
+	Limit   *string   `json:"Limit,omitempty" name:"Limit"`     // 返回数量, 最大值为100
+}
+
+// DescribeVpcsResponse 查询私有网络响应结果
+type DescribeVpcsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TotalCount uint64 `json:"TotalCount,omitempty"` // 符合条件的私有网络数量
+		VpcSet     []*Vpc `json:"VpcSet,omitempty"`     // 私有网络列表
+		RequestId  string `json:"RequestId,omitempty"`  // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeVpcsRequest 实例化
+func NewDescribeVpcsRequest() *DescribeVpcsRequest {
+	req := &DescribeVpcsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DescribeVpcs")
+	return req
+}
+
+// NewDescribeVpcsResponse 实例化
+func NewDescribeVpcsResponse() *DescribeVpcsResponse {
+	return &DescribeVpcsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeVpcs 查询私有网络
+func (c *Client) DescribeVpcs(req *DescribeVpcsRequest) (*DescribeVpcsResponse, error) {
+	return c.DescribeVpcsWithContext(context.Background(), req)
+}
+
+// DescribeVpcsWithContext 查询私有网络
+func (c *Client) DescribeVpcsWithContext(ctx context.Context, req *DescribeVpcsRequest) (*DescribeVpcsResponse, error) {
+	if req == nil {
+		req = NewDescribeVpcsRequest()
+	}
+	resp := NewDescribeVpcsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DescribeSubnetsRequest 查询子网请求参数
+type DescribeSubnetsRequest struct {
+	*tcHttp.BaseRequest
+	SubnetIds []*string `json:"SubnetIds,omitempty" name:"SubnetIds"` // 子网ID
+	Filters   []*Filter `json:"Filters,omitempty" name:"Filters"`     // 过滤条件, 如 vpc-id、zone、subnet-name、tag-key
+	Offset    *string   `json:"Offset,omitempty" name:"Offset"`       // 偏移量
+	Limit     *string   `json:"Limit,omitempty" name:"Limit"`         // 返回数量, 最大值为100
+}
+
+// DescribeSubnetsResponse 查询子网响应结果
+type DescribeSubnetsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TotalCount uint64    `json:"TotalCount,omitempty"` // 符合条件的子网数量
+		SubnetSet  []*Subnet `json:"SubnetSet,omitempty"`  // 子网列表
+		RequestId  string    `json:"RequestId,omitempty"`  // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeSubnetsRequest 实例化
+func NewDescribeSubnetsRequest() *DescribeSubnetsRequest {
+	req := &DescribeSubnetsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DescribeSubnets")
+	return req
+}
+
+// NewDescribeSubnetsResponse 实例化
+func NewDescribeSubnetsResponse() *DescribeSubnetsResponse {
+	return &DescribeSubnetsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeSubnets 查询子网
+func (c *Client) DescribeSubnets(req *DescribeSubnetsRequest) (*DescribeSubnetsResponse, error) {
+	return c.DescribeSubnetsWithContext(context.Background(), req)
+}
+
+// DescribeSubnetsWithContext 查询子网
+func (c *Client) DescribeSubnetsWithContext(ctx context.Context, req *DescribeSubnetsRequest) (*DescribeSubnetsResponse, error) {
+	if req == nil {
+		req = NewDescribeSubnetsRequest()
+	}
+	resp := NewDescribeSubnetsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DescribeAddressesRequest 查询弹性公网IP请求参数
+type DescribeAddressesRequest struct {
+	*tcHttp.BaseRequest
+	AddressIds []*string `json:"AddressIds,omitempty" name:"AddressIds"` // 弹性公网IP ID
+	Filters    []*Filter `json:"Filters,omitempty" name:"Filters"`       // 过滤条件, 如 address-status、instance-id、tag-key
+	Offset     *int64    `json:"Offset,omitempty" name:"Offset"`         // 偏移量
+	Limit      *int64    `json:"Limit,omitempty" name:"Limit"`           // 返回数量, 最大值为100
+}
+
+// DescribeAddressesResponse 查询弹性公网IP响应结果
+type DescribeAddressesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TotalCount int64      `json:"TotalCount,omitempty"` // 符合条件的弹性公网IP数量
+		AddressSet []*Address `json:"AddressSet,omitempty"` // 弹性公网IP列表
+		RequestId  string     `json:"RequestId,omitempty"`  // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeAddressesRequest 实例化
+func NewDescribeAddressesRequest() *DescribeAddressesRequest {
+	req := &DescribeAddressesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DescribeAddresses")
+	return req
+}
+
+// NewDescribeAddressesResponse 实例化
+func NewDescribeAddressesResponse() *DescribeAddressesResponse {
+	return &DescribeAddressesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeAddresses 查询弹性公网IP
+func (c *Client) DescribeAddresses(req *DescribeAddressesRequest) (*DescribeAddressesResponse, error) {
+	return c.DescribeAddressesWithContext(context.Background(), req)
+}
+
+// DescribeAddressesWithContext 查询弹性公网IP
+func (c *Client) DescribeAddressesWithContext(ctx context.Context, req *DescribeAddressesRequest) (*DescribeAddressesResponse, error) {
+	if req == nil {
+		req = NewDescribeAddressesRequest()
+	}
+	resp := NewDescribeAddressesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// AllocateAddressesRequest 申请弹性公网IP请求参数
+type AllocateAddressesRequest struct {
+	*tcHttp.BaseRequest
+	AddressCount            *int64  `json:"AddressCount,omitempty" name:"AddressCount"`                       // 申请数量, 默认为1
+	InternetChargeType      *string `json:"InternetChargeType,omitempty" name:"InternetChargeType"`           // 计费方式
+	InternetMaxBandwidthOut *int64  `json:"InternetMaxBandwidthOut,omitempty" name:"InternetMaxBandwidthOut"` // 公网出带宽上限, 单位 Mbps
+	AddressName             *string `json:"AddressName,omitempty" name:"AddressName"`                         // 名称
+	Tags                    []*Tag  `json:"Tags,omitempty" name:"Tags"`                                       // 标签
+}
+
+// AllocateAddressesResponse 申请弹性公网IP响应结果
+type AllocateAddressesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		AddressSet []string `json:"AddressSet,omitempty"` // 弹性公网IP ID列表
+		TaskId     string   `json:"TaskId,omitempty"`     // 异步任务ID
+		RequestId  string   `json:"RequestId,omitempty"`  // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewAllocateAddressesRequest 实例化
+func NewAllocateAddressesRequest() *AllocateAddressesRequest {
+	req := &AllocateAddressesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "AllocateAddresses")
+	return req
+}
+
+// NewAllocateAddressesResponse 实例化
+func NewAllocateAddressesResponse() *AllocateAddressesResponse {
+	return &AllocateAddressesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// AllocateAddresses 申请弹性公网IP
+func (c *Client) AllocateAddresses(req *AllocateAddressesRequest) (*AllocateAddressesResponse, error) {
+	return c.AllocateAddressesWithContext(context.Background(), req)
+}
+
+// AllocateAddressesWithContext 申请弹性公网IP
+func (c *Client) AllocateAddressesWithContext(ctx context.Context, req *AllocateAddressesRequest) (*AllocateAddressesResponse, error) {
+	if req == nil {
+		req = NewAllocateAddressesRequest()
+	}
+	resp := NewAllocateAddressesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// AssociateAddressRequest 绑定弹性公网IP请求参数
+type AssociateAddressRequest struct {
+	*tcHttp.BaseRequest
+	AddressId          *string `json:"AddressId,omitempty" name:"AddressId"`                   // 弹性公网IP ID
+	InstanceId         *string `json:"InstanceId,omitempty" name:"InstanceId"`                 // 实例ID, 不能与 NetworkInterfaceId 同时指定
+	NetworkInterfaceId *string `json:"NetworkInterfaceId,omitempty" name:"NetworkInterfaceId"` // 弹性网卡ID
+	PrivateIpAddress   *string `json:"PrivateIpAddress,omitempty" name:"PrivateIpAddress"`     // 弹性网卡内网IP
+}
+
+// AssociateAddressResponse 绑定弹性公网IP响应结果
+type AssociateAddressResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TaskId    string `json:"TaskId,omitempty"`    // 异步任务ID
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewAssociateAddressRequest 实例化
+func NewAssociateAddressRequest() *AssociateAddressRequest {
+	req := &AssociateAddressRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "AssociateAddress")
+	return req
+}
+
+// NewAssociateAddressResponse 实例化
+func NewAssociateAddressResponse() *AssociateAddressResponse {
+	return &AssociateAddressResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// AssociateAddress 绑定弹性公网IP
+func (c *Client) AssociateAddress(req *AssociateAddressRequest) (*AssociateAddressResponse, error) {
+	return c.AssociateAddressWithContext(context.Background(), req)
+}
+
+// AssociateAddressWithContext 绑定弹性公网IP
+func (c *Client) AssociateAddressWithContext(ctx context.Context, req *AssociateAddressRequest) (*AssociateAddressResponse, error) {
+	if req == nil {
+		req = NewAssociateAddressRequest()
+	}
+	resp := NewAssociateAddressResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DisassociateAddressRequest 解绑弹性公网IP请求参数
+type DisassociateAddressRequest struct {
+	*tcHttp.BaseRequest
+	AddressId                *string `json:"AddressId,omitempty" name:"AddressId"`                               // 弹性公网IP ID
+	ReallocateNormalPublicIp *bool   `json:"ReallocateNormalPublicIp,omitempty" name:"ReallocateNormalPublicIp"` // 解绑后是否分配普通公网IP
+}
+
+// DisassociateAddressResponse 解绑弹性公网IP响应结果
+type DisassociateAddressResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TaskId    string `json:"TaskId,omitempty"`    // 异步任务ID
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDisassociateAddressRequest 实例化
+func NewDisassociateAddressRequest() *DisassociateAddressRequest {
+	req := &DisassociateAddressRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DisassociateAddress")
+	return req
+}
+
+// NewDisassociateAddressResponse 实例化
+func NewDisassociateAddressResponse() *DisassociateAddressResponse {
+	return &DisassociateAddressResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DisassociateAddress 解绑弹性公网IP
+func (c *Client) DisassociateAddress(req *DisassociateAddressRequest) (*DisassociateAddressResponse, error) {
+	return c.DisassociateAddressWithContext(context.Background(), req)
+}
+
+// DisassociateAddressWithContext 解绑弹性公网IP
+func (c *Client) DisassociateAddressWithContext(ctx context.Context, req *DisassociateAddressRequest) (*DisassociateAddressResponse, error) {
+	if req == nil {
+		req = NewDisassociateAddressRequest()
+	}
+	resp := NewDisassociateAddressResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// ReleaseAddressesRequest 释放弹性公网IP请求参数
+type ReleaseAddressesRequest struct {
+	*tcHttp.BaseRequest
+	AddressIds []*string `json:"AddressIds,omitempty" name:"AddressIds"` // 弹性公网IP ID
+}
+
+// ReleaseAddressesResponse 释放弹性公网IP响应结果
+type ReleaseAddressesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TaskId    string `json:"TaskId,omitempty"`    // 异步任务ID
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewReleaseAddressesRequest 实例化
+func NewReleaseAddressesRequest() *ReleaseAddressesRequest {
+	req := &ReleaseAddressesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "ReleaseAddresses")
+	return req
+}
+
+// NewReleaseAddressesResponse 实例化
+func NewReleaseAddressesResponse() *ReleaseAddressesResponse {
+	return &ReleaseAddressesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// ReleaseAddresses 释放弹性公网IP
+func (c *Client) ReleaseAddresses(req *ReleaseAddressesRequest) (*ReleaseAddressesResponse, error) {
+	return c.ReleaseAddressesWithContext(context.Background(), req)
+}
+
+// ReleaseAddressesWithContext 释放弹性公网IP
+func (c *Client) ReleaseAddressesWithContext(ctx context.Context, req *ReleaseAddressesRequest) (*ReleaseAddressesResponse, error) {
+	if req == nil {
+		req = NewReleaseAddressesRequest()
+	}
+	resp := NewReleaseAddressesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DescribeNetworkInterfacesRequest 查询弹性网卡请求参数
+type DescribeNetworkInterfacesRequest struct {
+	*tcHttp.BaseRequest
+	NetworkInterfaceIds []*string `json:"NetworkInterfaceIds,omitempty" name:"NetworkInterfaceIds"` // 弹性网卡ID
+	Filters             []*Filter `json:"Filters,omitempty" name:"Filters"`                         // 过滤条件, 如 vpc-id、subnet-id、attachment.instance-id
+	Offset              *uint64   `json:"Offset,omitempty" name:"Offset"`                           // 偏移量
+	Limit               *uint64   `json:"Limit,omitempty" name:"Limit"`                             // 返回数量, 最大值为100
+}
+
+// DescribeNetworkInterfacesResponse 查询弹性网卡响应结果
+type DescribeNetworkInterfacesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TotalCount          uint64              `json:"TotalCount,omitempty"`          // 符合条件的弹性网卡数量
+		NetworkInterfaceSet []*NetworkInterface `json:"NetworkInterfaceSet,omitempty"` // 弹性网卡列表
+		RequestId           string              `json:"RequestId,omitempty"`           // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeNetworkInterfacesRequest 实例化
+func NewDescribeNetworkInterfacesRequest() *DescribeNetworkInterfacesRequest {
+	req := &DescribeNetworkInterfacesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DescribeNetworkInterfaces")
+	return req
+}
+
+// NewDescribeNetworkInterfacesResponse 实例化
+func NewDescribeNetworkInterfacesResponse() *DescribeNetworkInterfacesResponse {
+	return &DescribeNetworkInterfacesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeNetworkInterfaces 查询弹性网卡
+func (c *Client) DescribeNetworkInterfaces(req *DescribeNetworkInterfacesRequest) (*DescribeNetworkInterfacesResponse, error) {
+	return c.DescribeNetworkInterfacesWithContext(context.Background(), req)
+}
+
+// DescribeNetworkInterfacesWithContext 查询弹性网卡
+func (c *Client) DescribeNetworkInterfacesWithContext(ctx context.Context, req *DescribeNetworkInterfacesRequest) (*DescribeNetworkInterfacesResponse, error) {
+	if req == nil {
+		req = NewDescribeNetworkInterfacesRequest()
+	}
+	resp := NewDescribeNetworkInterfacesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
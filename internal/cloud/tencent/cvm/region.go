@@ -1,8 +1,10 @@
 package cvm
 
 import (
+	"context"
 	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
 	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/profile"
 )
 
 const APIVersion = "2017-03-12"
@@ -27,6 +29,16 @@ type Client struct {
 	common.Client
 }
 
+// NewClient 实例化客户端
+func NewClient(credential common.CredentialProvider, region string, clientProfile *profile.ClientProfile) (*Client, error) {
+	client := &Client{}
+	client.Init(region).WithProvider(credential)
+	if clientProfile != nil {
+		client.WithProfile(clientProfile)
+	}
+	return client, nil
+}
+
 // ZonesRequest 可用区
 type ZonesRequest struct {
 	*tcHttp.BaseRequest
@@ -57,11 +69,16 @@ func NewZonesResponse() *ZonesResponse {
 
 // DescribeZones 查询可用区信息
 func (c *Client) DescribeZones(req *ZonesRequest) (*ZonesResponse, error) {
+	return c.DescribeZonesWithContext(context.Background(), req)
+}
+
+// DescribeZonesWithContext 查询可用区信息
+func (c *Client) DescribeZonesWithContext(ctx context.Context, req *ZonesRequest) (*ZonesResponse, error) {
 	if req == nil {
 		req = NewZonesRequest()
 	}
 	resp := NewZonesResponse()
-	err := c.Send(req, resp)
+	err := c.SendWithContext(ctx, req, resp)
 	return resp, err
 }
 
@@ -95,10 +112,15 @@ func NewRegionResponse() *RegionResponse {
 
 // DescribeRegions 查询地域信息
 func (c *Client) DescribeRegions(req *RegionRequest) (*RegionResponse, error) {
+	return c.DescribeRegionsWithContext(context.Background(), req)
+}
+
+// DescribeRegionsWithContext 查询地域信息
+func (c *Client) DescribeRegionsWithContext(ctx context.Context, req *RegionRequest) (*RegionResponse, error) {
 	if req == nil {
 		req = NewRegionRequest()
 	}
 	resp := NewRegionResponse()
-	err := c.Send(req, resp)
+	err := c.SendWithContext(ctx, req, resp)
 	return resp, err
 }
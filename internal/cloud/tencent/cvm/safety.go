@@ -1 +1,681 @@
 package cvm
+
+import (
+	"context"
+	tcHttp "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/http"
+)
+
+// 安全组规则协议
+const (
+	ProtocolTCP  = "TCP"
+	ProtocolUDP  = "UDP"
+	ProtocolICMP = "ICMP"
+	ProtocolALL  = "ALL"
+)
+
+// 安全组规则动作
+const (
+	PolicyActionAccept = "ACCEPT" // 允许
+	PolicyActionDrop   = "DROP"   // 拒绝
+)
+
+// SecurityGroup 安全组信息
+type SecurityGroup struct {
+	SecurityGroupId   string `json:"SecurityGroupId"`   // 安全组ID
+	SecurityGroupName string `json:"SecurityGroupName"` // 安全组名称
+	SecurityGroupDesc string `json:"SecurityGroupDesc"` // 安全组描述
+	ProjectId         string `json:"ProjectId"`         // 项目ID
+	IsDefault         bool   `json:"IsDefault"`         // 是否默认安全组
+	CreatedTime       string `json:"CreatedTime"`       // 创建时间
+	TagSet            []*Tag `json:"TagSet"`            // 标签
+}
+
+// SecurityGroupPolicy 安全组规则
+type SecurityGroupPolicy struct {
+	PolicyIndex       *int64  `json:"PolicyIndex,omitempty" name:"PolicyIndex"`             // 规则索引号, 删除规则时使用
+	Protocol          *string `json:"Protocol,omitempty" name:"Protocol"`                   // 协议, TCP、UDP、ICMP、ALL
+	Port              *string `json:"Port,omitempty" name:"Port"`                           // 端口, 如 22、30000-32767、ALL
+	CidrBlock         *string `json:"CidrBlock,omitempty" name:"CidrBlock"`                 // 网段或IP
+	SecurityGroupId   *string `json:"SecurityGroupId,omitempty" name:"SecurityGroupId"`     // 来源或目标安全组ID
+	Action            *string `json:"Action,omitempty" name:"Action"`                       // ACCEPT 或 DROP
+	PolicyDescription *string `json:"PolicyDescription,omitempty" name:"PolicyDescription"` // 规则描述
+}
+
+// SecurityGroupPolicySet 安全组规则集合
+type SecurityGroupPolicySet struct {
+	Version *string                `json:"Version,omitempty" name:"Version"` // 规则版本号
+	Egress  []*SecurityGroupPolicy `json:"Egress,omitempty" name:"Egress"`   // 出站规则
+	Ingress []*SecurityGroupPolicy `json:"Ingress,omitempty" name:"Ingress"` // 入站规则
+}
+
+// NewSecurityGroupPolicy 实例化允许访问的安全组规则
+func NewSecurityGroupPolicy(protocol, port, cidrBlock, description string) *SecurityGroupPolicy {
+	action := PolicyActionAccept
+	p := &SecurityGroupPolicy{
+		Protocol:          &protocol,
+		CidrBlock:         &cidrBlock,
+		Action:            &action,
+		PolicyDescription: &description,
+	}
+	if port != "" {
+		p.Port = &port
+	}
+	return p
+}
+
+// CreateSecurityGroupRequest 创建安全组请求参数
+type CreateSecurityGroupRequest struct {
+	*tcHttp.BaseRequest
+	GroupName        *string `json:"GroupName,omitempty" name:"GroupName"`               // 安全组名称
+	GroupDescription *string `json:"GroupDescription,omitempty" name:"GroupDescription"` // 安全组描述
+	ProjectId        *string `json:"ProjectId,omitempty" name:"ProjectId"`               // 项目ID, 默认0
+	Tags             []*Tag  `json:"Tags,omitempty" name:"Tags"`                         // 标签
+}
+
+// CreateSecurityGroupResponse 创建安全组响应结果
+type CreateSecurityGroupResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		SecurityGroup *SecurityGroup `json:"SecurityGroup,omitempty"` // 安全组
+		RequestId     string         `json:"RequestId,omitempty"`     // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewCreateSecurityGroupRequest 实例化
+func NewCreateSecurityGroupRequest() *CreateSecurityGroupRequest {
+	req := &CreateSecurityGroupRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "CreateSecurityGroup")
+	return req
+}
+
+// NewCreateSecurityGroupResponse 实例化
+func NewCreateSecurityGroupResponse() *CreateSecurityGroupResponse {
+	return &CreateSecurityGroupResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// CreateSecurityGroup 创建安全组
+func (c *Client) CreateSecurityGroup(req *CreateSecurityGroupRequest) (*CreateSecurityGroupResponse, error) {
+	return c.CreateSecurityGroupWithContext(context.Background(), req)
+}
+
+// CreateSecurityGroupWithContext 创建安全组
+func (c *Client) CreateSecurityGroupWithContext(ctx context.Context, req *CreateSecurityGroupRequest) (*CreateSecurityGroupResponse, error) {
+	if req == nil {
+		req = NewCreateSecurityGroupRequest()
+	}
+	resp := NewCreateSecurityGroupResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DescribeSecurityGroupsRequest 查询安全组请求参数
+type DescribeSecurityGroupsRequest struct {
+	*tcHttp.BaseRequest
+	SecurityGroupIds []*string `json:"SecurityGroupIds,omitempty" name:"SecurityGroupIds"` // 安全组ID
+	Filters          []*Filter `json:"Filters,omitempty" name:"Filters"`                   // 过滤条件, 如 security-group-name、tag-key
+	Offset           *string   `json:"Offset,omitempty" name:"Offset"`                     // 偏移量
+	Limit            *string   `json:"Limit,omitempty" name:"Limit"`                       // 返回数量, 最大值为100
+}
+
+// DescribeSecurityGroupsResponse 查询安全组响应结果
+type DescribeSecurityGroupsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		SecurityGroupSet []*SecurityGroup `json:"SecurityGroupSet,omitempty"` // 安全组列表
+		TotalCount       uint64           `json:"TotalCount,omitempty"`       // 符合条件的安全组数量
+		RequestId        string           `json:"RequestId,omitempty"`        // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeSecurityGroupsRequest 实例化
+func NewDescribeSecurityGroupsRequest() *DescribeSecurityGroupsRequest {
+	req := &DescribeSecurityGroupsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DescribeSecurityGroups")
+	return req
+}
+
+// NewDescribeSecurityGroupsResponse 实例化
+func NewDescribeSecurityGroupsResponse() *DescribeSecurityGroupsResponse {
+	return &DescribeSecurityGroupsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeSecurityGroups 查询安全组
+func (c *Client) DescribeSecurityGroups(req *DescribeSecurityGroupsRequest) (*DescribeSecurityGroupsResponse, error) {
+	return c.DescribeSecurityGroupsWithContext(context.Background(), req)
+}
+
+// DescribeSecurityGroupsWithContext 查询安全组
+func (c *Client) DescribeSecurityGroupsWithContext(ctx context.Context, req *DescribeSecurityGroupsRequest) (*DescribeSecurityGroupsResponse, error) {
+	if req == nil {
+		req = NewDescribeSecurityGroupsRequest()
+	}
+	resp := NewDescribeSecurityGroupsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DeleteSecurityGroupRequest 删除安全组请求参数
+type DeleteSecurityGroupRequest struct {
+	*tcHttp.BaseRequest
+	SecurityGroupId *string `json:"SecurityGroupId,omitempty" name:"SecurityGroupId"` // 安全组ID
+}
+
+// DeleteSecurityGroupResponse 删除安全组响应结果
+type DeleteSecurityGroupResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDeleteSecurityGroupRequest 实例化
+func NewDeleteSecurityGroupRequest() *DeleteSecurityGroupRequest {
+	req := &DeleteSecurityGroupRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DeleteSecurityGroup")
+	return req
+}
+
+// NewDeleteSecurityGroupResponse 实例化
+func NewDeleteSecurityGroupResponse() *DeleteSecurityGroupResponse {
+	return &DeleteSecurityGroupResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DeleteSecurityGroup 删除安全组
+func (c *Client) DeleteSecurityGroup(req *DeleteSecurityGroupRequest) (*DeleteSecurityGroupResponse, error) {
+	return c.DeleteSecurityGroupWithContext(context.Background(), req)
+}
+
+// DeleteSecurityGroupWithContext 删除安全组
+func (c *Client) DeleteSecurityGroupWithContext(ctx context.Context, req *DeleteSecurityGroupRequest) (*DeleteSecurityGroupResponse, error) {
+	if req == nil {
+		req = NewDeleteSecurityGroupRequest()
+	}
+	resp := NewDeleteSecurityGroupResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DescribeSecurityGroupPoliciesRequest 查询安全组规则请求参数
+type DescribeSecurityGroupPoliciesRequest struct {
+	*tcHttp.BaseRequest
+	SecurityGroupId *string `json:"SecurityGroupId,omitempty" name:"SecurityGroupId"` // 安全组ID
+}
+
+// DescribeSecurityGroupPoliciesResponse 查询安全组规则响应结果
+type DescribeSecurityGroupPoliciesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		SecurityGroupPolicySet *SecurityGroupPolicySet `json:"SecurityGroupPolicySet,omitempty"` // 安全组规则集合
+		RequestId              string                  `json:"RequestId,omitempty"`              // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeSecurityGroupPoliciesRequest 实例化
+func NewDescribeSecurityGroupPoliciesRequest() *DescribeSecurityGroupPoliciesRequest {
+	req := &DescribeSecurityGroupPoliciesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DescribeSecurityGroupPolicies")
+	return req
+}
+
+// NewDescribeSecurityGroupPoliciesResponse 实例化
+func NewDescribeSecurityGroupPoliciesResponse() *DescribeSecurityGroupPoliciesResponse {
+	return &DescribeSecurityGroupPoliciesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeSecurityGroupPolicies 查询安全组规则
+func (c *Client) DescribeSecurityGroupPolicies(req *DescribeSecurityGroupPoliciesRequest) (*DescribeSecurityGroupPoliciesResponse, error) {
+	return c.DescribeSecurityGroupPoliciesWithContext(context.Background(), req)
+}
+
+// DescribeSecurityGroupPoliciesWithContext 查询安全组规则
+func (c *Client) DescribeSecurityGroupPoliciesWithContext(ctx context.Context, req *DescribeSecurityGroupPoliciesRequest) (*DescribeSecurityGroupPoliciesResponse, error) {
+	if req == nil {
+		req = NewDescribeSecurityGroupPoliciesRequest()
+	}
+	resp := NewDescribeSecurityGroupPoliciesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// CreateSecurityGroupPoliciesRequest 添加安全组规则请求参数
+type CreateSecurityGroupPoliciesRequest struct {
+	*tcHttp.BaseRequest
+	SecurityGroupId        *string                 `json:"SecurityGroupId,omitempty" name:"SecurityGroupId"`               // 安全组ID
+	SecurityGroupPolicySet *SecurityGroupPolicySet `json:"SecurityGroupPolicySet,omitempty" name:"SecurityGroupPolicySet"` // 规则集合, 单次只能添加一个方向的规则
+}
+
+// CreateSecurityGroupPoliciesResponse 添加安全组规则响应结果
+type CreateSecurityGroupPoliciesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewCreateSecurityGroupPoliciesRequest 实例化
+func NewCreateSecurityGroupPoliciesRequest() *CreateSecurityGroupPoliciesRequest {
+	req := &CreateSecurityGroupPoliciesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "CreateSecurityGroupPolicies")
+	return req
+}
+
+// NewCreateSecurityGroupPoliciesResponse 实例化
+func NewCreateSecurityGroupPoliciesResponse() *CreateSecurityGroupPoliciesResponse {
+	return &CreateSecurityGroupPoliciesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// CreateSecurityGroupPolicies 添加安全组规则
+func (c *Client) CreateSecurityGroupPolicies(req *CreateSecurityGroupPoliciesRequest) (*CreateSecurityGroupPoliciesResponse, error) {
+	return c.CreateSecurityGroupPoliciesWithContext(context.Background(), req)
+}
+
+// CreateSecurityGroupPoliciesWithContext 添加安全组规则
+func (c *Client) CreateSecurityGroupPoliciesWithContext(ctx context.Context, req *CreateSecurityGroupPoliciesRequest) (*CreateSecurityGroupPoliciesResponse, error) {
+	if req == nil {
+		req = NewCreateSecurityGroupPoliciesRequest()
+	}
+	resp := NewCreateSecurityGroupPoliciesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DeleteSecurityGroupPoliciesRequest 删除安全组规则请求参数
+type DeleteSecurityGroupPoliciesRequest struct {
+	*tcHttp.BaseRequest
+	SecurityGroupId        *string                 `json:"SecurityGroupId,omitempty" name:"SecurityGroupId"`               // 安全组ID
+	SecurityGroupPolicySet *SecurityGroupPolicySet `json:"SecurityGroupPolicySet,omitempty" name:"SecurityGroupPolicySet"` // 规则集合, 可按 PolicyIndex 或规则内容删除
+}
+
+// DeleteSecurityGroupPoliciesResponse 删除安全组规则响应结果
+type DeleteSecurityGroupPoliciesResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDeleteSecurityGroupPoliciesRequest 实例化
+func NewDeleteSecurityGroupPoliciesRequest() *DeleteSecurityGroupPoliciesRequest {
+	req := &DeleteSecurityGroupPoliciesRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("vpc", VpcAPIVersion, "DeleteSecurityGroupPolicies")
+	return req
+}
+
+// NewDeleteSecurityGroupPoliciesResponse 实例化
+func NewDeleteSecurityGroupPoliciesResponse() *DeleteSecurityGroupPoliciesResponse {
+	return &DeleteSecurityGroupPoliciesResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DeleteSecurityGroupPolicies 删除安全组规则
+func (c *Client) DeleteSecurityGroupPolicies(req *DeleteSecurityGroupPoliciesRequest) (*DeleteSecurityGroupPoliciesResponse, error) {
+	return c.DeleteSecurityGroupPoliciesWithContext(context.Background(), req)
+}
+
+// DeleteSecurityGroupPoliciesWithContext 删除安全组规则
+func (c *Client) DeleteSecurityGroupPoliciesWithContext(ctx context.Context, req *DeleteSecurityGroupPoliciesRequest) (*DeleteSecurityGroupPoliciesResponse, error) {
+	if req == nil {
+		req = NewDeleteSecurityGroupPoliciesRequest()
+	}
+	resp := NewDeleteSecurityGroupPoliciesResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// AssociateSecurityGroupsRequest 实例绑定安全组请求参数
+type AssociateSecurityGroupsRequest struct {
+	*tcHttp.BaseRequest
+	SecurityGroupIds []*string `json:"SecurityGroupIds,omitempty" name:"SecurityGroupIds"` // 安全组ID
+	InstanceIds      []*string `json:"InstanceIds,omitempty" name:"InstanceIds"`           // 实例ID, 每次最多100个
+}
+
+// AssociateSecurityGroupsResponse 实例绑定安全组响应结果
+type AssociateSecurityGroupsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewAssociateSecurityGroupsRequest 实例化
+func NewAssociateSecurityGroupsRequest() *AssociateSecurityGroupsRequest {
+	req := &AssociateSecurityGroupsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "AssociateSecurityGroups")
+	return req
+}
+
+// NewAssociateSecurityGroupsResponse 实例化
+func NewAssociateSecurityGroupsResponse() *AssociateSecurityGroupsResponse {
+	return &AssociateSecurityGroupsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// AssociateSecurityGroups 实例绑定安全组
+func (c *Client) AssociateSecurityGroups(req *AssociateSecurityGroupsRequest) (*AssociateSecurityGroupsResponse, error) {
+	return c.AssociateSecurityGroupsWithContext(context.Background(), req)
+}
+
+// AssociateSecurityGroupsWithContext 实例绑定安全组
+func (c *Client) AssociateSecurityGroupsWithContext(ctx context.Context, req *AssociateSecurityGroupsRequest) (*AssociateSecurityGroupsResponse, error) {
+	if req == nil {
+		req = NewAssociateSecurityGroupsRequest()
+	}
+	resp := NewAssociateSecurityGroupsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DisassociateSecurityGroupsRequest 实例解绑安全组请求参数
+type DisassociateSecurityGroupsRequest struct {
+	*tcHttp.BaseRequest
+	SecurityGroupIds []*string `json:"SecurityGroupIds,omitempty" name:"SecurityGroupIds"` // 安全组ID
+	InstanceIds      []*string `json:"InstanceIds,omitempty" name:"InstanceIds"`           // 实例ID, 每次最多100个
+}
+
+// DisassociateSecurityGroupsResponse 实例解绑安全组响应结果
+type DisassociateSecurityGroupsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDisassociateSecurityGroupsRequest 实例化
+func NewDisassociateSecurityGroupsRequest() *DisassociateSecurityGroupsRequest {
+	req := &DisassociateSecurityGroupsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DisassociateSecurityGroups")
+	return req
+}
+
+// NewDisassociateSecurityGroupsResponse 实例化
+func NewDisassociateSecurityGroupsResponse() *DisassociateSecurityGroupsResponse {
+	return &DisassociateSecurityGroupsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DisassociateSecurityGroups 实例解绑安全组
+func (c *Client) DisassociateSecurityGroups(req *DisassociateSecurityGroupsRequest) (*DisassociateSecurityGroupsResponse, error) {
+	return c.DisassociateSecurityGroupsWithContext(context.Background(), req)
+}
+
+// DisassociateSecurityGroupsWithContext 实例解绑安全组
+func (c *Client) DisassociateSecurityGroupsWithContext(ctx context.Context, req *DisassociateSecurityGroupsRequest) (*DisassociateSecurityGroupsResponse, error) {
+	if req == nil {
+		req = NewDisassociateSecurityGroupsRequest()
+	}
+	resp := NewDisassociateSecurityGroupsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// KeyPair 密钥对信息
+type KeyPair struct {
+	KeyId                 string   `json:"KeyId"`                 // 密钥对ID
+	KeyName               string   `json:"KeyName"`               // 密钥对名称
+	ProjectId             int64    `json:"ProjectId"`             // 项目ID
+	Description           string   `json:"Description"`           // 描述
+	PublicKey             string   `json:"PublicKey"`             // 公钥
+	PrivateKey            string   `json:"PrivateKey"`            // 私钥, 仅创建时返回, 腾讯云不保存
+	AssociatedInstanceIds []string `json:"AssociatedInstanceIds"` // 绑定的实例ID
+	CreatedTime           string   `json:"CreatedTime"`           // 创建时间
+	Tags                  []*Tag   `json:"Tags"`                  // 标签
+}
+
+// DescribeKeyPairsRequest 查询密钥对请求参数
+type DescribeKeyPairsRequest struct {
+	*tcHttp.BaseRequest
+	KeyIds  []*string `json:"KeyIds,omitempty" name:"KeyIds"`   // 密钥对ID
+	Filters []*Filter `json:"Filters,omitempty" name:"Filters"` // 过滤条件, 如 project-id、key-name、tag-key
+	Offset  *int64    `json:"Offset,omitempty" name:"Offset"`   // 偏移量, 默认为0
+	Limit   *int64    `json:"Limit,omitempty" name:"Limit"`     // 返回数量, 默认为20, 最大值为100
+}
+
+// DescribeKeyPairsResponse 查询密钥对响应结果
+type DescribeKeyPairsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		TotalCount int64      `json:"TotalCount,omitempty"` // 符合条件的密钥对数量
+		KeyPairSet []*KeyPair `json:"KeyPairSet,omitempty"` // 密钥对列表
+		RequestId  string     `json:"RequestId,omitempty"`  // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDescribeKeyPairsRequest 实例化
+func NewDescribeKeyPairsRequest() *DescribeKeyPairsRequest {
+	req := &DescribeKeyPairsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DescribeKeyPairs")
+	return req
+}
+
+// NewDescribeKeyPairsResponse 实例化
+func NewDescribeKeyPairsResponse() *DescribeKeyPairsResponse {
+	return &DescribeKeyPairsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DescribeKeyPairs 查询密钥对
+func (c *Client) DescribeKeyPairs(req *DescribeKeyPairsRequest) (*DescribeKeyPairsResponse, error) {
+	return c.DescribeKeyPairsWithContext(context.Background(), req)
+}
+
+// DescribeKeyPairsWithContext 查询密钥对
+func (c *Client) DescribeKeyPairsWithContext(ctx context.Context, req *DescribeKeyPairsRequest) (*DescribeKeyPairsResponse, error) {
+	if req == nil {
+		req = NewDescribeKeyPairsRequest()
+	}
+	resp := NewDescribeKeyPairsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// CreateKeyPairRequest 创建密钥对请求参数
+type CreateKeyPairRequest struct {
+	*tcHttp.BaseRequest
+	KeyName          *string             `json:"KeyName,omitempty" name:"KeyName"`                   // 密钥对名称, 由字母、数字和下划线组成
+	ProjectId        *int64              `json:"ProjectId,omitempty" name:"ProjectId"`               // 项目ID, 默认0
+	TagSpecification []*TagSpecification `json:"TagSpecification,omitempty" name:"TagSpecification"` // 标签
+}
+
+// CreateKeyPairResponse 创建密钥对响应结果
+type CreateKeyPairResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		KeyPair   *KeyPair `json:"KeyPair,omitempty"`   // 密钥对, 包含私钥
+		RequestId string   `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewCreateKeyPairRequest 实例化
+func NewCreateKeyPairRequest() *CreateKeyPairRequest {
+	req := &CreateKeyPairRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "CreateKeyPair")
+	return req
+}
+
+// NewCreateKeyPairResponse 实例化
+func NewCreateKeyPairResponse() *CreateKeyPairResponse {
+	return &CreateKeyPairResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// CreateKeyPair 创建密钥对
+func (c *Client) CreateKeyPair(req *CreateKeyPairRequest) (*CreateKeyPairResponse, error) {
+	return c.CreateKeyPairWithContext(context.Background(), req)
+}
+
+// CreateKeyPairWithContext 创建密钥对
+func (c *Client) CreateKeyPairWithContext(ctx context.Context, req *CreateKeyPairRequest) (*CreateKeyPairResponse, error) {
+	if req == nil {
+		req = NewCreateKeyPairRequest()
+	}
+	resp := NewCreateKeyPairResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// ImportKeyPairRequest 导入密钥对请求参数
+type ImportKeyPairRequest struct {
+	*tcHttp.BaseRequest
+	KeyName          *string             `json:"KeyName,omitempty" name:"KeyName"`                   // 密钥对名称
+	ProjectId        *int64              `json:"ProjectId,omitempty" name:"ProjectId"`               // 项目ID, 必填, 默认项目为0
+	PublicKey        *string             `json:"PublicKey,omitempty" name:"PublicKey"`               // 公钥内容, OpenSSH RSA 格式
+	TagSpecification []*TagSpecification `json:"TagSpecification,omitempty" name:"TagSpecification"` // 标签
+}
+
+// ImportKeyPairResponse 导入密钥对响应结果
+type ImportKeyPairResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		KeyId     string `json:"KeyId,omitempty"`     // 密钥对ID
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewImportKeyPairRequest 实例化
+func NewImportKeyPairRequest() *ImportKeyPairRequest {
+	req := &ImportKeyPairRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "ImportKeyPair")
+	return req
+}
+
+// NewImportKeyPairResponse 实例化
+func NewImportKeyPairResponse() *ImportKeyPairResponse {
+	return &ImportKeyPairResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// ImportKeyPair 导入密钥对
+func (c *Client) ImportKeyPair(req *ImportKeyPairRequest) (*ImportKeyPairResponse, error) {
+	return c.ImportKeyPairWithContext(context.Background(), req)
+}
+
+// ImportKeyPairWithContext 导入密钥对
+func (c *Client) ImportKeyPairWithContext(ctx context.Context, req *ImportKeyPairRequest) (*ImportKeyPairResponse, error) {
+	if req == nil {
+		req = NewImportKeyPairRequest()
+	}
+	resp := NewImportKeyPairResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// AssociateInstancesKeyPairsRequest 实例绑定密钥对请求参数
+type AssociateInstancesKeyPairsRequest struct {
+	*tcHttp.BaseRequest
+	InstanceIds []*string `json:"InstanceIds,omitempty" name:"InstanceIds"` // 实例ID, 每次最多100个
+	KeyIds      []*string `json:"KeyIds,omitempty" name:"KeyIds"`           // 密钥对ID, 每次最多100个
+	ForceStop   *bool     `json:"ForceStop,omitempty" name:"ForceStop"`     // 是否强制关机, 运行中的实例需关机后才能绑定
+}
+
+// AssociateInstancesKeyPairsResponse 实例绑定密钥对响应结果
+type AssociateInstancesKeyPairsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewAssociateInstancesKeyPairsRequest 实例化
+func NewAssociateInstancesKeyPairsRequest() *AssociateInstancesKeyPairsRequest {
+	req := &AssociateInstancesKeyPairsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "AssociateInstancesKeyPairs")
+	return req
+}
+
+// NewAssociateInstancesKeyPairsResponse 实例化
+func NewAssociateInstancesKeyPairsResponse() *AssociateInstancesKeyPairsResponse {
+	return &AssociateInstancesKeyPairsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// AssociateInstancesKeyPairs 实例绑定密钥对
+func (c *Client) AssociateInstancesKeyPairs(req *AssociateInstancesKeyPairsRequest) (*AssociateInstancesKeyPairsResponse, error) {
+	return c.AssociateInstancesKeyPairsWithContext(context.Background(), req)
+}
+
+// AssociateInstancesKeyPairsWithContext 实例绑定密钥对
+func (c *Client) AssociateInstancesKeyPairsWithContext(ctx context.Context, req *AssociateInstancesKeyPairsRequest) (*AssociateInstancesKeyPairsResponse, error) {
+	if req == nil {
+		req = NewAssociateInstancesKeyPairsRequest()
+	}
+	resp := NewAssociateInstancesKeyPairsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DisassociateInstancesKeyPairsRequest 实例解绑密钥对请求参数
+type DisassociateInstancesKeyPairsRequest struct {
+	*tcHttp.BaseRequest
+	InstanceIds []*string `json:"InstanceIds,omitempty" name:"InstanceIds"` // 实例ID, 每次最多100个
+	KeyIds      []*string `json:"KeyIds,omitempty" name:"KeyIds"`           // 密钥对ID, 每次最多100个
+	ForceStop   *bool     `json:"ForceStop,omitempty" name:"ForceStop"`     // 是否强制关机
+}
+
+// DisassociateInstancesKeyPairsResponse 实例解绑密钥对响应结果
+type DisassociateInstancesKeyPairsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDisassociateInstancesKeyPairsRequest 实例化
+func NewDisassociateInstancesKeyPairsRequest() *DisassociateInstancesKeyPairsRequest {
+	req := &DisassociateInstancesKeyPairsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DisassociateInstancesKeyPairs")
+	return req
+}
+
+// NewDisassociateInstancesKeyPairsResponse 实例化
+func NewDisassociateInstancesKeyPairsResponse() *DisassociateInstancesKeyPairsResponse {
+	return &DisassociateInstancesKeyPairsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DisassociateInstancesKeyPairs 实例解绑密钥对
+func (c *Client) DisassociateInstancesKeyPairs(req *DisassociateInstancesKeyPairsRequest) (*DisassociateInstancesKeyPairsResponse, error) {
+	return c.DisassociateInstancesKeyPairsWithContext(context.Background(), req)
+}
+
+// DisassociateInstancesKeyPairsWithContext 实例解绑密钥对
+func (c *Client) DisassociateInstancesKeyPairsWithContext(ctx context.Context, req *DisassociateInstancesKeyPairsRequest) (*DisassociateInstancesKeyPairsResponse, error) {
+	if req == nil {
+		req = NewDisassociateInstancesKeyPairsRequest()
+	}
+	resp := NewDisassociateInstancesKeyPairsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
+
+// DeleteKeyPairsRequest 删除密钥对请求参数
+type DeleteKeyPairsRequest struct {
+	*tcHttp.BaseRequest
+	KeyIds []*string `json:"KeyIds,omitempty" name:"KeyIds"` // 密钥对ID, 每次最多100个, 已绑定实例的密钥对不能删除
+}
+
+// DeleteKeyPairsResponse 删除密钥对响应结果
+type DeleteKeyPairsResponse struct {
+	*tcHttp.BaseResponse
+	Response *struct {
+		RequestId string `json:"RequestId,omitempty"` // 唯一请求 ID
+	} `json:"Response"`
+}
+
+// NewDeleteKeyPairsRequest 实例化
+func NewDeleteKeyPairsRequest() *DeleteKeyPairsRequest {
+	req := &DeleteKeyPairsRequest{BaseRequest: &tcHttp.BaseRequest{}}
+	req.Init().WithApiInfo("cvm", APIVersion, "DeleteKeyPairs")
+	return req
+}
+
+// NewDeleteKeyPairsResponse 实例化
+func NewDeleteKeyPairsResponse() *DeleteKeyPairsResponse {
+	return &DeleteKeyPairsResponse{BaseResponse: &tcHttp.BaseResponse{}}
+}
+
+// DeleteKeyPairs 删除密钥对
+func (c *Client) DeleteKeyPairs(req *DeleteKeyPairsRequest) (*DeleteKeyPairsResponse, error) {
+	return c.DeleteKeyPairsWithContext(context.Background(), req)
+}
+
+// DeleteKeyPairsWithContext 删除密钥对
+func (c *Client) DeleteKeyPairsWithContext(ctx context.Context, req *DeleteKeyPairsRequest) (*DeleteKeyPairsResponse, error) {
+	if req == nil {
+		req = NewDeleteKeyPairsRequest()
+	}
+	resp := NewDeleteKeyPairsResponse()
+	err := c.SendWithContext(ctx, req, resp)
+	return resp, err
+}
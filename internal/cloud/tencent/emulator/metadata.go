@@ -0,0 +1,42 @@
+package emulator
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// metadataPrefix 实例元数据路径前缀
+const metadataPrefix = "/latest/meta-data/"
+
+// SetMetadata 设置实例元数据, 如 instance-id、spot/termination-time, value 为空时删除
+// 模拟服务同时作为元数据服务, 元数据地址即 URL
+func (s *Server) SetMetadata(name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.metadata == nil {
+		s.metadata = make(map[string]string)
+	}
+	if value == "" {
+		delete(s.metadata, name)
+		return
+	}
+	s.metadata[name] = value
+}
+
+// NotifyTermination 模拟竞价实例回收通知, 回收时间为北京时间
+func (s *Server) NotifyTermination(at time.Time) {
+	s.SetMetadata("spot/termination-time", at.In(time.FixedZone("CST", 8*3600)).Format("2006-01-02 15:04:05"))
+}
+
+// serveMetadata 返回实例元数据, 不存在时返回 404
+func (s *Server) serveMetadata(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	value, ok := s.metadata[strings.TrimPrefix(r.URL.Path, metadataPrefix)]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write([]byte(value))
+}
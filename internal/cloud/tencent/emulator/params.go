@@ -0,0 +1,101 @@
+package emulator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// decodeParams 按 name 标签将表单参数解析到 value, 没有 name 标签时使用字段名称
+func decodeParams(params map[string]string, key string, value reflect.Value) error {
+	if !hasParam(params, key) {
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return decodeParams(params, key, value.Elem())
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name, ok := field.Tag.Lookup("name")
+			if !ok {
+				name = field.Name
+			}
+			if err := decodeParams(params, join(key, name), value.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		list := reflect.MakeSlice(value.Type(), 0, 0)
+		for i := 0; hasParam(params, join(key, strconv.Itoa(i))); i++ {
+			item := reflect.New(value.Type().Elem()).Elem()
+			if err := decodeParams(params, join(key, strconv.Itoa(i)), item); err != nil {
+				return err
+			}
+			list = reflect.Append(list, item)
+		}
+		value.Set(list)
+		return nil
+	}
+
+	s := params[key]
+	var err error
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, 64)
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 64)
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, 64)
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("emulator: unsupported parameter %s of type %s", key, value.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("emulator: invalid parameter %s=%s, %w", key, s, err)
+	}
+	return nil
+}
+
+// hasParam 是否存在参数 key 或其子参数, key 为空时表示根
+func hasParam(params map[string]string, key string) bool {
+	if key == "" {
+		return true
+	}
+	if _, ok := params[key]; ok {
+		return true
+	}
+	for k := range params {
+		if strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,252 @@
+package emulator
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/profile"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 模拟服务默认密钥
+const (
+	DefaultSecretId  = "AKIDEMULATOR00000000000000000000"
+	DefaultSecretKey = "emulator0000000000000000000000000"
+)
+
+// MaxSkew 请求时间戳与服务端时间允许的最大偏差, 与腾讯云一致
+const MaxSkew = 5 * time.Minute
+
+//go:embed recorded/*.json
+var recorded embed.FS
+
+// Error 腾讯云格式的错误, HttpStatus 非0时直接返回该 http 状态码, 用于模拟网关错误
+type Error struct {
+	HttpStatus int    // http 状态码
+	Code       string // 错误码, 如 LimitExceeded、ResourceInsufficient.SpecifiedInstanceType
+	Message    string // 错误信息
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Request 已通过签名校验的请求
+type Request struct {
+	Action          string            // 接口名称
+	Version         string            // 接口版本
+	Region          string            // 地域
+	Service         string            // 服务, 仅 TC3-HMAC-SHA256 签名时可知, 如 cvm、vpc
+	SignatureMethod string            // 签名方法
+	Token           string            // 临时证书 Token
+	Params          map[string]string // 表单或 URL 参数, TC3-HMAC-SHA256 签名的 POST 请求为空
+	Body            []byte            // 请求体
+}
+
+// Decode 解析请求参数到 v, JSON 请求体按 json 标签解析, 表单或 URL 参数按 name 标签解析, 如 Filters.0.Values.1
+func (r *Request) Decode(v interface{}) error {
+	if r.Params == nil {
+		return json.Unmarshal(r.Body, v)
+	}
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("emulator: decode %s into non-pointer %T", r.Action, v)
+	}
+	return decodeParams(r.Params, "", value.Elem())
+}
+
+// Handler 处理接口请求, 返回值序列化后作为 Response 内容, 返回 *Error 时按腾讯云错误格式返回
+type Handler func(req *Request) (interface{}, error)
+
+// Server 基于 httptest 的腾讯云 API 模拟服务, 校验签名后按接口名称返回录制的响应, 并发安全
+type Server struct {
+	*httptest.Server
+	SecretId  string // 校验签名使用的 SecretId
+	SecretKey string // 校验签名使用的 SecretKey
+	Token     string // 非空时校验临时证书 Token
+
+	mu        sync.Mutex
+	seq       int64
+	handlers  map[string]Handler
+	responses map[string]json.RawMessage
+	failures  map[string][]*Error
+	requests  []*Request
+	metadata  map[string]string
+}
+
+// NewServer 启动模拟服务, 预置 recorded 目录下录制的 CVM、VPC 响应, 使用完毕后需调用 Close
+func NewServer(secretId, secretKey string) *Server {
+	s := &Server{
+		SecretId:  secretId,
+		SecretKey: secretKey,
+		handlers:  make(map[string]Handler),
+		responses: make(map[string]json.RawMessage),
+		failures:  make(map[string][]*Error),
+	}
+	files, err := recorded.ReadDir("recorded")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		b, err := recorded.ReadFile(path.Join("recorded", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		s.responses[strings.TrimSuffix(file.Name(), ".json")] = b
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Handle 设置接口处理函数, 优先于录制的响应
+func (s *Server) Handle(action string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[action] = handler
+}
+
+// Record 设置接口返回的响应内容, 不含 RequestId
+func (s *Server) Record(action string, response interface{}) {
+	b, err := json.Marshal(response)
+	if err != nil {
+		panic(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[action] = b
+}
+
+// Fail 接口接下来的 times 次请求返回 err
+func (s *Server) Fail(action string, err *Error, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < times; i++ {
+		s.failures[action] = append(s.failures[action], err)
+	}
+}
+
+// Requests 已通过签名校验的请求, 按接收顺序
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Request(nil), s.requests...)
+}
+
+// Credential 与模拟服务匹配的访问密钥
+func (s *Server) Credential() *common.Credential {
+	return &common.Credential{SecretId: s.SecretId, SecretKey: s.SecretKey, Token: s.Token}
+}
+
+// ClientProfile 指向模拟服务的客户端配置, signMethod 为空时使用 TC3-HMAC-SHA256
+func (s *Server) ClientProfile(signMethod string) *profile.ClientProfile {
+	cpf := profile.NewClientProfile()
+	cpf.HttpProfile.Endpoint = strings.TrimPrefix(s.URL, "http://")
+	cpf.HttpProfile.Scheme = "HTTP"
+	if signMethod != "" {
+		cpf.SignMethod = signMethod
+	}
+	return cpf
+}
+
+// ServeHTTP 校验签名, 按接口名称路由, 实例元数据请求无需签名
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, metadataPrefix) {
+		s.serveMetadata(w, r)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, &Error{Code: "InvalidParameter", Message: err.Error()})
+		return
+	}
+	var req *Request
+	var e *Error
+	if strings.HasPrefix(r.Header.Get("Authorization"), signMethodV3) {
+		req, e = s.verifyV3(r, body)
+	} else {
+		req, e = s.verifyV1(r, body)
+	}
+	if e != nil {
+		s.writeError(w, e)
+		return
+	}
+	if req.Action == "" {
+		s.writeError(w, &Error{Code: "MissingParameter", Message: "The request is missing a required parameter `Action`."})
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	if failures := s.failures[req.Action]; len(failures) > 0 {
+		s.failures[req.Action] = failures[1:]
+		s.mu.Unlock()
+		s.writeError(w, failures[0])
+		return
+	}
+	handler, response := s.handlers[req.Action], s.responses[req.Action]
+	s.mu.Unlock()
+
+	if handler != nil {
+		result, err := handler(req)
+		if err != nil {
+			if e, ok := err.(*Error); ok {
+				s.writeError(w, e)
+			} else {
+				s.writeError(w, &Error{Code: "InternalError", Message: err.Error()})
+			}
+			return
+		}
+		if response, err = json.Marshal(result); err != nil {
+			s.writeError(w, &Error{Code: "InternalError", Message: err.Error()})
+			return
+		}
+	}
+	if response == nil {
+		s.writeError(w, &Error{Code: "InvalidAction", Message: fmt.Sprintf("The action `%s` does not exist.", req.Action)})
+		return
+	}
+	s.write(w, response)
+}
+
+// write 返回 {"Response": {...,"RequestId": ""}}
+func (s *Server) write(w http.ResponseWriter, response json.RawMessage) {
+	fields := make(map[string]json.RawMessage)
+	if len(response) > 0 && string(response) != "null" {
+		if err := json.Unmarshal(response, &fields); err != nil {
+			s.writeError(w, &Error{Code: "InternalError", Message: err.Error()})
+			return
+		}
+	}
+	fields["RequestId"], _ = json.Marshal(s.requestId())
+	b, _ := json.Marshal(map[string]interface{}{"Response": fields})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// writeError 返回 {"Response": {"Error": {"Code": "", "Message": ""}, "RequestId": ""}}
+func (s *Server) writeError(w http.ResponseWriter, e *Error) {
+	if e.HttpStatus != 0 && e.HttpStatus != http.StatusOK {
+		http.Error(w, e.Message, e.HttpStatus)
+		return
+	}
+	b, _ := json.Marshal(map[string]interface{}{
+		"Error": map[string]string{"Code": e.Code, "Message": e.Message},
+	})
+	s.write(w, b)
+}
+
+// requestId 生成请求ID
+func (s *Server) requestId() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.seq)
+}
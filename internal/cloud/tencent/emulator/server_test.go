@@ -0,0 +1,174 @@
+package emulator_test
+
+import (
+	"errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	tcerr "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/emulator"
+	"net/http"
+	"testing"
+)
+
+// signMethods 客户端支持的签名方法及请求方式
+var signMethods = []struct {
+	signMethod string
+	reqMethod  string
+}{
+	{"TC3-HMAC-SHA256", http.MethodPost},
+	{"TC3-HMAC-SHA256", http.MethodGet},
+	{common.HmacSHA1, http.MethodGet},
+	{common.HmacSHA1, http.MethodPost},
+	{common.HmacSHA256, http.MethodPost},
+}
+
+// newClient 使用 credential 访问模拟服务的客户端, 不重试
+func newClient(t *testing.T, s *emulator.Server, credential *common.Credential, signMethod, reqMethod string) *cvm.Client {
+	t.Helper()
+	cpf := s.ClientProfile(signMethod)
+	cpf.HttpProfile.ReqMethod = reqMethod
+	cpf.RetryProfile.MaxAttempts = 1
+	client, err := cvm.NewClient(credential, "ap-guangzhou", cpf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// sdkError err 中的腾讯云错误
+func sdkError(t *testing.T, err error) *tcerr.TencentCloudSDKError {
+	t.Helper()
+	var sdkErr *tcerr.TencentCloudSDKError
+	if !errors.As(err, &sdkErr) {
+		t.Fatalf("err = %v, want TencentCloudSDKError", err)
+	}
+	return sdkErr
+}
+
+func TestSignatureMethods(t *testing.T) {
+	for _, m := range signMethods {
+		t.Run(m.signMethod+"/"+m.reqMethod, func(t *testing.T) {
+			s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+			defer s.Close()
+			client := newClient(t, s, s.Credential(), m.signMethod, m.reqMethod)
+			resp, err := client.DescribeZones(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Response.TotalCount != 3 || resp.Response.RequestId == "" {
+				t.Fatalf("response = %+v, want recorded zones with request id", resp.Response)
+			}
+			requests := s.Requests()
+			if len(requests) != 1 {
+				t.Fatalf("recorded %d requests, want 1", len(requests))
+			}
+			if req := requests[0]; req.Action != "DescribeZones" || req.SignatureMethod != m.signMethod || req.Region != "ap-guangzhou" {
+				t.Fatalf("request = %+v, want DescribeZones signed with %s in ap-guangzhou", req, m.signMethod)
+			}
+		})
+	}
+}
+
+func TestRejectsInvalidCredential(t *testing.T) {
+	for _, m := range signMethods {
+		t.Run(m.signMethod+"/"+m.reqMethod, func(t *testing.T) {
+			s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+			defer s.Close()
+			cases := []struct {
+				credential *common.Credential
+				code       string
+			}{
+				{&common.Credential{SecretId: s.SecretId, SecretKey: "wrong"}, "AuthFailure.SignatureFailure"},
+				{&common.Credential{SecretId: "AKIDunknown", SecretKey: s.SecretKey}, "AuthFailure.SecretIdNotFound"},
+			}
+			for _, c := range cases {
+				_, err := newClient(t, s, c.credential, m.signMethod, m.reqMethod).DescribeZones(nil)
+				if code := sdkError(t, err).Code; code != c.code {
+					t.Fatalf("code = %s, want %s", code, c.code)
+				}
+			}
+			if n := len(s.Requests()); n != 0 {
+				t.Fatalf("recorded %d requests with invalid signature, want 0", n)
+			}
+		})
+	}
+}
+
+func TestVerifiesToken(t *testing.T) {
+	s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+	defer s.Close()
+	s.Token = "session-token"
+	_, err := newClient(t, s, &common.Credential{SecretId: s.SecretId, SecretKey: s.SecretKey}, "", http.MethodPost).DescribeZones(nil)
+	if code := sdkError(t, err).Code; code != "AuthFailure.TokenFailure" {
+		t.Fatalf("code = %s, want AuthFailure.TokenFailure", code)
+	}
+	if _, err := newClient(t, s, s.Credential(), "", http.MethodPost).DescribeZones(nil); err != nil {
+		t.Fatal(err)
+	}
+	if requests := s.Requests(); len(requests) != 1 || requests[0].Token != s.Token {
+		t.Fatalf("requests = %+v, want one request with token", requests)
+	}
+}
+
+func TestRouting(t *testing.T) {
+	s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+	defer s.Close()
+	client := newClient(t, s, s.Credential(), "", http.MethodPost)
+
+	// 未录制的接口
+	_, err := client.AllocateAddresses(cvm.NewAllocateAddressesRequest())
+	if code := sdkError(t, err).Code; code != "InvalidAction" {
+		t.Fatalf("code = %s, want InvalidAction", code)
+	}
+
+	s.Record("DescribeZones", map[string]interface{}{"TotalCount": 1, "ZoneSet": []map[string]string{{"Zone": "ap-guangzhou-7"}}})
+	resp, err := client.DescribeZones(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Response.TotalCount != 1 || resp.Response.ZoneSet[0].Zone != "ap-guangzhou-7" {
+		t.Fatalf("response = %+v, want recorded ap-guangzhou-7", resp.Response)
+	}
+
+	// 处理函数优先于录制的响应
+	s.Handle("DescribeZones", func(req *emulator.Request) (interface{}, error) {
+		return map[string]interface{}{"TotalCount": 2}, nil
+	})
+	if resp, err = client.DescribeZones(nil); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Response.TotalCount != 2 {
+		t.Fatalf("TotalCount = %d, want 2 from handler", resp.Response.TotalCount)
+	}
+	s.Handle("DescribeZones", func(req *emulator.Request) (interface{}, error) {
+		return nil, &emulator.Error{Code: "UnauthorizedOperation", Message: "denied"}
+	})
+	_, err = client.DescribeZones(nil)
+	if code := sdkError(t, err).Code; code != "UnauthorizedOperation" {
+		t.Fatalf("code = %s, want UnauthorizedOperation", code)
+	}
+}
+
+func TestFail(t *testing.T) {
+	s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+	defer s.Close()
+	client := newClient(t, s, s.Credential(), "", http.MethodPost)
+
+	s.Fail("DescribeZones", &emulator.Error{Code: "RequestLimitExceeded", Message: "too many requests"}, 1)
+	s.Fail("DescribeZones", &emulator.Error{HttpStatus: http.StatusBadGateway, Message: "bad gateway"}, 1)
+	_, err := client.DescribeZones(nil)
+	sdkErr := sdkError(t, err)
+	if sdkErr.Code != "RequestLimitExceeded" || sdkErr.Message != "too many requests" || sdkErr.RequestId == "" {
+		t.Fatalf("error = %+v, want RequestLimitExceeded with request id", sdkErr)
+	}
+	if !errors.Is(err, tcerr.ErrThrottled) {
+		t.Fatalf("err = %v, want ErrThrottled", err)
+	}
+	_, err = client.DescribeZones(nil)
+	if sdkErr := sdkError(t, err); sdkErr.HttpStatus != http.StatusBadGateway {
+		t.Fatalf("error = %+v, want http status 502", sdkErr)
+	}
+	if _, err := client.DescribeZones(nil); err != nil {
+		t.Fatalf("third request err = %v, want nil after failures are consumed", err)
+	}
+}
@@ -0,0 +1,212 @@
+package emulator
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const signMethodV3 = "TC3-HMAC-SHA256"
+
+// 签名校验失败的错误
+var (
+	errSignature = &Error{Code: "AuthFailure.SignatureFailure", Message: "The provided credentials could not be validated. Please check your signature is correct."}
+	errSecretId  = &Error{Code: "AuthFailure.SecretIdNotFound", Message: "The SecretId is not found, please ensure that your SecretId is correct."}
+	errExpire    = &Error{Code: "AuthFailure.SignatureExpire", Message: "Signature expired. Timestamp and server time are too far apart."}
+	errToken     = &Error{Code: "AuthFailure.TokenFailure", Message: "Token verification failed."}
+)
+
+// verifyV3 校验 TC3-HMAC-SHA256 签名, 公共参数取自 X-TC-* 请求头
+func (s *Server) verifyV3(r *http.Request, body []byte) (*Request, *Error) {
+	auth := make(map[string]string)
+	for _, field := range strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), signMethodV3+" "), ", ") {
+		if kv := strings.SplitN(field, "=", 2); len(kv) == 2 {
+			auth[kv[0]] = kv[1]
+		}
+	}
+	// Credential=SecretId/Date/Service/tc3_request
+	credential := strings.Split(auth["Credential"], "/")
+	if len(credential) != 4 || credential[3] != "tc3_request" || auth["SignedHeaders"] == "" {
+		return nil, errSignature
+	}
+	secretId, date, service := credential[0], credential[1], credential[2]
+	if secretId != s.SecretId {
+		return nil, errSecretId
+	}
+	timestamp := r.Header.Get("X-TC-Timestamp")
+	t, e := s.checkTimestamp(timestamp)
+	if e != nil {
+		return nil, e
+	}
+	if date != t.UTC().Format("2006-01-02") {
+		return nil, errSignature
+	}
+
+	canonicalQuery := ""
+	if r.Method == http.MethodGet {
+		canonicalQuery = r.URL.Query().Encode()
+	}
+	var canonicalHeaders bytes.Buffer
+	for _, name := range strings.Split(auth["SignedHeaders"], ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.ToLower(strings.TrimSpace(value)) + "\n")
+	}
+	payload := string(body)
+	if r.Header.Get("X-TC-Content-SHA256") == "UNSIGNED-PAYLOAD" {
+		payload = "UNSIGNED-PAYLOAD"
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.Path,
+		canonicalQuery,
+		canonicalHeaders.String(),
+		auth["SignedHeaders"],
+		sha256hex(payload),
+	}, "\n")
+	string2sign := strings.Join([]string{
+		signMethodV3,
+		timestamp,
+		date + "/" + service + "/tc3_request",
+		sha256hex(canonicalRequest),
+	}, "\n")
+	key := hmacSum(sha256.New, []byte("TC3"+s.SecretKey), date)
+	key = hmacSum(sha256.New, key, service)
+	key = hmacSum(sha256.New, key, "tc3_request")
+	signature := hex.EncodeToString(hmacSum(sha256.New, key, string2sign))
+	if !hmac.Equal([]byte(signature), []byte(auth["Signature"])) {
+		return nil, errSignature
+	}
+	if e := s.checkToken(r.Header.Get("X-TC-Token")); e != nil {
+		return nil, e
+	}
+
+	req := &Request{
+		Action:          r.Header.Get("X-TC-Action"),
+		Version:         r.Header.Get("X-TC-Version"),
+		Region:          r.Header.Get("X-TC-Region"),
+		Service:         service,
+		SignatureMethod: signMethodV3,
+		Token:           r.Header.Get("X-TC-Token"),
+		Body:            body,
+	}
+	if r.Method == http.MethodGet {
+		req.Params = flatten(r.URL.Query())
+	}
+	return req, nil
+}
+
+// verifyV1 校验 HmacSHA1、HmacSHA256 签名, GET 请求参数取自 URL, POST 请求参数取自表单
+func (s *Server) verifyV1(r *http.Request, body []byte) (*Request, *Error) {
+	values := r.URL.Query()
+	if r.Method == http.MethodPost {
+		var err error
+		if values, err = url.ParseQuery(string(body)); err != nil {
+			return nil, &Error{Code: "InvalidParameter", Message: err.Error()}
+		}
+	}
+	params := flatten(values)
+	if params["Signature"] == "" {
+		return nil, &Error{Code: "AuthFailure.SignatureFailure", Message: "The request is missing a required parameter `Signature`."}
+	}
+	if params["SecretId"] != s.SecretId {
+		return nil, errSecretId
+	}
+	if _, e := s.checkTimestamp(params["Timestamp"]); e != nil {
+		return nil, e
+	}
+
+	h := sha1.New
+	switch params["SignatureMethod"] {
+	case "", "HmacSHA1":
+	case "HmacSHA256":
+		h = sha256.New
+	default:
+		return nil, errSignature
+	}
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if k != "Signature" && v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.WriteString(r.Method + r.Host + r.URL.Path + "?")
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteString("&")
+		}
+		buf.WriteString(k + "=" + params[k])
+	}
+	signature := base64.StdEncoding.EncodeToString(hmacSum(h, []byte(s.SecretKey), buf.String()))
+	if !hmac.Equal([]byte(signature), []byte(params["Signature"])) {
+		return nil, errSignature
+	}
+	if e := s.checkToken(params["Token"]); e != nil {
+		return nil, e
+	}
+
+	return &Request{
+		Action:          params["Action"],
+		Version:         params["Version"],
+		Region:          params["Region"],
+		SignatureMethod: params["SignatureMethod"],
+		Token:           params["Token"],
+		Params:          params,
+		Body:            body,
+	}, nil
+}
+
+// checkTimestamp 校验时间戳与服务端时间的偏差
+func (s *Server) checkTimestamp(timestamp string) (time.Time, *Error) {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, &Error{Code: "InvalidParameter", Message: "Timestamp is invalid."}
+	}
+	t := time.Unix(sec, 0)
+	if d := time.Since(t); d > MaxSkew || d < -MaxSkew {
+		return time.Time{}, errExpire
+	}
+	return t, nil
+}
+
+// checkToken 服务端设置了 Token 时校验临时证书
+func (s *Server) checkToken(token string) *Error {
+	if s.Token != "" && token != s.Token {
+		return errToken
+	}
+	return nil
+}
+
+// flatten 多值参数只取第一个
+func flatten(values url.Values) map[string]string {
+	params := make(map[string]string, len(values))
+	for k := range values {
+		params[k] = values.Get(k)
+	}
+	return params
+}
+
+func sha256hex(s string) string {
+	b := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(b[:])
+}
+
+func hmacSum(h func() hash.Hash, key []byte, s string) []byte {
+	mac := hmac.New(h, key)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
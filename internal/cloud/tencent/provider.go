@@ -0,0 +1,32 @@
+package tencent
+
+import (
+	"fmt"
+	"github.com/eadydb/k8s-aim/config"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common/profile"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+func init() {
+	cloud.Register(cloud.Tencent, NewProvider)
+}
+
+// NewProvider 根据配置构建腾讯云实现
+func NewProvider(c *config.Config) (cloud.Provider, error) {
+	if c.Tencent == nil {
+		return nil, fmt.Errorf("%w: missing tencent section", cloud.ErrNotConfigured)
+	}
+	if c.Tencent.Region == "" {
+		return nil, fmt.Errorf("%w: tencent.region is required", cloud.ErrNotConfigured)
+	}
+	client, err := cvm.NewClient(NewCredentialProvider(c.Tencent), c.Tencent.Region, profile.NewClientProfile())
+	if err != nil {
+		return nil, err
+	}
+	client.Use(common.LoggingMiddleware())
+	server := NewInstanceServer(client)
+	server.SetMetadataClient(common.NewMetadataClient(c.Tencent.MetadataEndpoint))
+	return server, nil
+}
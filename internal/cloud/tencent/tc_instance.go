@@ -1,30 +1,264 @@
 package tencent
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	tcerr "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/utils"
+	"github.com/eadydb/k8s-aim/pkg/zlog"
+	"sync"
+	"time"
+)
+
+const (
+	waitInterval = 5 * time.Second  // 轮询实例状态间隔
+	waitTimeout  = 10 * time.Minute // 等待实例状态超时时间
+	maxItems     = 100000           // 分页查询最多条数, 防止服务端分页异常时无限查询
+	adoptTimeout = time.Minute      // 按令牌查找已创建实例的超时时间
+)
+
+var _ cloud.Provider = (*InstanceServer)(nil)
+
 type InstanceServer struct {
+	client   *cvm.Client            // 腾讯云CVM客户端
+	keyStore cloud.KeyStore         // 私钥存储
+	metadata *common.MetadataClient // 实例元数据客户端
+	tags     *cloud.ResourceTags    // 资源标签, 为空时不添加标签也不按标签过滤
+	drainer  cloud.NodeDrainer      // 关机前腾空节点, 为空时不处理
+
+	mu      sync.Mutex
+	localId string // 当前实例ID, 不在 CVM 实例内运行时为空
+}
+
+// NewInstanceServer 实例化, 私钥默认保存在 ~/.k8s-aim/keys
+func NewInstanceServer(client *cvm.Client) *InstanceServer {
+	return &InstanceServer{client: client, keyStore: cloud.NewFileKeyStore(""), metadata: common.NewMetadataClient("")}
+}
+
+// SetKeyStore 设置私钥存储
+func (i *InstanceServer) SetKeyStore(store cloud.KeyStore) {
+	i.keyStore = store
+}
+
+// SetDrainer 设置节点腾空实现, 轮换密钥关机前封锁并驱逐实例对应的节点
+func (i *InstanceServer) SetDrainer(drainer cloud.NodeDrainer) {
+	i.drainer = drainer
 }
 
 // GetImage 获取镜像
-func (i *InstanceServer) GetImage(obj interface{}) (interface{}, error) {
+func (i *InstanceServer) GetImage(query *cloud.ImageQuery) (*cloud.ImageInfo, error) {
+	selector := &cvm.ImageSelector{ImageType: imageTypes[query.ImageType], InstanceType: query.InstanceType}
+	images, err := i.client.ListImages(context.Background(), selector)
+	if err != nil {
+		return nil, err
+	}
+	var infos []cloud.ImageInfo
+	for _, image := range images {
+		if image.ImageState == "" || image.ImageState == cvm.ImageStateNormal {
+			infos = append(infos, *toImageInfo(image))
+		}
+	}
+	return cloud.SelectImage(infos, query.NameRegex, query.OS)
+}
 
-	return nil, nil
+// CreateImage 使用已准备好的节点制作自定义镜像, 返回镜像ID
+func (i *InstanceServer) CreateImage(instanceId, imageName, description string) (string, error) {
+	req := cvm.NewCreateImageRequest()
+	req.InstanceId = &instanceId
+	req.ImageName = &imageName
+	req.ImageDescription = &description
+	req.ForcePoweroff = utils.StringPtr("TRUE")
+	resp, err := i.client.CreateImage(req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Response.ImageId, nil
 }
 
-// CreateInstance 创建实例
-func (i *InstanceServer) CreateInstance(obj interface{}) (interface{}, error) {
-	return nil, nil
+// CreateInstance 创建实例, 等待实例运行后返回
+// 实例带有 cloud.ClientTokenTag 标签, 未指定 ClientToken 时自动生成; 指定的令牌已创建过实例时直接接管, 不再重复创建
+// 机型或可用区售罄时返回的错误可按 cloud.ErrInsufficientStock 匹配
+func (i *InstanceServer) CreateInstance(spec *cloud.InstanceSpec) ([]cloud.InstanceInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	instanceIds, err := i.runInstances(ctx, spec)
+	if err != nil {
+		return nil, toCloudError(err)
+	}
+	instances, err := i.waitInstances(ctx, instanceIds, cvm.InstanceStateRunning)
+	if err != nil {
+		return nil, err
+	}
+	return toInstanceInfos(instances), nil
+}
+
+// runInstances 创建实例, 返回实例ID
+func (i *InstanceServer) runInstances(ctx context.Context, spec *cloud.InstanceSpec) ([]string, error) {
+	tagged := *spec
+	tagged.Tags = i.withTags(spec.Tags)
+	if spec.Spot != nil {
+		spot, err := i.withOnDemandPrice(spec)
+		if err != nil {
+			return nil, err
+		}
+		tagged.Spot = spot
+	}
+	if tagged.ClientToken == "" {
+		// 客户端会重试超时的请求, 未指定令牌时生成一个, 由腾讯云去重避免重复创建实例
+		token, err := cloud.NewClientToken()
+		if err != nil {
+			return nil, err
+		}
+		tagged.ClientToken = token
+	} else if instanceIds, err := i.adoptInstances(spec.ClientToken); err != nil || len(instanceIds) > 0 {
+		return instanceIds, err
+	}
+	tagged.Tags[cloud.ClientTokenTag] = tagged.ClientToken
+	resp, err := i.client.RunInstancesWithContext(ctx, runInstancesRequest(&tagged))
+	if err == nil {
+		return resp.Response.InstanceIdSet, nil
+	}
+	// 超时、网络及服务端错误时无法确定实例是否已创建, 按令牌标签查找
+	// 实例可能尚未出现在查询结果中, 此时返回原错误, 调用方使用同一令牌重试由腾讯云去重
+	switch tcerr.CategoryOf(err) {
+	case tcerr.CategoryNetwork, tcerr.CategoryServer:
+	default:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+	}
+	instanceIds, adoptErr := i.adoptInstances(tagged.ClientToken)
+	if adoptErr != nil || len(instanceIds) == 0 {
+		return nil, err
+	}
+	zlog.Warnf("run instances with client token %s failed, adopt created instances %v, %s", tagged.ClientToken, instanceIds, err)
+	return instanceIds, nil
+}
+
+// adoptInstances 查询令牌已创建且未销毁的实例
+func (i *InstanceServer) adoptInstances(clientToken string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), adoptTimeout)
+	defer cancel()
+	filters := append(i.tagFilters(), cvm.NewFilter("tag:"+cloud.ClientTokenTag, clientToken))
+	instances, err := i.client.ListInstances(ctx, nil, filters, maxItems)
+	if err != nil {
+		return nil, err
+	}
+	var instanceIds []string
+	for _, instance := range instances {
+		switch instance.InstanceState {
+		case cvm.InstanceStateLaunchFail, cvm.InstanceStateShutdown, cvm.InstanceStateTerminating:
+		default:
+			instanceIds = append(instanceIds, instance.InstanceId)
+		}
+	}
+	return instanceIds, nil
 }
 
 // StartInstance 启动实例
-func (i *InstanceServer) StartInstance(obj interface{}) error {
-	return nil
+func (i *InstanceServer) StartInstance(instanceIds ...string) error {
+	req := cvm.NewStartInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	_, err := i.client.StartInstances(req)
+	return err
+}
+
+// StopInstance 停止实例, 优先软关机
+func (i *InstanceServer) StopInstance(instanceIds ...string) error {
+	req := cvm.NewStopInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	req.StopType = utils.StringPtr(cvm.StopTypeSoftFirst)
+	_, err := i.client.StopInstances(req)
+	return err
+}
+
+// RestartInstance 重启实例, 优先软关机
+func (i *InstanceServer) RestartInstance(instanceIds ...string) error {
+	req := cvm.NewRebootInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	req.StopType = utils.StringPtr(cvm.StopTypeSoftFirst)
+	_, err := i.client.RebootInstances(req)
+	return err
+}
+
+// TerminateInstance 退还实例
+func (i *InstanceServer) TerminateInstance(instanceIds ...string) error {
+	req := cvm.NewTerminateInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	_, err := i.client.TerminateInstances(req)
+	return err
+}
+
+// DescribeInstances 查询实例, 未指定实例ID时只返回带有资源标签的实例
+// 腾讯云不支持同时指定实例ID和过滤条件, 指定实例ID时其余条件在本地过滤
+func (i *InstanceServer) DescribeInstances(filter *cloud.InstanceFilter) ([]cloud.InstanceInfo, error) {
+	if filter == nil {
+		filter = &cloud.InstanceFilter{}
+	}
+	var filters []*cvm.Filter
+	if len(filter.InstanceIds) == 0 {
+		if filter.Zone != "" {
+			filters = append(filters, cvm.NewFilter("zone", filter.Zone))
+		}
+		if filter.Name != "" {
+			filters = append(filters, cvm.NewFilter("instance-name", filter.Name))
+		}
+		filters = append(filters, toTagFilters(cloud.MergeTags(filter.Tags, i.selector()))...)
+	}
+	var instanceIds []*string
+	if len(filter.InstanceIds) > 0 {
+		instanceIds = utils.StringPtrs(filter.InstanceIds)
+	}
+	instances, err := i.client.ListInstances(context.Background(), instanceIds, filters, maxItems)
+	if err != nil {
+		return nil, err
+	}
+	var infos []cloud.InstanceInfo
+	for _, instance := range instances {
+		info := toInstanceInfo(instance)
+		if matchInstance(&info, filter) {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
 }
 
-// StopInstance 停止实例
-func (i *InstanceServer) StopInstance(obj interface{}) error {
-	return nil
+// waitInstances 轮询直到实例全部达到指定状态
+func (i *InstanceServer) waitInstances(ctx context.Context, ids []string, state string) ([]*cvm.Instance, error) {
+	req := cvm.NewDescribeInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(ids)
+	for {
+		resp, err := i.client.DescribeInstancesWithContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		ready := len(resp.Response.InstanceSet) == len(ids)
+		for _, instance := range resp.Response.InstanceSet {
+			if instance.InstanceState == cvm.InstanceStateLaunchFail {
+				return nil, fmt.Errorf("instance %s launch failed", instance.InstanceId)
+			}
+			if instance.InstanceState != state {
+				ready = false
+			}
+		}
+		if ready {
+			return resp.Response.InstanceSet, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait instances %v to be %s, %s", ids, state, ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
 }
 
-// RestartInstance 重启实例
-func (i *InstanceServer) RestartInstance(obj interface{}) error {
-	return nil
+// paginate 分页查询全部数据, 超过 maxItems 条时返回 common.ErrTooManyItems
+func (i *InstanceServer) paginate(fetch common.PageFunc) error {
+	pager := common.NewPager(fetch)
+	pager.MaxItems = maxItems
+	return pager.All(context.Background())
 }
@@ -0,0 +1,263 @@
+package tencent
+
+import (
+	"errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/emulator"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/utils"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runInstancesParams 测试关心的 RunInstances 参数
+type runInstancesParams struct {
+	ClientToken           string
+	TagSpecification      []*cvm.TagSpecification
+	InstanceMarketOptions *cvm.InstanceMarketOptionsRequest
+}
+
+// describeInstancesParams 测试关心的 DescribeInstances 参数
+type describeInstancesParams struct {
+	InstanceIds []string
+	Filters     []*cvm.Filter
+}
+
+// newTestServer 启动模拟服务并构建使用该服务的 InstanceServer, 重试间隔缩短为毫秒级
+func newTestServer(t *testing.T) (*emulator.Server, *InstanceServer) {
+	t.Helper()
+	s := emulator.NewServer(emulator.DefaultSecretId, emulator.DefaultSecretKey)
+	t.Cleanup(s.Close)
+	cpf := s.ClientProfile("")
+	cpf.RetryProfile.BaseDelay = time.Millisecond
+	cpf.RetryProfile.MaxDelay = time.Millisecond
+	client, err := cvm.NewClient(s.Credential(), "ap-guangzhou", cpf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, NewInstanceServer(client)
+}
+
+// handleInstances DescribeInstances 按实例ID或 tag:key 过滤返回 instances
+func handleInstances(s *emulator.Server, instances ...*cvm.Instance) {
+	s.Handle("DescribeInstances", instancesHandler(func() []*cvm.Instance { return instances }))
+}
+
+// instancesHandler DescribeInstances 按实例ID或 tag:key 过滤返回 instances 的结果
+func instancesHandler(instances func() []*cvm.Instance) emulator.Handler {
+	return func(req *emulator.Request) (interface{}, error) {
+		var params describeInstancesParams
+		if err := req.Decode(&params); err != nil {
+			return nil, err
+		}
+		var matched []*cvm.Instance
+		for _, instance := range instances() {
+			if matchParams(instance, &params) {
+				matched = append(matched, instance)
+			}
+		}
+		return map[string]interface{}{"TotalCount": len(matched), "InstanceSet": matched}, nil
+	}
+}
+
+func matchParams(instance *cvm.Instance, params *describeInstancesParams) bool {
+	if len(params.InstanceIds) > 0 && !contains(params.InstanceIds, instance.InstanceId) {
+		return false
+	}
+	for _, filter := range params.Filters {
+		if !strings.HasPrefix(*filter.Name, "tag:") {
+			continue
+		}
+		key, ok := strings.TrimPrefix(*filter.Name, "tag:"), false
+		for _, tag := range instance.Tags {
+			for _, value := range filter.Values {
+				ok = ok || *tag.Key == key && *tag.Value == *value
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+// newInstance 运行中的实例, 带有 tags 标签
+func newInstance(instanceId string, tags map[string]string) *cvm.Instance {
+	instance := &cvm.Instance{
+		InstanceId:         instanceId,
+		InstanceName:       "k8s-worker-1",
+		InstanceType:       "S5.MEDIUM4",
+		InstanceState:      cvm.InstanceStateRunning,
+		Placement:          &cvm.Placement{Zone: utils.StringPtr("ap-guangzhou-3")},
+		PrivateIpAddresses: []string{"10.0.0.12"},
+	}
+	for key, value := range tags {
+		instance.Tags = append(instance.Tags, &cvm.Tag{Key: utils.StringPtr(key), Value: utils.StringPtr(value)})
+	}
+	return instance
+}
+
+// runRequests 已收到的 RunInstances 请求参数
+func runRequests(t *testing.T, s *emulator.Server) []runInstancesParams {
+	t.Helper()
+	var params []runInstancesParams
+	for _, req := range s.Requests() {
+		if req.Action != "RunInstances" {
+			continue
+		}
+		var p runInstancesParams
+		if err := req.Decode(&p); err != nil {
+			t.Fatal(err)
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+func tokenTag(p runInstancesParams) string {
+	for _, spec := range p.TagSpecification {
+		for _, tag := range spec.Tags {
+			if *tag.Key == cloud.ClientTokenTag {
+				return *tag.Value
+			}
+		}
+	}
+	return ""
+}
+
+func TestCreateInstanceRetriesWithSameClientToken(t *testing.T) {
+	s, server := newTestServer(t)
+	s.Fail("RunInstances", &emulator.Error{Code: "InternalError", Message: "internal error"}, 1)
+	handleInstances(s, newInstance("ins-1vbe4kq6", nil))
+
+	instances, err := server.CreateInstance(&cloud.InstanceSpec{Name: "k8s-worker-1", InstanceType: "S5.MEDIUM4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].InstanceId != "ins-1vbe4kq6" {
+		t.Fatalf("instances = %+v, want ins-1vbe4kq6", instances)
+	}
+	requests := runRequests(t, s)
+	if len(requests) != 2 {
+		t.Fatalf("RunInstances sent %d times, want 2", len(requests))
+	}
+	token := requests[0].ClientToken
+	if token == "" {
+		t.Fatal("RunInstances sent without ClientToken")
+	}
+	for _, p := range requests {
+		if p.ClientToken != token || tokenTag(p) != token {
+			t.Fatalf("retry sent ClientToken %q with tag %q, want %q", p.ClientToken, tokenTag(p), token)
+		}
+	}
+}
+
+func TestCreateInstanceAdoptsExistingInstanceByClientToken(t *testing.T) {
+	s, server := newTestServer(t)
+	server.SetTags(cloud.ResourceTags{Cluster: "test"})
+	handleInstances(s,
+		newInstance("ins-other", map[string]string{cloud.ClientTokenTag: "token-2", cloud.TagCluster: "test"}),
+		newInstance("ins-adopted", map[string]string{cloud.ClientTokenTag: "token-1", cloud.TagCluster: "test", cloud.TagCreatedBy: cloud.CreatedBy}),
+	)
+
+	instances, err := server.CreateInstance(&cloud.InstanceSpec{Name: "k8s-worker-1", InstanceType: "S5.MEDIUM4", ClientToken: "token-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].InstanceId != "ins-adopted" {
+		t.Fatalf("instances = %+v, want ins-adopted", instances)
+	}
+	if requests := runRequests(t, s); len(requests) != 0 {
+		t.Fatalf("RunInstances sent %d times, want 0", len(requests))
+	}
+}
+
+func TestCreateInstanceAdoptsAfterServerError(t *testing.T) {
+	s, server := newTestServer(t)
+	// 网关超时, 请求可能已被受理
+	s.Fail("RunInstances", &emulator.Error{HttpStatus: http.StatusGatewayTimeout, Message: "gateway timeout"}, 3)
+	created := newInstance("ins-created", map[string]string{cloud.ClientTokenTag: "token-1"})
+	s.Handle("DescribeInstances", instancesHandler(func() []*cvm.Instance {
+		for _, req := range s.Requests() {
+			if req.Action == "RunInstances" {
+				return []*cvm.Instance{created}
+			}
+		}
+		return nil
+	}))
+
+	instances, err := server.CreateInstance(&cloud.InstanceSpec{Name: "k8s-worker-1", InstanceType: "S5.MEDIUM4", ClientToken: "token-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].InstanceId != "ins-created" {
+		t.Fatalf("instances = %+v, want ins-created", instances)
+	}
+	requests := runRequests(t, s)
+	if len(requests) != 3 {
+		t.Fatalf("RunInstances sent %d times, want 3", len(requests))
+	}
+	for _, p := range requests {
+		if p.ClientToken != "token-1" {
+			t.Fatalf("RunInstances sent ClientToken %q, want token-1", p.ClientToken)
+		}
+	}
+}
+
+func TestCreateInstanceInsufficientStockIsNotRetried(t *testing.T) {
+	s, server := newTestServer(t)
+	s.Fail("RunInstances", &emulator.Error{Code: "ResourceInsufficient.SpecifiedInstanceType", Message: "sold out"}, 1)
+	handleInstances(s)
+
+	_, err := server.CreateInstance(&cloud.InstanceSpec{Name: "k8s-worker-1", InstanceType: "S5.MEDIUM4", ClientToken: "token-1"})
+	if !errors.Is(err, cloud.ErrInsufficientStock) {
+		t.Fatalf("err = %v, want ErrInsufficientStock", err)
+	}
+	if requests := runRequests(t, s); len(requests) != 1 {
+		t.Fatalf("RunInstances sent %d times, want 1", len(requests))
+	}
+}
+
+func TestCreateSpotInstanceBidsOnDemandPrice(t *testing.T) {
+	s, server := newTestServer(t)
+	handleInstances(s, newInstance("ins-1vbe4kq6", nil))
+	spec := &cloud.InstanceSpec{Name: "k8s-worker-1", Zone: "ap-guangzhou-3", InstanceType: "S5.MEDIUM4", Spot: &cloud.SpotOptions{}}
+
+	if _, err := server.CreateInstance(spec); err != nil {
+		t.Fatal(err)
+	}
+	requests := runRequests(t, s)
+	if len(requests) != 1 {
+		t.Fatalf("RunInstances sent %d times, want 1", len(requests))
+	}
+	options := requests[0].InstanceMarketOptions
+	if options == nil || options.SpotOptions == nil || options.SpotOptions.MaxPrice == nil || *options.SpotOptions.MaxPrice != "0.39" {
+		t.Fatalf("InstanceMarketOptions = %+v, want max price 0.39 from instance type catalog", options)
+	}
+	if spec.Spot.MaxPrice != 0 {
+		t.Fatalf("spec max price changed to %v", spec.Spot.MaxPrice)
+	}
+}
+
+func TestCreateSpotInstanceWithoutKnownPrice(t *testing.T) {
+	s, server := newTestServer(t)
+	spec := &cloud.InstanceSpec{Name: "k8s-worker-1", Zone: "ap-guangzhou-3", InstanceType: "X9.UNKNOWN", Spot: &cloud.SpotOptions{}}
+
+	if _, err := server.CreateInstance(spec); err == nil {
+		t.Fatal("expected error for spot instance without max price")
+	}
+	if requests := runRequests(t, s); len(requests) != 0 {
+		t.Fatalf("RunInstances sent %d times, want 0", len(requests))
+	}
+}
@@ -0,0 +1,53 @@
+package tencent
+
+import (
+	"context"
+	tcerr "github.com/eadydb/k8s-aim/internal/cloud/tencent/common/errors"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+var _ cloud.InstanceTypes = (*InstanceServer)(nil)
+
+// DescribeInstanceTypes 查询可用区内按量计费机型的库存, zones 为空时查询全部可用区
+func (i *InstanceServer) DescribeInstanceTypes(zones ...string) ([]cloud.InstanceTypeOffering, error) {
+	req := cvm.NewDescribeZoneInstanceConfigInfosRequest()
+	req.Filters = []*cvm.Filter{cvm.NewFilter("instance-charge-type", cvm.InstanceChargeTypePostpaidByHour)}
+	if len(zones) > 0 {
+		req.Filters = append(req.Filters, cvm.NewFilter("zone", zones...))
+	}
+	resp, err := i.client.DescribeZoneInstanceConfigInfosWithContext(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	offerings := make([]cloud.InstanceTypeOffering, 0, len(resp.Response.InstanceTypeQuotaSet))
+	for _, item := range resp.Response.InstanceTypeQuotaSet {
+		offerings = append(offerings, toInstanceTypeOffering(item))
+	}
+	return offerings, nil
+}
+
+// stockError 售罄错误, 既可按 cloud.ErrInsufficientStock 匹配, 也保留腾讯云错误供 errors.As 使用
+type stockError struct {
+	err error
+}
+
+func (e *stockError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stockError) Unwrap() error {
+	return e.err
+}
+
+func (e *stockError) Is(target error) bool {
+	return target == cloud.ErrInsufficientStock
+}
+
+// toCloudError 腾讯云售罄类错误转换为 cloud.ErrInsufficientStock, 其他错误原样返回
+func toCloudError(err error) error {
+	if tcerr.CategoryOf(err) == tcerr.CategoryInsufficientStock {
+		return &stockError{err: err}
+	}
+	return err
+}
@@ -0,0 +1,347 @@
+package tencent
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/k8s"
+	"github.com/eadydb/k8s-aim/pkg/utils"
+	"github.com/eadydb/k8s-aim/pkg/zlog"
+	"time"
+)
+
+// 实例最新操作状态
+const (
+	operationStateOperating = "OPERATING"
+	operationStateFailed    = "FAILED"
+)
+
+var (
+	_ cloud.KeyParis   = (*InstanceServer)(nil)
+	_ cloud.KeyRotator = (*InstanceServer)(nil)
+)
+
+// DescribeKeyPairs 查询密钥对, keyIds 为空时查询全部带有资源标签的密钥对
+func (i *InstanceServer) DescribeKeyPairs(keyIds ...string) ([]cloud.KeyPair, error) {
+	var keyPairs []cloud.KeyPair
+	err := i.paginate(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		req := cvm.NewDescribeKeyPairsRequest()
+		if len(keyIds) > 0 {
+			req.KeyIds = utils.StringPtrs(keyIds)
+		} else {
+			req.Filters = i.tagFilters()
+		}
+		req.Offset, req.Limit = &offset, &limit
+		resp, err := i.client.DescribeKeyPairsWithContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, keyPair := range resp.Response.KeyPairSet {
+			keyPairs = append(keyPairs, *toKeyPair(keyPair))
+		}
+		return len(resp.Response.KeyPairSet), resp.Response.TotalCount, nil
+	})
+	return keyPairs, err
+}
+
+// CreateKeyPair 创建密钥对, 私钥以密钥对ID为名称保存到 KeyStore
+func (i *InstanceServer) CreateKeyPair(name string) (*cloud.KeyPair, error) {
+	req := cvm.NewCreateKeyPairRequest()
+	req.KeyName = &name
+	req.TagSpecification = i.keyPairTags()
+	resp, err := i.client.CreateKeyPair(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response.KeyPair == nil {
+		return nil, fmt.Errorf("create key pair returned no key pair, request id: %s", resp.Response.RequestId)
+	}
+	keyPair := toKeyPair(resp.Response.KeyPair)
+	if err := i.keyStore.Save(keyPair.KeyId, keyPair.PrivateKey); err != nil {
+		return keyPair, fmt.Errorf("save private key of %s, %s", keyPair.KeyId, err)
+	}
+	return keyPair, nil
+}
+
+// ImportKeyPair 导入已有公钥, privateKey 不为空时一并保存到 KeyStore
+func (i *InstanceServer) ImportKeyPair(name, publicKey, privateKey string) (*cloud.KeyPair, error) {
+	req := cvm.NewImportKeyPairRequest()
+	req.KeyName = &name
+	req.PublicKey = &publicKey
+	req.ProjectId = utils.Int64Ptr(0)
+	req.TagSpecification = i.keyPairTags()
+	resp, err := i.client.ImportKeyPair(req)
+	if err != nil {
+		return nil, err
+	}
+	keyPair := &cloud.KeyPair{KeyId: resp.Response.KeyId, Name: name, PublicKey: publicKey}
+	if privateKey != "" {
+		if err := i.keyStore.Save(keyPair.KeyId, privateKey); err != nil {
+			return keyPair, fmt.Errorf("save private key of %s, %s", keyPair.KeyId, err)
+		}
+	}
+	return keyPair, nil
+}
+
+// BindKeyPairs 绑定密钥对, 实例原有的密钥将失效
+// 腾讯云要求实例处于关机状态, 运行中的实例需先关机或使用 RotateKeyPair
+func (i *InstanceServer) BindKeyPairs(instanceIds []string, keyIds ...string) error {
+	return i.bindKeyPairs(instanceIds, keyIds)
+}
+
+// UnBindKeyPairs 解绑密钥对
+func (i *InstanceServer) UnBindKeyPairs(instanceIds []string, keyIds ...string) error {
+	req := cvm.NewDisassociateInstancesKeyPairsRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	req.KeyIds = utils.StringPtrs(keyIds)
+	_, err := i.client.DisassociateInstancesKeyPairs(req)
+	return err
+}
+
+// DeleteKeyPairs 删除密钥对及 KeyStore 中保存的私钥
+func (i *InstanceServer) DeleteKeyPairs(keyIds ...string) error {
+	req := cvm.NewDeleteKeyPairsRequest()
+	req.KeyIds = utils.StringPtrs(keyIds)
+	if _, err := i.client.DeleteKeyPairs(req); err != nil {
+		return err
+	}
+	for _, id := range keyIds {
+		if err := i.keyStore.Delete(id); err != nil {
+			return fmt.Errorf("delete private key of %s, %s", id, err)
+		}
+	}
+	return nil
+}
+
+// NodeInfo 根据实例绑定的密钥对生成节点初始化所需的登录信息
+func (i *InstanceServer) NodeInfo(region, ip, keyId string) (*k8s.NodeInfo, error) {
+	privateKey, err := i.keyStore.Load(keyId)
+	if err != nil {
+		return nil, fmt.Errorf("load private key of %s, %s", keyId, err)
+	}
+	keyPair, err := i.describeKeyPair(keyId)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.NewNodeInfo(region, ip, privateKey, keyPair.PublicKey), nil
+}
+
+// RotateKeyPair 轮换实例密钥, 创建名为 name 的新密钥对并绑定到实例, 旧密钥对不再绑定任何实例时删除
+// 腾讯云只能为关机的实例绑定密钥: 运行中的实例先封锁并驱逐对应的 kubernetes 节点(设置了 NodeDrainer 时)再软关机,
+// 绑定完成后重新开机并恢复节点调度; forceStop 为 true 时软关机失败后强制关机
+// 中途失败时重新开机已关机的实例并恢复已封锁节点的调度, 返回已创建的新密钥对及错误
+func (i *InstanceServer) RotateKeyPair(oldKeyId, name string, instanceIds []string, forceStop bool) (keyPair *cloud.KeyPair, err error) {
+	keyPair, err = i.CreateKeyPair(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	running, err := i.runningInstances(ctx, instanceIds)
+	if err != nil {
+		return keyPair, err
+	}
+	var drained []*cvm.Instance
+	var stopped []string
+	defer func() {
+		if err != nil {
+			i.restoreInstances(stopped, drained)
+		}
+	}()
+	runningIds := make([]string, 0, len(running))
+	for _, instance := range running {
+		if i.drainer != nil {
+			// 驱逐失败时节点可能已封锁, 同样需要恢复
+			drained = append(drained, instance)
+			if err := i.drainer.DrainInstance(toInstanceInfo(instance)); err != nil {
+				return keyPair, fmt.Errorf("drain node of instance %s, %s", instance.InstanceId, err)
+			}
+		}
+		runningIds = append(runningIds, instance.InstanceId)
+	}
+	if len(runningIds) > 0 {
+		stopped = runningIds
+		if err := i.stopInstances(runningIds, forceStop); err != nil {
+			return keyPair, err
+		}
+		if _, err := i.waitInstances(ctx, runningIds, cvm.InstanceStateStopped); err != nil {
+			return keyPair, err
+		}
+	}
+	if err := i.bindKeyPairs(instanceIds, []string{keyPair.KeyId}); err != nil {
+		return keyPair, err
+	}
+	if err := i.waitOperation(ctx, instanceIds, "AssociateInstancesKeyPairs"); err != nil {
+		return keyPair, err
+	}
+	if len(runningIds) > 0 {
+		if err := i.StartInstance(runningIds...); err != nil {
+			return keyPair, err
+		}
+		if _, err := i.waitInstances(ctx, runningIds, cvm.InstanceStateRunning); err != nil {
+			return keyPair, err
+		}
+	}
+	stopped = nil
+	if i.drainer != nil {
+		for n, instance := range running {
+			if err := i.drainer.UncordonInstance(toInstanceInfo(instance)); err != nil {
+				drained = running[n:]
+				return keyPair, fmt.Errorf("uncordon node of instance %s, %s", instance.InstanceId, err)
+			}
+		}
+	}
+	drained = nil
+
+	if oldKeyId == "" {
+		return keyPair, nil
+	}
+	old, err := i.describeKeyPair(oldKeyId)
+	if err != nil {
+		return keyPair, err
+	}
+	if len(old.InstanceIds) > 0 {
+		zlog.Infof("key pair %s still associated with %v, skip deleting", oldKeyId, old.InstanceIds)
+		return keyPair, nil
+	}
+	return keyPair, i.DeleteKeyPairs(oldKeyId)
+}
+
+// restoreInstances 轮换密钥失败后重新开机已关机的实例, 等待运行后恢复已封锁节点的调度, 失败时只记录日志
+func (i *InstanceServer) restoreInstances(stopped []string, drained []*cvm.Instance) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	if len(stopped) > 0 {
+		// 关机中的实例需等待关机完成后才能开机
+		if err := i.startStopped(ctx, stopped); err != nil {
+			zlog.Warnf("restart instances %v after key rotation failure failed, %s", stopped, err)
+		} else if _, err := i.waitInstances(ctx, stopped, cvm.InstanceStateRunning); err != nil {
+			zlog.Warnf("restart instances %v after key rotation failure failed, %s", stopped, err)
+		}
+	}
+	for _, instance := range drained {
+		if err := i.drainer.UncordonInstance(toInstanceInfo(instance)); err != nil {
+			zlog.Warnf("uncordon node of instance %s after key rotation failure failed, %s", instance.InstanceId, err)
+		}
+	}
+}
+
+// startStopped 开机 ids 中已关机或关机中的实例, 关机中的实例等待关机完成, 运行中及开机中的实例不处理
+func (i *InstanceServer) startStopped(ctx context.Context, ids []string) error {
+	req := cvm.NewDescribeInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(ids)
+	resp, err := i.client.DescribeInstancesWithContext(ctx, req)
+	if err != nil {
+		return err
+	}
+	var stopping, start []string
+	for _, instance := range resp.Response.InstanceSet {
+		switch instance.InstanceState {
+		case cvm.InstanceStateStopping:
+			stopping = append(stopping, instance.InstanceId)
+			start = append(start, instance.InstanceId)
+		case cvm.InstanceStateStopped:
+			start = append(start, instance.InstanceId)
+		}
+	}
+	if len(stopping) > 0 {
+		if _, err := i.waitInstances(ctx, stopping, cvm.InstanceStateStopped); err != nil {
+			return err
+		}
+	}
+	if len(start) == 0 {
+		return nil
+	}
+	return i.StartInstance(start...)
+}
+
+// keyPairTags 创建密钥对时绑定的资源标签
+func (i *InstanceServer) keyPairTags() []*cvm.TagSpecification {
+	tags := toTags(i.withTags(nil))
+	if len(tags) == 0 {
+		return nil
+	}
+	return []*cvm.TagSpecification{{ResourceType: utils.StringPtr("keypair"), Tags: tags}}
+}
+
+// bindKeyPairs 绑定密钥对, 实例需处于关机状态
+func (i *InstanceServer) bindKeyPairs(instanceIds, keyIds []string) error {
+	req := cvm.NewAssociateInstancesKeyPairsRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	req.KeyIds = utils.StringPtrs(keyIds)
+	_, err := i.client.AssociateInstancesKeyPairs(req)
+	return err
+}
+
+// stopInstances 关机, force 为 true 时软关机失败后强制关机
+func (i *InstanceServer) stopInstances(instanceIds []string, force bool) error {
+	req := cvm.NewStopInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	req.StopType = utils.StringPtr(cvm.StopTypeSoft)
+	if force {
+		req.StopType = utils.StringPtr(cvm.StopTypeSoftFirst)
+	}
+	_, err := i.client.StopInstances(req)
+	return err
+}
+
+// describeKeyPair 查询单个密钥对
+func (i *InstanceServer) describeKeyPair(keyId string) (*cloud.KeyPair, error) {
+	keyPairs, err := i.DescribeKeyPairs(keyId)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyPairs) == 0 {
+		return nil, fmt.Errorf("key pair %s not found", keyId)
+	}
+	return &keyPairs[0], nil
+}
+
+// runningInstances 返回处于运行中的实例
+func (i *InstanceServer) runningInstances(ctx context.Context, ids []string) ([]*cvm.Instance, error) {
+	req := cvm.NewDescribeInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(ids)
+	resp, err := i.client.DescribeInstancesWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var running []*cvm.Instance
+	for _, instance := range resp.Response.InstanceSet {
+		if instance.InstanceState == cvm.InstanceStateRunning {
+			running = append(running, instance)
+		}
+	}
+	return running, nil
+}
+
+// waitOperation 轮询直到实例的最新操作完成
+func (i *InstanceServer) waitOperation(ctx context.Context, ids []string, operation string) error {
+	req := cvm.NewDescribeInstancesRequest()
+	req.InstanceIds = utils.StringPtrs(ids)
+	for {
+		resp, err := i.client.DescribeInstancesWithContext(ctx, req)
+		if err != nil {
+			return err
+		}
+		done := true
+		for _, instance := range resp.Response.InstanceSet {
+			if instance.LatestOperation != operation || instance.LatestOperationState == operationStateOperating {
+				done = false
+				continue
+			}
+			if instance.LatestOperationState == operationStateFailed {
+				return fmt.Errorf("instance %s %s failed", instance.InstanceId, operation)
+			}
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait instances %v %s, %s", ids, operation, ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package tencent
+
+import (
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/emulator"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/utils"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingDrainer 记录封锁及恢复调度的实例
+type recordingDrainer struct {
+	mu         sync.Mutex
+	drained    []string
+	uncordoned []string
+}
+
+func (d *recordingDrainer) DrainInstance(instance cloud.InstanceInfo) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.drained = append(d.drained, instance.InstanceId)
+	return nil
+}
+
+func (d *recordingDrainer) UncordonInstance(instance cloud.InstanceInfo) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.uncordoned = append(d.uncordoned, instance.InstanceId)
+	return nil
+}
+
+// newRotateServer 运行中的实例 ins-1 绑定旧密钥对 skey-old, 开关机及绑定密钥立即完成
+func newRotateServer(t *testing.T) (*emulator.Server, *InstanceServer, *recordingDrainer, func() *cvm.Instance) {
+	t.Helper()
+	s, server := newTestServer(t)
+	server.SetKeyStore(cloud.NewFileKeyStore(t.TempDir()))
+	drainer := &recordingDrainer{}
+	server.SetDrainer(drainer)
+
+	var mu sync.Mutex
+	instance := newInstance("ins-1", nil)
+	instance.LoginSettings = &cvm.LoginSettings{KeyIds: utils.StringPtrs([]string{"skey-old"})}
+	current := func() *cvm.Instance {
+		mu.Lock()
+		defer mu.Unlock()
+		copied := *instance
+		return &copied
+	}
+	setState := func(state, operation string) emulator.Handler {
+		return func(req *emulator.Request) (interface{}, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if state != "" {
+				instance.InstanceState = state
+			}
+			instance.LatestOperation, instance.LatestOperationState = operation, "SUCCESS"
+			return map[string]interface{}{}, nil
+		}
+	}
+	s.Handle("DescribeInstances", instancesHandler(func() []*cvm.Instance { return []*cvm.Instance{current()} }))
+	s.Handle("StopInstances", setState(cvm.InstanceStateStopped, "StopInstances"))
+	s.Handle("StartInstances", setState(cvm.InstanceStateRunning, "StartInstances"))
+	s.Handle("AssociateInstancesKeyPairs", setState("", "AssociateInstancesKeyPairs"))
+	s.Handle("CreateKeyPair", func(req *emulator.Request) (interface{}, error) {
+		return map[string]interface{}{"KeyPair": cvm.KeyPair{KeyId: "skey-new", KeyName: "k8s_aim_new", PrivateKey: "private"}}, nil
+	})
+	s.Handle("DescribeKeyPairs", func(req *emulator.Request) (interface{}, error) {
+		return map[string]interface{}{"TotalCount": 1, "KeyPairSet": []cvm.KeyPair{{KeyId: "skey-old"}}}, nil
+	})
+	return s, server, drainer, current
+}
+
+// actions 模拟服务收到的写操作
+func actions(s *emulator.Server) []string {
+	var names []string
+	for _, req := range s.Requests() {
+		switch req.Action {
+		case "DescribeInstances", "DescribeKeyPairs":
+		default:
+			names = append(names, req.Action)
+		}
+	}
+	return names
+}
+
+func TestRotateKeyPair(t *testing.T) {
+	s, server, drainer, current := newRotateServer(t)
+	keyPair, err := server.RotateKeyPair("skey-old", "k8s_aim_new", []string{"ins-1"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyPair.KeyId != "skey-new" {
+		t.Fatalf("key pair = %+v, want skey-new", keyPair)
+	}
+	want := []string{"CreateKeyPair", "StopInstances", "AssociateInstancesKeyPairs", "StartInstances", "DeleteKeyPairs"}
+	if got := actions(s); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	if state := current().InstanceState; state != cvm.InstanceStateRunning {
+		t.Fatalf("instance state = %s, want RUNNING", state)
+	}
+	if len(drainer.drained) != 1 || len(drainer.uncordoned) != 1 {
+		t.Fatalf("drained %v, uncordoned %v, want ins-1 both", drainer.drained, drainer.uncordoned)
+	}
+}
+
+func TestRotateKeyPairRestoresInstancesOnFailure(t *testing.T) {
+	s, server, drainer, current := newRotateServer(t)
+	s.Fail("AssociateInstancesKeyPairs", &emulator.Error{Code: "InvalidInstance.NotSupported", Message: "not supported"}, 1)
+	if _, err := server.RotateKeyPair("skey-old", "k8s_aim_new", []string{"ins-1"}, false); err == nil {
+		t.Fatal("expected bind error")
+	}
+	want := []string{"CreateKeyPair", "StopInstances", "AssociateInstancesKeyPairs", "StartInstances"}
+	if got := actions(s); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	if state := current().InstanceState; state != cvm.InstanceStateRunning {
+		t.Fatalf("instance state = %s, want RUNNING after restore", state)
+	}
+	if len(drainer.uncordoned) != 1 || drainer.uncordoned[0] != "ins-1" {
+		t.Fatalf("uncordoned %v, want [ins-1]", drainer.uncordoned)
+	}
+}
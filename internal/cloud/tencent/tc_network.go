@@ -0,0 +1,197 @@
+package tencent
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/utils"
+	"strconv"
+	"time"
+)
+
+// DescribeVpcs 查询私有网络
+func (i *InstanceServer) DescribeVpcs() ([]cloud.Vpc, error) {
+	var vpcs []cloud.Vpc
+	err := i.paginate(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		req := cvm.NewDescribeVpcsRequest()
+		req.Offset = utils.StringPtr(strconv.FormatInt(offset, 10))
+		req.Limit = utils.StringPtr(strconv.FormatInt(limit, 10))
+		resp, err := i.client.DescribeVpcsWithContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, v := range resp.Response.VpcSet {
+			vpcs = append(vpcs, cloud.Vpc{VpcId: v.VpcId, Name: v.VpcName, CidrBlock: v.CidrBlock, IsDefault: v.IsDefault})
+		}
+		return len(resp.Response.VpcSet), int64(resp.Response.TotalCount), nil
+	})
+	return vpcs, err
+}
+
+// DescribeSubnets 查询子网
+func (i *InstanceServer) DescribeSubnets(vpcId, zone string) ([]cloud.Subnet, error) {
+	var filters []*cvm.Filter
+	if vpcId != "" {
+		filters = append(filters, cvm.NewFilter("vpc-id", vpcId))
+	}
+	if zone != "" {
+		filters = append(filters, cvm.NewFilter("zone", zone))
+	}
+	var subnets []cloud.Subnet
+	err := i.paginate(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		req := cvm.NewDescribeSubnetsRequest()
+		req.Filters = filters
+		req.Offset = utils.StringPtr(strconv.FormatInt(offset, 10))
+		req.Limit = utils.StringPtr(strconv.FormatInt(limit, 10))
+		resp, err := i.client.DescribeSubnetsWithContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, s := range resp.Response.SubnetSet {
+			subnets = append(subnets, cloud.Subnet{
+				SubnetId:         s.SubnetId,
+				VpcId:            s.VpcId,
+				Name:             s.SubnetName,
+				CidrBlock:        s.CidrBlock,
+				Zone:             s.Zone,
+				AvailableIpCount: int64(s.AvailableIpAddressCount),
+				IsDefault:        s.IsDefault,
+			})
+		}
+		return len(resp.Response.SubnetSet), int64(resp.Response.TotalCount), nil
+	})
+	return subnets, err
+}
+
+// DescribeAddresses 查询弹性公网IP, addressIds 为空时只查询带有资源标签的弹性公网IP
+func (i *InstanceServer) DescribeAddresses(addressIds ...string) ([]cloud.Address, error) {
+	var addresses []cloud.Address
+	err := i.paginate(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		req := cvm.NewDescribeAddressesRequest()
+		if len(addressIds) > 0 {
+			req.AddressIds = utils.StringPtrs(addressIds)
+		} else {
+			req.Filters = i.tagFilters()
+		}
+		req.Offset, req.Limit = &offset, &limit
+		resp, err := i.client.DescribeAddressesWithContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, a := range resp.Response.AddressSet {
+			addresses = append(addresses, cloud.Address{
+				AddressId:  a.AddressId,
+				Ip:         a.AddressIp,
+				Status:     a.AddressStatus,
+				InstanceId: a.InstanceId,
+				Tags:       fromTags(a.TagSet),
+			})
+		}
+		return len(resp.Response.AddressSet), resp.Response.TotalCount, nil
+	})
+	return addresses, err
+}
+
+// AllocateAddress 申请弹性公网IP, 等待创建完成后返回
+func (i *InstanceServer) AllocateAddress(bandwidth int64) (*cloud.Address, error) {
+	req := cvm.NewAllocateAddressesRequest()
+	req.AddressCount = utils.Int64Ptr(1)
+	req.Tags = toTags(i.withTags(nil))
+	if bandwidth > 0 {
+		req.InternetMaxBandwidthOut = &bandwidth
+	}
+	resp, err := i.client.AllocateAddresses(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Response.AddressSet) == 0 {
+		return nil, fmt.Errorf("allocate address returned no address, request id: %s", resp.Response.RequestId)
+	}
+	addressId := resp.Response.AddressSet[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+	for {
+		addresses, err := i.DescribeAddresses(addressId)
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses) > 0 && addresses[0].Status != cvm.AddressStatusCreating {
+			return &addresses[0], nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait address %s to be created, %s", addressId, ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// AssociateAddress 绑定弹性公网IP到实例
+func (i *InstanceServer) AssociateAddress(addressId, instanceId string) error {
+	req := cvm.NewAssociateAddressRequest()
+	req.AddressId = &addressId
+	req.InstanceId = &instanceId
+	_, err := i.client.AssociateAddress(req)
+	return err
+}
+
+// DisassociateAddress 解绑弹性公网IP
+func (i *InstanceServer) DisassociateAddress(addressId string) error {
+	req := cvm.NewDisassociateAddressRequest()
+	req.AddressId = &addressId
+	_, err := i.client.DisassociateAddress(req)
+	return err
+}
+
+// ReleaseAddress 释放弹性公网IP
+func (i *InstanceServer) ReleaseAddress(addressId string) error {
+	req := cvm.NewReleaseAddressesRequest()
+	req.AddressIds = utils.StringPtrs([]string{addressId})
+	_, err := i.client.ReleaseAddresses(req)
+	return err
+}
+
+// DescribeNetworkInterfaces 查询实例绑定的弹性网卡
+func (i *InstanceServer) DescribeNetworkInterfaces(instanceId string) ([]cloud.NetworkInterface, error) {
+	req := cvm.NewDescribeNetworkInterfacesRequest()
+	req.Filters = []*cvm.Filter{cvm.NewFilter("attachment.instance-id", instanceId)}
+	req.Limit = utils.Uint64Ptr(common.DefaultPageLimit)
+	resp, err := i.client.DescribeNetworkInterfaces(req)
+	if err != nil {
+		return nil, err
+	}
+	var enis []cloud.NetworkInterface
+	for _, n := range resp.Response.NetworkInterfaceSet {
+		eni := cloud.NetworkInterface{
+			NetworkInterfaceId: n.NetworkInterfaceId,
+			VpcId:              n.VpcId,
+			SubnetId:           n.SubnetId,
+			MacAddress:         n.MacAddress,
+			Primary:            n.Primary,
+			State:              n.State,
+		}
+		if n.Attachment != nil {
+			eni.InstanceId = n.Attachment.InstanceId
+		}
+		for _, ip := range n.PrivateIpAddressSet {
+			eni.PrivateIps = append(eni.PrivateIps, ip.PrivateIpAddress)
+			if ip.PublicIpAddress != "" {
+				eni.PublicIps = append(eni.PublicIps, ip.PublicIpAddress)
+			}
+		}
+		enis = append(enis, eni)
+	}
+	return enis, nil
+}
+
+// PickSubnet 在可用区内选择可用IP数不少于 need 的子网
+func (i *InstanceServer) PickSubnet(vpcId, zone string, need int64) (*cloud.Subnet, error) {
+	subnets, err := i.DescribeSubnets(vpcId, zone)
+	if err != nil {
+		return nil, err
+	}
+	return cloud.PickSubnet(subnets, zone, need)
+}
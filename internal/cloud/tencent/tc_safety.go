@@ -0,0 +1,177 @@
+package tencent
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+	"github.com/eadydb/k8s-aim/pkg/utils"
+	"strconv"
+)
+
+var _ cloud.SecurityGroup = (*InstanceServer)(nil)
+
+// CreateSecurityGroup 创建安全组并添加规则, 返回安全组ID
+func (i *InstanceServer) CreateSecurityGroup(name, description string, rules []cloud.SecurityRule) (string, error) {
+	req := cvm.NewCreateSecurityGroupRequest()
+	req.GroupName = &name
+	req.GroupDescription = &description
+	req.Tags = toTags(i.withTags(nil))
+	resp, err := i.client.CreateSecurityGroup(req)
+	if err != nil {
+		return "", err
+	}
+	if resp.Response.SecurityGroup == nil {
+		return "", fmt.Errorf("create security group returned no group, request id: %s", resp.Response.RequestId)
+	}
+	groupId := resp.Response.SecurityGroup.SecurityGroupId
+	if len(rules) > 0 {
+		if err := i.AddRules(groupId, rules); err != nil {
+			return groupId, err
+		}
+	}
+	return groupId, nil
+}
+
+// DescribeSecurityGroups 查询安全组, groupIds 为空时查询全部带有资源标签的安全组
+func (i *InstanceServer) DescribeSecurityGroups(groupIds ...string) ([]cloud.SecurityGroupInfo, error) {
+	groups, err := i.describeSecurityGroups(groupIds, nil)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]cloud.SecurityGroupInfo, 0, len(groups))
+	for _, group := range groups {
+		infos = append(infos, toSecurityGroupInfo(group))
+	}
+	return infos, nil
+}
+
+// DescribeSecurityGroupByName 按名称查询带有资源标签的安全组, 不存在时返回 nil
+func (i *InstanceServer) DescribeSecurityGroupByName(name string) (*cloud.SecurityGroupInfo, error) {
+	groups, err := i.describeSecurityGroups(nil, []*cvm.Filter{cvm.NewFilter("security-group-name", name)})
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if group.SecurityGroupName == name {
+			info := toSecurityGroupInfo(group)
+			return &info, nil
+		}
+	}
+	return nil, nil
+}
+
+// DeleteSecurityGroup 删除安全组
+func (i *InstanceServer) DeleteSecurityGroup(groupId string) error {
+	req := cvm.NewDeleteSecurityGroupRequest()
+	req.SecurityGroupId = &groupId
+	_, err := i.client.DeleteSecurityGroup(req)
+	return err
+}
+
+// DescribeRules 查询安全组规则
+func (i *InstanceServer) DescribeRules(groupId string) ([]cloud.SecurityRule, error) {
+	req := cvm.NewDescribeSecurityGroupPoliciesRequest()
+	req.SecurityGroupId = &groupId
+	resp, err := i.client.DescribeSecurityGroupPolicies(req)
+	if err != nil {
+		return nil, err
+	}
+	return toRules(resp.Response.SecurityGroupPolicySet), nil
+}
+
+// AddRules 添加安全组规则, 接口单次只能添加一个方向, 入站与出站分开提交
+func (i *InstanceServer) AddRules(groupId string, rules []cloud.SecurityRule) error {
+	for _, set := range splitPolicies(toPolicySet(rules)) {
+		req := cvm.NewCreateSecurityGroupPoliciesRequest()
+		req.SecurityGroupId = &groupId
+		req.SecurityGroupPolicySet = set
+		if _, err := i.client.CreateSecurityGroupPolicies(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveRules 删除安全组规则, 入站与出站分开提交
+func (i *InstanceServer) RemoveRules(groupId string, rules []cloud.SecurityRule) error {
+	for _, set := range splitPolicies(toPolicySet(rules)) {
+		req := cvm.NewDeleteSecurityGroupPoliciesRequest()
+		req.SecurityGroupId = &groupId
+		req.SecurityGroupPolicySet = set
+		if _, err := i.client.DeleteSecurityGroupPolicies(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureWorkerSecurityGroup 获取或创建 kubernetes worker 节点安全组, 返回安全组ID
+// cidr 为允许访问节点的网段, 一般为集群所在私有网络网段
+func (i *InstanceServer) EnsureWorkerSecurityGroup(name, cidr string) (string, error) {
+	group, err := i.DescribeSecurityGroupByName(name)
+	if err != nil {
+		return "", err
+	}
+	if group != nil {
+		return group.SecurityGroupId, nil
+	}
+	return i.CreateSecurityGroup(name, "kubernetes worker nodes", cloud.KubernetesWorkerRules(cidr))
+}
+
+// Bind 实例绑定安全组
+func (i *InstanceServer) Bind(instanceIds []string, groupIds ...string) error {
+	req := cvm.NewAssociateSecurityGroupsRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	req.SecurityGroupIds = utils.StringPtrs(groupIds)
+	_, err := i.client.AssociateSecurityGroups(req)
+	return err
+}
+
+// UnBind 实例解绑安全组
+func (i *InstanceServer) UnBind(instanceIds []string, groupIds ...string) error {
+	req := cvm.NewDisassociateSecurityGroupsRequest()
+	req.InstanceIds = utils.StringPtrs(instanceIds)
+	req.SecurityGroupIds = utils.StringPtrs(groupIds)
+	_, err := i.client.DisassociateSecurityGroups(req)
+	return err
+}
+
+// describeSecurityGroups 分页查询安全组, 未指定安全组ID时追加资源标签过滤条件
+func (i *InstanceServer) describeSecurityGroups(groupIds []string, filters []*cvm.Filter) ([]*cvm.SecurityGroup, error) {
+	if len(groupIds) == 0 {
+		filters = append(filters, i.tagFilters()...)
+	}
+	var groups []*cvm.SecurityGroup
+	err := i.paginate(func(ctx context.Context, offset, limit int64) (int, int64, error) {
+		req := cvm.NewDescribeSecurityGroupsRequest()
+		if len(groupIds) > 0 {
+			req.SecurityGroupIds = utils.StringPtrs(groupIds)
+		}
+		req.Filters = filters
+		req.Offset = utils.StringPtr(strconv.FormatInt(offset, 10))
+		req.Limit = utils.StringPtr(strconv.FormatInt(limit, 10))
+		resp, err := i.client.DescribeSecurityGroupsWithContext(ctx, req)
+		if err != nil {
+			return 0, 0, err
+		}
+		groups = append(groups, resp.Response.SecurityGroupSet...)
+		return len(resp.Response.SecurityGroupSet), int64(resp.Response.TotalCount), nil
+	})
+	return groups, err
+}
+
+// splitPolicies 按方向拆分规则集合
+func splitPolicies(policies *cvm.SecurityGroupPolicySet) []*cvm.SecurityGroupPolicySet {
+	if policies == nil {
+		return nil
+	}
+	var sets []*cvm.SecurityGroupPolicySet
+	if len(policies.Ingress) > 0 {
+		sets = append(sets, &cvm.SecurityGroupPolicySet{Ingress: policies.Ingress})
+	}
+	if len(policies.Egress) > 0 {
+		sets = append(sets, &cvm.SecurityGroupPolicySet{Egress: policies.Egress})
+	}
+	return sets
+}
@@ -0,0 +1,69 @@
+package tencent
+
+import (
+	"context"
+	"fmt"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/common"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+// SetMetadataClient 设置实例元数据客户端, 用于查询竞价实例回收通知
+func (i *InstanceServer) SetMetadataClient(client *common.MetadataClient) {
+	i.metadata = client
+}
+
+// DescribeInterruption 通过实例元数据查询竞价实例的回收通知
+// 元数据服务只能由实例自身访问, instanceId 不是当前实例时返回 cloud.ErrNotSupported, 需在竞价节点上运行监控
+func (i *InstanceServer) DescribeInterruption(instanceId string) (*cloud.Interruption, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), adoptTimeout)
+	defer cancel()
+	localId, err := i.localInstanceId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if localId != instanceId {
+		return nil, fmt.Errorf("%w: instance %s is not the local instance %s", cloud.ErrNotSupported, instanceId, localId)
+	}
+	t, ok, err := i.metadata.TerminationTime(ctx)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &cloud.Interruption{InstanceId: instanceId, Time: t}, nil
+}
+
+// withOnDemandPrice 腾讯云竞价实例必须指定出价, 未指定时使用机型目录中的按量计费单价, 返回新的竞价参数
+func (i *InstanceServer) withOnDemandPrice(spec *cloud.InstanceSpec) (*cloud.SpotOptions, error) {
+	spot := *spec.Spot
+	if spot.MaxPrice > 0 {
+		return &spot, nil
+	}
+	var zones []string
+	if spec.Zone != "" {
+		zones = []string{spec.Zone}
+	}
+	offerings, err := i.DescribeInstanceTypes(zones...)
+	if err != nil {
+		return nil, fmt.Errorf("query on-demand price of %s for spot max price, %w", spec.InstanceType, err)
+	}
+	offering, ok := cloud.FindInstanceType(offerings, spec.Zone, spec.InstanceType)
+	if !ok || offering.Price <= 0 {
+		return nil, fmt.Errorf("spot max price is required, on-demand price of %s in zone %s is unknown", spec.InstanceType, spec.Zone)
+	}
+	spot.MaxPrice = offering.Price
+	return &spot, nil
+}
+
+// localInstanceId 当前实例ID, 查询成功后缓存
+func (i *InstanceServer) localInstanceId(ctx context.Context) (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.localId != "" {
+		return i.localId, nil
+	}
+	id, err := i.metadata.InstanceId(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: query local instance id from metadata failed, %s", cloud.ErrNotSupported, err)
+	}
+	i.localId = id
+	return id, nil
+}
@@ -0,0 +1,44 @@
+package tencent
+
+import (
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+var _ cloud.TagAware = (*InstanceServer)(nil)
+
+// SetTags 设置资源标签, 创建的实例(含云硬盘)、弹性公网IP、安全组及密钥对均带有这些标签,
+// 未指定资源ID的查询只返回满足 Selector 的资源
+func (i *InstanceServer) SetTags(tags cloud.ResourceTags) {
+	i.tags = &tags
+}
+
+// withTags 合并资源标签, 同名时以资源标签为准, 返回新的标签
+func (i *InstanceServer) withTags(tags map[string]string) map[string]string {
+	if i.tags == nil {
+		return cloud.MergeTags(tags)
+	}
+	return cloud.MergeTags(tags, i.tags.Tags())
+}
+
+// selector 查询资源时的过滤标签, 未设置资源标签时为空
+func (i *InstanceServer) selector() map[string]string {
+	if i.tags == nil {
+		return nil
+	}
+	return i.tags.Selector()
+}
+
+// tagFilters 按资源标签过滤的查询条件, 未设置资源标签时为空
+func (i *InstanceServer) tagFilters() []*cvm.Filter {
+	return toTagFilters(i.selector())
+}
+
+// toTagFilters 标签转换为查询条件, 按键排序保证请求稳定
+func toTagFilters(tags map[string]string) []*cvm.Filter {
+	var filters []*cvm.Filter
+	for _, key := range sortedKeys(tags) {
+		filters = append(filters, cvm.NewFilter("tag:"+key, tags[key]))
+	}
+	return filters
+}
@@ -0,0 +1,42 @@
+package tencent
+
+import (
+	"context"
+	"github.com/eadydb/k8s-aim/internal/cloud/tencent/cvm"
+	"github.com/eadydb/k8s-aim/pkg/cloud"
+)
+
+// 地域、可用区可用状态
+const (
+	stateAvailable = "AVAILABLE"
+)
+
+var _ cloud.Zones = (*InstanceServer)(nil)
+
+// DescribeZones 查询当前地域的可用区, 地域不可用时所有可用区均视为不可用
+func (i *InstanceServer) DescribeZones() ([]cloud.Zone, error) {
+	ctx := context.Background()
+	regions, err := i.client.DescribeRegionsWithContext(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	regionAvailable := true
+	for _, region := range regions.Response.RegionSet {
+		if region.Region == i.client.GetRegion() {
+			regionAvailable = region.RegionState == stateAvailable
+		}
+	}
+	resp, err := i.client.DescribeZonesWithContext(ctx, cvm.NewZonesRequest())
+	if err != nil {
+		return nil, err
+	}
+	zones := make([]cloud.Zone, 0, len(resp.Response.ZoneSet))
+	for _, zone := range resp.Response.ZoneSet {
+		zones = append(zones, cloud.Zone{
+			Zone:      zone.Zone,
+			Name:      zone.ZoneName,
+			Available: regionAvailable && zone.ZoneState == stateAvailable,
+		})
+	}
+	return zones, nil
+}
@@ -0,0 +1,146 @@
+package cloud
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SelectImage 从镜像列表中选择名称匹配 nameRegex 且满足操作系统约束 os 的镜像
+// 优先选择版本最高的, 版本相同时选择创建时间最新的, 调用方负责过滤不可用的镜像
+func SelectImage(images []ImageInfo, nameRegex, os string) (*ImageInfo, error) {
+	var nameExp *regexp.Regexp
+	if nameRegex != "" {
+		exp, err := regexp.Compile(nameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image name regex %s, %s", nameRegex, err)
+		}
+		nameExp = exp
+	}
+	constraint, err := ParseOSConstraint(os)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []ImageInfo
+	for _, image := range images {
+		if nameExp != nil && !nameExp.MatchString(image.Name) {
+			continue
+		}
+		if !constraint.Match(&image) {
+			continue
+		}
+		candidates = append(candidates, image)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no image matches name %q and os %q", nameRegex, os)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if c := compareVersion(imageVersion(&candidates[i]), imageVersion(&candidates[j])); c != 0 {
+			return c > 0
+		}
+		return candidates[i].CreatedTime.After(candidates[j].CreatedTime)
+	})
+	return &candidates[0], nil
+}
+
+// OSConstraint 操作系统约束
+type OSConstraint struct {
+	Family   string // 操作系统, 如 ubuntu、centos, 为空时不限
+	Operator string // 比较符 =、>=、>、<=、<
+	Version  string // 版本, 可使用 * 通配, 如 7.*
+}
+
+var (
+	osConstraintExp = regexp.MustCompile(`^([A-Za-z][A-Za-z ]*?)\s*(>=|<=|==|=|>|<)?\s*([0-9][0-9.]*(\.\*)?|\*)?$`)
+	osVersionExp    = regexp.MustCompile(`\d+(\.\d+)*`)
+)
+
+// ParseOSConstraint 解析操作系统约束, 如 ubuntu>=20.04、centos 7.*、ubuntu
+func ParseOSConstraint(s string) (*OSConstraint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return &OSConstraint{}, nil
+	}
+	m := osConstraintExp.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid os constraint %q", s)
+	}
+	c := &OSConstraint{Family: strings.ToLower(strings.TrimSpace(m[1])), Operator: m[2], Version: m[3]}
+	if c.Operator == "" || c.Operator == "==" {
+		c.Operator = "="
+	}
+	if strings.Contains(c.Version, "*") && c.Operator != "=" {
+		return nil, fmt.Errorf("invalid os constraint %q, wildcard only supports =", s)
+	}
+	return c, nil
+}
+
+// Match 镜像是否满足约束
+func (c *OSConstraint) Match(image *ImageInfo) bool {
+	if c.Family != "" &&
+		!strings.EqualFold(image.Platform, c.Family) &&
+		!strings.HasPrefix(strings.ToLower(image.OsName), c.Family) {
+		return false
+	}
+	if c.Version == "" || c.Version == "*" {
+		return true
+	}
+	version := imageVersion(image)
+	if version == "" {
+		return false
+	}
+	if strings.HasSuffix(c.Version, ".*") {
+		prefix := strings.TrimSuffix(c.Version, "*")
+		return version+"." == prefix || strings.HasPrefix(version, prefix)
+	}
+	cmp := compareVersion(version, c.Version)
+	switch c.Operator {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// imageVersion 从操作系统名称中提取版本号, 如 Ubuntu Server 20.04 LTS 64位 返回 20.04
+func imageVersion(image *ImageInfo) string {
+	name := strings.TrimSpace(strings.TrimPrefix(image.OsName, image.Platform))
+	for _, v := range osVersionExp.FindAllString(name, -1) {
+		// 跳过 64位、32位 等架构描述
+		if v == "64" || v == "32" {
+			continue
+		}
+		return v
+	}
+	return ""
+}
+
+// compareVersion 按数字逐段比较版本号
+func compareVersion(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
@@ -1,25 +1,144 @@
 package cloud
 
+import "time"
+
+// InstanceState 实例状态
+type InstanceState string
+
+const (
+	InstanceStatePending     InstanceState = "Pending"     // 创建中
+	InstanceStateRunning     InstanceState = "Running"     // 运行中
+	InstanceStateStarting    InstanceState = "Starting"    // 开机中
+	InstanceStateStopping    InstanceState = "Stopping"    // 关机中
+	InstanceStateStopped     InstanceState = "Stopped"     // 关机
+	InstanceStateRebooting   InstanceState = "Rebooting"   // 重启中
+	InstanceStateTerminating InstanceState = "Terminating" // 销毁中
+	InstanceStateTerminated  InstanceState = "Terminated"  // 已销毁或待销毁
+	InstanceStateFailed      InstanceState = "Failed"      // 创建失败
+	InstanceStateUnknown     InstanceState = "Unknown"     // 未知状态
+)
+
+// Disk 磁盘
+type Disk struct {
+	Type   string // 磁盘类型, 取值与云厂商相关, 为空时使用默认类型
+	SizeGB int64  // 磁盘大小, 单位GB
+}
+
+// InstanceSpec 创建实例参数
+type InstanceSpec struct {
+	Name              string            // 实例名称
+	HostName          string            // 主机名
+	Zone              string            // 可用区
+	InstanceType      string            // 实例机型
+	ImageId           string            // 镜像ID
+	SystemDisk        *Disk             // 系统盘, 为空时使用默认配置
+	DataDisks         []Disk            // 数据盘
+	VpcId             string            // 私有网络ID
+	SubnetId          string            // 子网ID
+	SecurityGroupIds  []string          // 安全组ID
+	KeyPairIds        []string          // 密钥对ID
+	InternetBandwidth int64             // 公网出带宽上限(Mbps), 为0时不分配公网IP
+	Tags              map[string]string // 标签
+	UserData          string            // 自定义数据, 原始内容, 由实现负责编码
+	Count             int64             // 创建数量, 为0时创建1台
+	ClientToken       string            // 保证请求幂等性的字符串
+	Spot              *SpotOptions      // 竞价实例参数, 为空时按量计费
+}
+
+// InstanceInfo 实例信息
+type InstanceInfo struct {
+	InstanceId       string            // 实例ID
+	Name             string            // 实例名称
+	InstanceType     string            // 实例机型
+	Zone             string            // 可用区
+	ImageId          string            // 镜像ID
+	State            InstanceState     // 状态
+	PrivateIps       []string          // 内网IP
+	PublicIps        []string          // 公网IP
+	SecurityGroupIds []string          // 安全组ID
+	KeyPairIds       []string          // 密钥对ID
+	Tags             map[string]string // 标签
+	Spot             bool              // 是否竞价实例
+	CreatedTime      time.Time         // 创建时间
+}
+
+// InstanceFilter 查询实例条件, 字段为空时不过滤
+type InstanceFilter struct {
+	InstanceIds []string          // 实例ID
+	Name        string            // 实例名称
+	Zone        string            // 可用区
+	Tags        map[string]string // 标签, 同时匹配所有标签
+}
+
 // Instance 云厂商实例
 type Instance interface {
 	NetWork // 网络
 
-	// CreateInstance 创建实例
-	CreateInstance(obj interface{}) (interface{}, error)
+	// CreateInstance 创建实例, 等待实例运行后返回
+	CreateInstance(spec *InstanceSpec) ([]InstanceInfo, error)
+
+	// DescribeInstances 查询实例, filter 为 nil 时查询全部
+	DescribeInstances(filter *InstanceFilter) ([]InstanceInfo, error)
 
 	// StartInstance 启动实例
-	StartInstance(obj interface{}) error
+	StartInstance(instanceIds ...string) error
 
 	// StopInstance 停止实例
-	StopInstance(obj interface{}) error
+	StopInstance(instanceIds ...string) error
 
 	// RestartInstance 重启实例
-	RestartInstance(obj interface{}) error
+	RestartInstance(instanceIds ...string) error
+
+	// TerminateInstance 退还实例
+	TerminateInstance(instanceIds ...string) error
+
+	// DescribeInterruption 查询竞价实例的回收通知, 未收到通知时返回 nil, 无法查询该实例时返回 ErrNotSupported
+	DescribeInterruption(instanceId string) (*Interruption, error)
+}
+
+// ImageType 镜像类型
+type ImageType string
+
+const (
+	ImageTypePublic  ImageType = "Public"  // 公共镜像
+	ImageTypePrivate ImageType = "Private" // 自定义镜像
+	ImageTypeShared  ImageType = "Shared"  // 共享镜像
+)
+
+// ImageQuery 镜像查询条件, 字段为空时不限
+type ImageQuery struct {
+	ImageType    ImageType // 镜像类型
+	OS           string    // 操作系统约束, 如 ubuntu>=20.04、centos 7.*
+	NameRegex    string    // 镜像名称正则
+	InstanceType string    // 实例机型, 只选择该机型支持的镜像
+}
+
+// ImageInfo 镜像信息
+type ImageInfo struct {
+	ImageId      string    // 镜像ID
+	Name         string    // 镜像名称
+	ImageType    ImageType // 镜像类型
+	OsName       string    // 操作系统名称
+	Platform     string    // 操作系统平台
+	Architecture string    // 操作系统架构
+	SizeGB       int64     // 镜像大小, 单位GB
+	CreatedTime  time.Time // 创建时间
 }
 
 // Image 云厂商镜像
 type Image interface {
 
-	// GetImage 加载镜像
-	GetImage(obj interface{}) (interface{}, error)
+	// GetImage 加载镜像, 返回符合条件且版本最高、创建时间最新的镜像
+	GetImage(query *ImageQuery) (*ImageInfo, error)
+
+	// CreateImage 使用实例制作自定义镜像, 返回镜像ID
+	CreateImage(instanceId, name, description string) (string, error)
+}
+
+// Provider 云厂商, 节点管理只依赖该接口
+type Provider interface {
+	Instance
+	Image
+	SecurityGroup
+	KeyParis
 }
@@ -0,0 +1,112 @@
+package cloud
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrInsufficientStock 可用区内机型售罄, 云厂商实现创建实例时应包装该错误, 调用方据此切换机型或可用区
+var ErrInsufficientStock = errors.New("cloud: insufficient stock")
+
+// InstanceTypeOffering 可用区内的机型及库存
+type InstanceTypeOffering struct {
+	Zone         string  // 可用区
+	InstanceType string  // 实例机型
+	Family       string  // 实例机型系列
+	CPU          int64   // CPU核数
+	MemoryGB     int64   // 内存容量, 单位GB
+	GPU          int64   // GPU数量
+	InStock      bool    // 是否有库存
+	Price        float64 // 按量计费单价, 未知时为0
+}
+
+// InstanceTypes 机型目录, 云厂商可选实现
+type InstanceTypes interface {
+
+	// DescribeInstanceTypes 查询可用区内的机型及库存, zones 为空时查询全部可用区
+	DescribeInstanceTypes(zones ...string) ([]InstanceTypeOffering, error)
+}
+
+// InstanceTypeRequirement 机型要求, 数值为0时不限
+type InstanceTypeRequirement struct {
+	MinCPU      int64    // 最少CPU核数
+	MaxCPU      int64    // 最多CPU核数
+	MinMemoryGB int64    // 最少内存, 单位GB
+	MaxMemoryGB int64    // 最多内存, 单位GB
+	AllowGPU    bool     // 是否允许GPU机型, 默认排除
+	Families    []string // 允许的机型系列, 按优先级排列, 为空时不限
+	Zones       []string // 允许的可用区, 按优先级排列, 为空时不限
+}
+
+// Match 机型是否满足要求, 不考虑库存
+func (r *InstanceTypeRequirement) Match(o *InstanceTypeOffering) bool {
+	switch {
+	case r.MinCPU > 0 && o.CPU < r.MinCPU, r.MaxCPU > 0 && o.CPU > r.MaxCPU:
+		return false
+	case r.MinMemoryGB > 0 && o.MemoryGB < r.MinMemoryGB, r.MaxMemoryGB > 0 && o.MemoryGB > r.MaxMemoryGB:
+		return false
+	case !r.AllowGPU && o.GPU > 0:
+		return false
+	case len(r.Families) > 0 && indexOf(r.Families, o.Family) < 0:
+		return false
+	case len(r.Zones) > 0 && indexOf(r.Zones, o.Zone) < 0:
+		return false
+	}
+	return true
+}
+
+// SelectInstanceTypes 筛选满足要求的机型并排序, 包含售罄的机型以便调用方展示
+// 有库存的优先, 其次按可用区优先级、配置最接近要求(CPU、内存从小到大)、机型系列优先级、价格从低到高排序
+func SelectInstanceTypes(offerings []InstanceTypeOffering, req InstanceTypeRequirement) []InstanceTypeOffering {
+	var candidates []InstanceTypeOffering
+	for _, o := range offerings {
+		if req.Match(&o) {
+			candidates = append(candidates, o)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := &candidates[i], &candidates[j]
+		if a.InStock != b.InStock {
+			return a.InStock
+		}
+		if x, y := indexOf(req.Zones, a.Zone), indexOf(req.Zones, b.Zone); x != y {
+			return x < y
+		}
+		if a.CPU != b.CPU {
+			return a.CPU < b.CPU
+		}
+		if a.MemoryGB != b.MemoryGB {
+			return a.MemoryGB < b.MemoryGB
+		}
+		if x, y := indexOf(req.Families, a.Family), indexOf(req.Families, b.Family); x != y {
+			return x < y
+		}
+		if a.Price != b.Price {
+			return a.Price < b.Price
+		}
+		if a.Zone != b.Zone {
+			return a.Zone < b.Zone
+		}
+		return a.InstanceType < b.InstanceType
+	})
+	return candidates
+}
+
+// FindInstanceType 查找可用区内的机型, zone 为空时匹配任意可用区
+func FindInstanceType(offerings []InstanceTypeOffering, zone, instanceType string) (*InstanceTypeOffering, bool) {
+	for i := range offerings {
+		if offerings[i].InstanceType == instanceType && (zone == "" || offerings[i].Zone == zone) {
+			return &offerings[i], true
+		}
+	}
+	return nil, false
+}
+
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
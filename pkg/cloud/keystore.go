@@ -0,0 +1,92 @@
+package cloud
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KeyStore 私钥存储
+// 云厂商只在创建密钥对时返回一次私钥, 需要自行保存, 用于节点初始化时 SSH 登录
+type KeyStore interface {
+
+	// Save 保存私钥, name 一般为密钥对ID
+	Save(name, privateKey string) error
+
+	// Load 读取私钥
+	Load(name string) (string, error)
+
+	// Delete 删除私钥, 不存在时不报错
+	Delete(name string) error
+}
+
+// FileKeyStore 本地文件私钥存储, 目录权限 0700, 文件权限 0600
+type FileKeyStore struct {
+	Dir string // 存储目录
+}
+
+// NewFileKeyStore 实例化, dir 为空时使用 ~/.k8s-aim/keys
+func NewFileKeyStore(dir string) *FileKeyStore {
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".k8s-aim", "keys")
+	}
+	return &FileKeyStore{Dir: dir}
+}
+
+// Save 保存私钥
+func (s *FileKeyStore) Save(name, privateKey string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	// 先写临时文件再重命名, 避免中途失败留下不完整的私钥
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(privateKey), 0600); err != nil {
+		return err
+	}
+	// WriteFile 不会修改已存在文件的权限
+	if err := os.Chmod(tmp, 0600); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load 读取私钥
+func (s *FileKeyStore) Load(name string) (string, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Delete 删除私钥
+func (s *FileKeyStore) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path 私钥文件路径, 拒绝包含路径分隔符的名称
+func (s *FileKeyStore) path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid key name %q", name)
+	}
+	return filepath.Join(s.Dir, name+".pem"), nil
+}
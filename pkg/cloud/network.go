@@ -1,5 +1,92 @@
 package cloud
 
+import (
+	"fmt"
+	"sort"
+)
+
+// Vpc 私有网络
+type Vpc struct {
+	VpcId     string // 私有网络ID
+	Name      string // 名称
+	CidrBlock string // 网段
+	IsDefault bool   // 是否默认私有网络
+}
+
+// Subnet 子网
+type Subnet struct {
+	SubnetId         string // 子网ID
+	VpcId            string // 所属私有网络ID
+	Name             string // 名称
+	CidrBlock        string // 网段
+	Zone             string // 可用区
+	AvailableIpCount int64  // 可用IP数
+	IsDefault        bool   // 是否默认子网
+}
+
+// Address 弹性公网IP
+type Address struct {
+	AddressId  string            // 弹性公网IP ID
+	Ip         string            // 公网IP地址
+	Status     string            // 状态
+	InstanceId string            // 绑定的实例ID, 未绑定时为空
+	Tags       map[string]string // 标签
+}
+
+// NetworkInterface 弹性网卡
+type NetworkInterface struct {
+	NetworkInterfaceId string   // 弹性网卡ID
+	VpcId              string   // 私有网络ID
+	SubnetId           string   // 子网ID
+	InstanceId         string   // 绑定的实例ID
+	MacAddress         string   // MAC地址
+	Primary            bool     // 是否主网卡
+	State              string   // 状态
+	PrivateIps         []string // 内网IP
+	PublicIps          []string // 公网IP
+}
+
 // NetWork 实例网络
 type NetWork interface {
+
+	// DescribeVpcs 查询私有网络
+	DescribeVpcs() ([]Vpc, error)
+
+	// DescribeSubnets 查询子网, vpcId、zone 为空时不过滤
+	DescribeSubnets(vpcId, zone string) ([]Subnet, error)
+
+	// DescribeAddresses 查询弹性公网IP, addressIds 为空时查询全部
+	DescribeAddresses(addressIds ...string) ([]Address, error)
+
+	// AllocateAddress 申请弹性公网IP, bandwidth 为公网出带宽上限(Mbps)
+	AllocateAddress(bandwidth int64) (*Address, error)
+
+	// AssociateAddress 绑定弹性公网IP到实例
+	AssociateAddress(addressId, instanceId string) error
+
+	// DisassociateAddress 解绑弹性公网IP
+	DisassociateAddress(addressId string) error
+
+	// ReleaseAddress 释放弹性公网IP
+	ReleaseAddress(addressId string) error
+
+	// DescribeNetworkInterfaces 查询实例绑定的弹性网卡
+	DescribeNetworkInterfaces(instanceId string) ([]NetworkInterface, error)
+}
+
+// PickSubnet 在可用区内选择可用IP数不少于 need 的子网, 优先选择可用IP最多的子网
+func PickSubnet(subnets []Subnet, zone string, need int64) (*Subnet, error) {
+	var candidates []Subnet
+	for _, subnet := range subnets {
+		if subnet.Zone == zone && subnet.AvailableIpCount >= need {
+			candidates = append(candidates, subnet)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no subnet in zone %s has %d available ips", zone, need)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].AvailableIpCount > candidates[j].AvailableIpCount
+	})
+	return &candidates[0], nil
 }
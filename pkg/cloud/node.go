@@ -7,6 +7,7 @@ const (
 	AliYun    Manufacturers = "AliYun"    // 阿里云厂家
 	Tencent   Manufacturers = "Tencent"   // 腾讯云厂家
 	QingCloud Manufacturers = "QingCloud" // 青云
+	Fake      Manufacturers = "Fake"      // 内存模拟实现, 用于测试及离线演示
 )
 
 // ClusterNode kubernetes cluster node
@@ -14,7 +15,17 @@ type ClusterNode struct {
 	Name     string   // kubernetes cluster worker node name
 	HostName string   // ecs hostname
 	Ip       string   // ecs ip address
-	Tags     []string // kubernetes node tags
+	Tags     []string // kubernetes node tags, key=value 格式, 同时作为标签添加到节点实例
+}
+
+// NodeDrainer 关闭实例前腾空实例对应的 kubernetes 节点, 实例未加入集群时不做处理
+type NodeDrainer interface {
+
+	// DrainInstance 封锁并驱逐实例对应的节点
+	DrainInstance(instance InstanceInfo) error
+
+	// UncordonInstance 恢复实例对应节点的调度
+	UncordonInstance(instance InstanceInfo) error
 }
 
 // Node kubernetes cluster node
@@ -0,0 +1,142 @@
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNoAvailableZone 没有可放置节点的可用区
+var ErrNoAvailableZone = errors.New("cloud: no available zone")
+
+// Zone 可用区
+type Zone struct {
+	Zone      string // 可用区, 如 ap-guangzhou-3
+	Name      string // 可用区描述
+	Available bool   // 是否可用, 可用区或所在地域不可用时为 false
+}
+
+// Zones 可用区, 云厂商可选实现
+type Zones interface {
+
+	// DescribeZones 查询当前地域的可用区及可用状态
+	DescribeZones() ([]Zone, error)
+}
+
+// PlacementPolicy 可用区分布策略
+type PlacementPolicy string
+
+const (
+	PlacementBalanced PlacementPolicy = "balanced" // 均衡, 优先选择节点最少的可用区
+	PlacementPack     PlacementPolicy = "pack"     // 集中, 优先填满靠前的可用区, 达到上限后溢出到下一个
+	PlacementWeighted PlacementPolicy = "weighted" // 按权重比例分布
+	PlacementPinned   PlacementPolicy = "pinned"   // 固定在指定的可用区, 按顺序选择第一个可用的
+)
+
+// Placement 新节点的可用区分布策略
+type Placement struct {
+	Policy     PlacementPolicy // 策略, 为空时按 balanced 处理
+	Zones      []string        // 候选可用区, 按优先级排列, pinned 策略必填, 其他策略为空时使用全部可用区
+	Weights    map[string]int  // weighted 策略各可用区权重, 未配置或权重不大于0的可用区不参与
+	MaxPerZone int             // pack 策略每个可用区的节点数上限, 为0时不限
+}
+
+// Validate 校验策略配置
+func (p *Placement) Validate() error {
+	switch p.Policy {
+	case "", PlacementBalanced, PlacementPack:
+	case PlacementWeighted:
+		if len(p.Weights) == 0 {
+			return fmt.Errorf("weighted placement requires weights")
+		}
+	case PlacementPinned:
+		if len(p.Zones) == 0 {
+			return fmt.Errorf("pinned placement requires zones")
+		}
+	default:
+		return fmt.Errorf("unknown placement policy %q", p.Policy)
+	}
+	return nil
+}
+
+// Rank 按策略对可用的可用区排序, counts 为各可用区已有节点数, 第一个即新节点的可用区, 后续可作为备选
+// 不可用的可用区始终跳过
+func (p *Placement) Rank(zones []Zone, counts map[string]int) ([]string, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	var candidates []string
+	for _, zone := range zones {
+		switch {
+		case !zone.Available:
+		case len(p.Zones) > 0 && indexOf(p.Zones, zone.Zone) < 0:
+		case p.Policy == PlacementWeighted && p.Weights[zone.Zone] <= 0:
+		default:
+			candidates = append(candidates, zone.Zone)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w for %s placement, zones %v", ErrNoAvailableZone, p.policy(), p.Zones)
+	}
+	// 未配置候选可用区时 indexOf 均为 -1, 按其他条件排序
+	priority := func(zone string) int {
+		return indexOf(p.Zones, zone)
+	}
+	var less func(a, b string) bool
+	switch p.policy() {
+	case PlacementBalanced:
+		less = func(a, b string) bool {
+			if counts[a] != counts[b] {
+				return counts[a] < counts[b]
+			}
+			return priority(a) < priority(b)
+		}
+	case PlacementPack:
+		full := func(zone string) bool {
+			return p.MaxPerZone > 0 && counts[zone] >= p.MaxPerZone
+		}
+		less = func(a, b string) bool {
+			if full(a) != full(b) {
+				return !full(a)
+			}
+			if priority(a) != priority(b) {
+				return priority(a) < priority(b)
+			}
+			return counts[a] > counts[b]
+		}
+	case PlacementWeighted:
+		// 新增一个节点后 节点数/权重 最小的可用区最欠缺节点
+		less = func(a, b string) bool {
+			x, y := (counts[a]+1)*p.Weights[b], (counts[b]+1)*p.Weights[a]
+			if x != y {
+				return x < y
+			}
+			if p.Weights[a] != p.Weights[b] {
+				return p.Weights[a] > p.Weights[b]
+			}
+			return priority(a) < priority(b)
+		}
+	case PlacementPinned:
+		less = func(a, b string) bool {
+			return priority(a) < priority(b)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a < b
+	})
+	return candidates, nil
+}
+
+func (p *Placement) policy() PlacementPolicy {
+	if p.Policy == "" {
+		return PlacementBalanced
+	}
+	return p.Policy
+}
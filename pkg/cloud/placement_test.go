@@ -0,0 +1,120 @@
+package cloud
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPlacementRank(t *testing.T) {
+	zones := []Zone{
+		{Zone: "zone-a", Available: true},
+		{Zone: "zone-b", Available: true},
+		{Zone: "zone-c", Available: true},
+		{Zone: "zone-d", Available: false},
+	}
+	tests := []struct {
+		name      string
+		placement Placement
+		counts    map[string]int
+		want      []string
+		err       error
+	}{
+		{
+			name:      "balanced prefers the zone with fewest nodes",
+			placement: Placement{Policy: PlacementBalanced},
+			counts:    map[string]int{"zone-a": 2, "zone-b": 1, "zone-c": 3},
+			want:      []string{"zone-b", "zone-a", "zone-c"},
+		},
+		{
+			name:      "empty policy is balanced",
+			placement: Placement{},
+			counts:    map[string]int{"zone-a": 1},
+			want:      []string{"zone-b", "zone-c", "zone-a"},
+		},
+		{
+			name:      "balanced breaks ties by zone priority",
+			placement: Placement{Policy: PlacementBalanced, Zones: []string{"zone-c", "zone-b", "zone-a"}},
+			want:      []string{"zone-c", "zone-b", "zone-a"},
+		},
+		{
+			name:      "pack fills the first zone",
+			placement: Placement{Policy: PlacementPack, Zones: []string{"zone-b", "zone-a"}, MaxPerZone: 3},
+			counts:    map[string]int{"zone-a": 1, "zone-b": 2},
+			want:      []string{"zone-b", "zone-a"},
+		},
+		{
+			name:      "pack overflows when the zone is full",
+			placement: Placement{Policy: PlacementPack, Zones: []string{"zone-b", "zone-a"}, MaxPerZone: 3},
+			counts:    map[string]int{"zone-a": 1, "zone-b": 3},
+			want:      []string{"zone-a", "zone-b"},
+		},
+		{
+			name:      "pack without priority prefers the fullest zone",
+			placement: Placement{Policy: PlacementPack},
+			counts:    map[string]int{"zone-b": 2, "zone-c": 1},
+			want:      []string{"zone-b", "zone-c", "zone-a"},
+		},
+		{
+			name:      "weighted follows the weight ratio",
+			placement: Placement{Policy: PlacementWeighted, Weights: map[string]int{"zone-a": 3, "zone-b": 1}},
+			counts:    map[string]int{"zone-a": 2, "zone-b": 0},
+			want:      []string{"zone-a", "zone-b"},
+		},
+		{
+			name:      "weighted moves to the zone short of nodes",
+			placement: Placement{Policy: PlacementWeighted, Weights: map[string]int{"zone-a": 3, "zone-b": 1}},
+			counts:    map[string]int{"zone-a": 3, "zone-b": 0},
+			want:      []string{"zone-b", "zone-a"},
+		},
+		{
+			name:      "pinned keeps the configured order",
+			placement: Placement{Policy: PlacementPinned, Zones: []string{"zone-d", "zone-c", "zone-a"}},
+			counts:    map[string]int{"zone-c": 5},
+			want:      []string{"zone-c", "zone-a"},
+		},
+		{
+			name:      "no available zone",
+			placement: Placement{Policy: PlacementPinned, Zones: []string{"zone-d"}},
+			err:       ErrNoAvailableZone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.placement.Rank(zones, tt.counts)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Rank = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlacementValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		placement Placement
+		ok        bool
+	}{
+		{"balanced", Placement{Policy: PlacementBalanced}, true},
+		{"pack", Placement{Policy: PlacementPack}, true},
+		{"weighted without weights", Placement{Policy: PlacementWeighted}, false},
+		{"pinned without zones", Placement{Policy: PlacementPinned}, false},
+		{"unknown policy", Placement{Policy: "random"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.placement.Validate(); (err == nil) != tt.ok {
+				t.Fatalf("Validate = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
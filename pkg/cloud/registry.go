@@ -0,0 +1,101 @@
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"github.com/eadydb/k8s-aim/config"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownManufacturer = errors.New("unknown cloud manufacturer")    // 未知的云厂商
+	ErrNotRegistered       = errors.New("cloud provider not registered") // 云厂商未注册实现
+	ErrNotConfigured       = errors.New("cloud provider not configured") // 云厂商缺少配置
+)
+
+// Factory 根据配置构建云厂商实现
+type Factory func(c *config.Config) (Provider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[Manufacturers]Factory)
+)
+
+// Register 注册云厂商实现, 一般在厂商包的 init 中调用, 重复注册会 panic
+func Register(m Manufacturers, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if factory == nil {
+		panic("cloud: register factory is nil for " + string(m))
+	}
+	if _, ok := factories[m]; ok {
+		panic("cloud: register called twice for " + string(m))
+	}
+	factories[m] = factory
+}
+
+// Registered 已注册的云厂商
+func Registered() []Manufacturers {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	list := make([]Manufacturers, 0, len(factories))
+	for m := range factories {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// ParseManufacturers 解析云厂商名称, 不区分大小写
+func ParseManufacturers(name string) (Manufacturers, error) {
+	name = strings.TrimSpace(name)
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	for _, m := range []Manufacturers{AliYun, Tencent, QingCloud, Fake} {
+		if strings.EqualFold(string(m), name) {
+			return m, nil
+		}
+	}
+	// 其他已注册的厂商
+	for m := range factories {
+		if strings.EqualFold(string(m), name) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q", ErrUnknownManufacturer, name)
+}
+
+// NewProvider 根据配置中的 manufacturers 构建云厂商实现
+func NewProvider(c *config.Config) (Provider, error) {
+	m, err := ParseManufacturers(c.Manufacturers)
+	if err != nil {
+		return nil, err
+	}
+	factoriesMu.RLock()
+	factory, ok := factories[m]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, registered: %v", ErrNotRegistered, m, Registered())
+	}
+	provider, err := factory(c)
+	if err != nil {
+		return nil, fmt.Errorf("create %s provider, %w", m, err)
+	}
+	return provider, nil
+}
+
+// KeyStoreAware 支持替换私钥存储的云厂商实现
+type KeyStoreAware interface {
+
+	// SetKeyStore 设置私钥存储
+	SetKeyStore(store KeyStore)
+}
+
+// DrainerAware 关闭实例前需要腾空节点的云厂商实现, 如轮换密钥
+type DrainerAware interface {
+
+	// SetDrainer 设置节点腾空实现
+	SetDrainer(drainer NodeDrainer)
+}
@@ -1,31 +1,122 @@
 package cloud
 
+import "time"
+
+// RuleDirection 安全组规则方向
+type RuleDirection string
+
+const (
+	RuleIngress RuleDirection = "Ingress" // 入站
+	RuleEgress  RuleDirection = "Egress"  // 出站
+)
+
+// 安全组规则协议
+const (
+	ProtocolTCP  = "TCP"
+	ProtocolUDP  = "UDP"
+	ProtocolICMP = "ICMP"
+	ProtocolALL  = "ALL"
+)
+
+// SecurityGroupInfo 安全组信息
+type SecurityGroupInfo struct {
+	SecurityGroupId string            // 安全组ID
+	Name            string            // 名称
+	Description     string            // 描述
+	CreatedTime     time.Time         // 创建时间
+	Tags            map[string]string // 标签
+}
+
+// SecurityRule 安全组规则, 只描述允许访问的规则
+type SecurityRule struct {
+	Direction   RuleDirection // 方向
+	Protocol    string        // 协议, TCP、UDP、ICMP、ALL
+	Port        string        // 端口, 如 22、30000-32767, 为空时不限
+	CidrBlock   string        // 对端网段
+	Description string        // 描述
+}
+
+// KubernetesWorkerRules kubernetes worker 节点规则预设
+// 允许 cidr 网段访问 SSH、kubelet、NodePort 及 CNI(flannel/calico VXLAN、calico BGP)端口, 出站不限制
+func KubernetesWorkerRules(cidr string) []SecurityRule {
+	return []SecurityRule{
+		{RuleIngress, ProtocolTCP, "22", cidr, "ssh"},
+		{RuleIngress, ProtocolTCP, "10250", cidr, "kubelet api"},
+		{RuleIngress, ProtocolTCP, "30000-32767", cidr, "kubernetes nodeport tcp"},
+		{RuleIngress, ProtocolUDP, "30000-32767", cidr, "kubernetes nodeport udp"},
+		{RuleIngress, ProtocolUDP, "8472", cidr, "flannel vxlan"},
+		{RuleIngress, ProtocolUDP, "4789", cidr, "calico vxlan"},
+		{RuleIngress, ProtocolTCP, "179", cidr, "calico bgp"},
+		{RuleIngress, ProtocolICMP, "", cidr, "icmp"},
+		{RuleEgress, ProtocolALL, "", "0.0.0.0/0", "allow all egress"},
+	}
+}
+
 // SecurityGroup 安全组
 type SecurityGroup interface {
 
+	// CreateSecurityGroup 创建安全组并添加规则, 返回安全组ID
+	CreateSecurityGroup(name, description string, rules []SecurityRule) (string, error)
+
+	// DescribeSecurityGroups 查询安全组, groupIds 为空时查询全部
+	DescribeSecurityGroups(groupIds ...string) ([]SecurityGroupInfo, error)
+
+	// DeleteSecurityGroup 删除安全组
+	DeleteSecurityGroup(groupId string) error
+
+	// DescribeRules 查询安全组规则
+	DescribeRules(groupId string) ([]SecurityRule, error)
+
+	// AddRules 添加安全组规则
+	AddRules(groupId string, rules []SecurityRule) error
+
+	// RemoveRules 删除安全组规则
+	RemoveRules(groupId string, rules []SecurityRule) error
+
 	// Bind 绑定安全组
-	Bind(obj interface{}) error
+	Bind(instanceIds []string, groupIds ...string) error
 
 	// UnBind 解绑安全组
-	UnBind(obj interface{}) error
+	UnBind(instanceIds []string, groupIds ...string) error
 }
 
+// KeyPair 密钥对
+type KeyPair struct {
+	KeyId       string            // 密钥对ID
+	Name        string            // 名称
+	PublicKey   string            // 公钥
+	PrivateKey  string            // 私钥, 仅创建时返回
+	InstanceIds []string          // 绑定的实例ID
+	CreatedTime time.Time         // 创建时间
+	Tags        map[string]string // 标签
+}
 
 // KeyParis 密钥
 type KeyParis interface {
 
-	// DescribeKeyPairs 查询密钥对
-	DescribeKeyPairs(obj interface{}) (interface{}, error)
+	// DescribeKeyPairs 查询密钥对, keyIds 为空时查询全部
+	DescribeKeyPairs(keyIds ...string) ([]KeyPair, error)
+
+	// CreateKeyPair 创建密钥对, 私钥保存到 KeyStore
+	CreateKeyPair(name string) (*KeyPair, error)
 
-	// CreateKeyPair 创建密钥对
-	CreateKeyPair(obj interface{}) (interface{}, error)
+	// ImportKeyPair 导入已有公钥, privateKey 不为空时保存到 KeyStore
+	ImportKeyPair(name, publicKey, privateKey string) (*KeyPair, error)
 
-	// BindKeyPairs 绑定密钥对
-	BindKeyPairs(obj interface{}) error
+	// BindKeyPairs 绑定密钥对, 实例原有的密钥将失效
+	BindKeyPairs(instanceIds []string, keyIds ...string) error
 
 	// UnBindKeyPairs 解绑密钥对
-	UnBindKeyPairs(obj interface{}) error
+	UnBindKeyPairs(instanceIds []string, keyIds ...string) error
+
+	// DeleteKeyPairs 删除密钥对及保存的私钥
+	DeleteKeyPairs(keyIds ...string) error
+}
+
+// KeyRotator 支持轮换实例密钥的云厂商实现
+type KeyRotator interface {
 
-	// DeleteKeyPairs 删除密钥对
-	DeleteKeyPairs(obj interface{}) error
+	// RotateKeyPair 创建名为 name 的新密钥对并绑定到实例, 旧密钥对不再绑定任何实例时删除
+	// forceStop 为 true 时需关机绑定的实例在软关机失败后强制关机
+	RotateKeyPair(oldKeyId, name string, instanceIds []string, forceStop bool) (*KeyPair, error)
 }
@@ -0,0 +1,29 @@
+package cloud
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotSupported 云厂商不支持该功能
+var ErrNotSupported = errors.New("cloud: not supported")
+
+// SpotOptions 竞价实例参数
+type SpotOptions struct {
+	MaxPrice float64 // 最高出价, 单位元/小时, 为0时使用云厂商默认出价, 腾讯云使用按量计费单价
+	SpotType string  // 竞价请求类型, 取值与云厂商相关, 如腾讯云 one-time, 为空时使用默认值
+}
+
+// InterruptionSource 可以查询任意竞价实例回收通知的云厂商实现, 如通过云 API 查询
+// 只能通过实例元数据查询本机回收通知的云厂商(如腾讯云、阿里云)不实现该接口, 无法在管理节点上集中检查
+type InterruptionSource interface {
+
+	// RemoteInterruption 是否可以查询非本机实例的回收通知
+	RemoteInterruption() bool
+}
+
+// Interruption 竞价实例回收通知
+type Interruption struct {
+	InstanceId string    // 实例ID
+	Time       time.Time // 预计回收时间
+}
@@ -0,0 +1,101 @@
+package cloud
+
+import (
+	"fmt"
+	"github.com/eadydb/k8s-aim/config"
+	"strings"
+)
+
+// 标准标签, k8s-aim 创建的实例、云硬盘、弹性公网IP、安全组及密钥对均带有这些标签, 用于成本分摊及识别自己创建的资源
+const (
+	TagCluster   = "k8s-aim/cluster"    // 集群名称
+	TagNodePool  = "k8s-aim/node-pool"  // 节点池
+	TagCreatedBy = "k8s-aim/created-by" // 创建者, 固定为 CreatedBy
+	TagOwner     = "k8s-aim/owner"      // 负责人
+)
+
+// CreatedBy TagCreatedBy 标签的值
+const CreatedBy = "k8s-aim"
+
+// ResourceTags 创建云资源时添加的标签
+type ResourceTags struct {
+	Cluster  string            // 集群名称
+	NodePool string            // 节点池
+	Owner    string            // 负责人
+	Extra    map[string]string // 其他标签, 与标准标签同名时以标准标签为准
+}
+
+// NewResourceTags 根据配置构建资源标签, 未配置时只包含 TagCreatedBy
+func NewResourceTags(c *config.Tags) ResourceTags {
+	if c == nil {
+		return ResourceTags{}
+	}
+	return ResourceTags{Cluster: c.Cluster, NodePool: c.NodePool, Owner: c.Owner, Extra: c.Extra}
+}
+
+// Tags 创建资源时添加的全部标签, 值为空的标准标签不添加
+func (t ResourceTags) Tags() map[string]string {
+	tags := MergeTags(t.Extra, t.Selector())
+	if t.Owner != "" {
+		tags[TagOwner] = t.Owner
+	}
+	return tags
+}
+
+// Selector 查询资源时用于过滤的标签, 即创建者、集群及节点池
+// 不包含负责人, 负责人变更后仍能查到之前创建的资源
+func (t ResourceTags) Selector() map[string]string {
+	tags := map[string]string{TagCreatedBy: CreatedBy}
+	if t.Cluster != "" {
+		tags[TagCluster] = t.Cluster
+	}
+	if t.NodePool != "" {
+		tags[TagNodePool] = t.NodePool
+	}
+	return tags
+}
+
+// Owns 资源标签是否满足 Selector, 即是否为当前集群及节点池创建的资源
+func (t ResourceTags) Owns(tags map[string]string) bool {
+	for key, value := range t.Selector() {
+		if v, ok := tags[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// TagAware 支持为创建的资源添加标签并按标签过滤查询结果的云厂商实现
+type TagAware interface {
+
+	// SetTags 设置资源标签
+	SetTags(tags ResourceTags)
+}
+
+// MergeTags 合并标签, 返回新的标签, 同名时后面的覆盖前面的
+func MergeTags(tags ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, m := range tags {
+		for key, value := range m {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// ParseTags 解析 key=value 格式的标签, 只有 key 时值为空
+func ParseTags(tags []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value := tag, ""
+		if i := strings.Index(tag, "="); i >= 0 {
+			key, value = tag[:i], tag[i+1:]
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid tag %q, expected key=value", tag)
+		}
+		parsed[key] = strings.TrimSpace(value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,116 @@
+package cloud
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ClientTokenTag 记录幂等令牌的实例标签, 创建结果不确定时按该标签查找已创建的实例
+const ClientTokenTag = "k8s-aim/client-token"
+
+// TokenStore 幂等令牌存储
+// 按请求键(如节点名称)保存创建实例使用的 ClientToken, 创建成功前重试或进程重启后仍使用同一令牌, 避免重复创建
+type TokenStore interface {
+
+	// Load 读取令牌, 不存在时返回空字符串
+	Load(key string) (string, error)
+
+	// Save 保存令牌
+	Save(key, token string) error
+
+	// Delete 删除令牌, 不存在时不报错
+	Delete(key string) error
+}
+
+// NewClientToken 生成随机令牌, 32位十六进制字符串
+func NewClientToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ClientToken 读取 key 对应的令牌, 不存在时生成并保存
+func ClientToken(store TokenStore, key string) (string, error) {
+	token, err := store.Load(key)
+	if err != nil || token != "" {
+		return token, err
+	}
+	if token, err = NewClientToken(); err != nil {
+		return "", err
+	}
+	return token, store.Save(key, token)
+}
+
+// FileTokenStore 本地文件令牌存储
+type FileTokenStore struct {
+	Dir string // 存储目录
+}
+
+// NewFileTokenStore 实例化, dir 为空时使用 ~/.k8s-aim/tokens
+func NewFileTokenStore(dir string) *FileTokenStore {
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".k8s-aim", "tokens")
+	}
+	return &FileTokenStore{Dir: dir}
+}
+
+// Load 读取令牌
+func (s *FileTokenStore) Load(key string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save 保存令牌
+func (s *FileTokenStore) Save(key, token string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	// 先写临时文件再重命名, 避免中途失败留下不完整的令牌
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(token), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Delete 删除令牌
+func (s *FileTokenStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path 令牌文件路径, 拒绝包含路径分隔符的键
+func (s *FileTokenStore) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("invalid token key %q", key)
+	}
+	return filepath.Join(s.Dir, key+".token"), nil
+}
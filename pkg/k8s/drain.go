@@ -0,0 +1,91 @@
+package k8s
+
+import (
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+const (
+	mirrorPodAnnotation = "kubernetes.io/config.mirror" // 静态 Pod 的镜像 Pod
+	evictRetryInterval  = 5 * time.Second               // PodDisruptionBudget 不允许驱逐时的重试间隔
+)
+
+// Cordon 封锁节点, 禁止调度新的 Pod
+func (c *KClient) Cordon(nodeName string) error {
+	patch := []byte(`{"spec":{"unschedulable":true}}`)
+	_, err := c.ClientSet.CoreV1().Nodes().Patch(c.Ctx, nodeName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// Uncordon 恢复节点调度
+func (c *KClient) Uncordon(nodeName string) error {
+	patch := []byte(`{"spec":{"unschedulable":false}}`)
+	_, err := c.ClientSet.CoreV1().Nodes().Patch(c.Ctx, nodeName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// Drain 驱逐节点上的 Pod, 跳过 DaemonSet 管理的 Pod、静态 Pod 及已结束的 Pod
+// gracePeriod 为 Pod 优雅退出时间, 为0时使用 Pod 自身配置; PodDisruptionBudget 不允许驱逐时重试直到 timeout
+func (c *KClient) Drain(nodeName string, gracePeriod, timeout time.Duration) error {
+	pods, err := c.ClientSet.CoreV1().Pods(metav1.NamespaceAll).List(c.Ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	var failed []string
+	for _, pod := range pods.Items {
+		if !evictable(&pod) {
+			continue
+		}
+		if err := c.evict(&pod, gracePeriod, deadline); err != nil {
+			failed = append(failed, fmt.Sprintf("%s/%s: %s", pod.Namespace, pod.Name, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("drain node %s, evict pods failed %v", nodeName, failed)
+	}
+	return nil
+}
+
+// evict 驱逐 Pod, 被 PodDisruptionBudget 拒绝时重试
+func (c *KClient) evict(pod *corev1.Pod, gracePeriod time.Duration, deadline time.Time) error {
+	eviction := &policyv1beta1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+	if gracePeriod > 0 {
+		seconds := int64(gracePeriod / time.Second)
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: &seconds}
+	}
+	for {
+		err := c.ClientSet.CoreV1().Pods(pod.Namespace).Evict(c.Ctx, eviction)
+		switch {
+		case err == nil, errors.IsNotFound(err):
+			return nil
+		case !errors.IsTooManyRequests(err) || time.Now().Add(evictRetryInterval).After(deadline):
+			return err
+		}
+		time.Sleep(evictRetryInterval)
+	}
+}
+
+// evictable Pod 是否需要驱逐
+func evictable(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller && owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+)
+
+// 节点可用区标签
+const (
+	LabelTopologyZone      = "topology.kubernetes.io/zone"
+	LabelFailureDomainZone = "failure-domain.beta.kubernetes.io/zone" // 已废弃, 兼容旧版本集群
+)
+
+// NodeZones 按可用区统计集群节点数, 未设置可用区标签的节点不计入, selector 为标签选择器, 为空时统计全部节点
+func (c *KClient) NodeZones(selector string) (map[string]int, error) {
+	nodes, err := c.ClientSet.CoreV1().Nodes().List(c.Ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, node := range nodes.Items {
+		zone := node.Labels[LabelTopologyZone]
+		if zone == "" {
+			zone = node.Labels[LabelFailureDomainZone]
+		}
+		if zone != "" {
+			counts[zone]++
+		}
+	}
+	return counts, nil
+}
+
+// NodeRefs 集群节点的 providerID 及IP, 用于判断云上实例是否已加入集群
+type NodeRefs struct {
+	ProviderIds []string        // 节点 spec.providerID, 如 qcloud:///800002/ins-xxx、cn-hangzhou.i-xxx
+	Ips         map[string]bool // 节点 InternalIP 及 ExternalIP
+}
+
+// NodeRefs 查询全部集群节点的 providerID 及IP
+func (c *KClient) NodeRefs() (*NodeRefs, error) {
+	nodes, err := c.ClientSet.CoreV1().Nodes().List(c.Ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return newNodeRefs(nodes.Items...), nil
+}
+
+// NodeName 查询实例对应的集群节点名称, 按 providerID 或IP匹配, 实例未加入集群时返回空字符串
+func (c *KClient) NodeName(instanceId string, ips ...string) (string, error) {
+	nodes, err := c.ClientSet.CoreV1().Nodes().List(c.Ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, node := range nodes.Items {
+		if newNodeRefs(node).Has(instanceId, ips...) {
+			return node.Name, nil
+		}
+	}
+	return "", nil
+}
+
+func newNodeRefs(nodes ...corev1.Node) *NodeRefs {
+	refs := &NodeRefs{Ips: make(map[string]bool)}
+	for _, node := range nodes {
+		if node.Spec.ProviderID != "" {
+			refs.ProviderIds = append(refs.ProviderIds, node.Spec.ProviderID)
+		}
+		for _, address := range node.Status.Addresses {
+			if address.Type == corev1.NodeInternalIP || address.Type == corev1.NodeExternalIP {
+				refs.Ips[address.Address] = true
+			}
+		}
+	}
+	return refs
+}
+
+// Has 实例是否对应某个集群节点, providerID 以实例ID结尾或任一IP属于集群节点时为 true
+func (r *NodeRefs) Has(instanceId string, ips ...string) bool {
+	for _, providerId := range r.ProviderIds {
+		if providerId == instanceId || strings.HasSuffix(providerId, "/"+instanceId) || strings.HasSuffix(providerId, "."+instanceId) {
+			return true
+		}
+	}
+	for _, ip := range ips {
+		if r.Ips[ip] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+)
+
+const (
+	secretPrivateKey = "ssh-privatekey" // kubernetes.io/ssh-auth 类型 Secret 的私钥字段
+	secretKeyLabel   = "k8s-aim/key-pair"
+)
+
+// SecretKeyStore 使用 kubernetes Secret 保存私钥
+type SecretKeyStore struct {
+	Client *KClient // kubernetes cluster client
+	Prefix string   // Secret 名称前缀
+}
+
+// NewSecretKeyStore 实例化, prefix 为空时使用 k8s-aim-key-
+func NewSecretKeyStore(client *KClient, prefix string) *SecretKeyStore {
+	if prefix == "" {
+		prefix = "k8s-aim-key-"
+	}
+	return &SecretKeyStore{Client: client, Prefix: prefix}
+}
+
+// Save 保存私钥, 已存在时覆盖
+func (s *SecretKeyStore) Save(name, privateKey string) error {
+	secrets := s.Client.ClientSet.CoreV1().Secrets(s.Client.NameSpace)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   s.secretName(name),
+			Labels: map[string]string{secretKeyLabel: name},
+		},
+		Type:       corev1.SecretTypeSSHAuth,
+		StringData: map[string]string{secretPrivateKey: privateKey},
+	}
+	_, err := secrets.Create(s.Client.Ctx, secret, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		_, err = secrets.Update(s.Client.Ctx, secret, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// Load 读取私钥
+func (s *SecretKeyStore) Load(name string) (string, error) {
+	secret, err := s.Client.ClientSet.CoreV1().Secrets(s.Client.NameSpace).Get(s.Client.Ctx, s.secretName(name), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	key, ok := secret.Data[secretPrivateKey]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no %s", secret.Namespace, secret.Name, secretPrivateKey)
+	}
+	return string(key), nil
+}
+
+// Delete 删除私钥
+func (s *SecretKeyStore) Delete(name string) error {
+	err := s.Client.ClientSet.CoreV1().Secrets(s.Client.NameSpace).Delete(s.Client.Ctx, s.secretName(name), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// secretName Secret 名称需符合 DNS-1123 规范
+func (s *SecretKeyStore) secretName(name string) string {
+	return s.Prefix + strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
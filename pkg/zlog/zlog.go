@@ -257,7 +257,7 @@ func Debug(msg string, fields ...zapcore.Field) {
 
 // Debugf uses fmt.Sprintf to log a templated message.
 func Debugf(template string, args ...interface{}) {
-	zLogger.Debugf(template, args)
+	zLogger.Debugf(template, args...)
 }
 
 // Debugw logs a message with some additional context. The variadic key-value
@@ -266,7 +266,7 @@ func Debugf(template string, args ...interface{}) {
 // When debug-level logging is disabled, this is much faster than
 //  s.With(keysAndValues).Debug(msg)
 func Debugw(msg string, keysAndValues ...interface{}) {
-	zLogger.Debugw(msg, keysAndValues)
+	zLogger.Debugw(msg, keysAndValues...)
 }
 
 // Info logs a message at InfoLevel. The message includes any fields passed
@@ -283,7 +283,7 @@ func Infof(template string, args ...interface{}) {
 // Infow logs a message with some additional context. The variadic key-value
 // pairs are treated as they are in With.
 func Infow(msg string, keysAndValues ...interface{}) {
-	zLogger.Infow(msg, keysAndValues)
+	zLogger.Infow(msg, keysAndValues...)
 }
 
 // Warn logs a message at WarnLevel. The message includes any fields passed
@@ -300,7 +300,7 @@ func Warnf(template string, args ...interface{}) {
 // Warnw logs a message with some additional context. The variadic key-value
 // pairs are treated as they are in With.
 func Warnw(msg string, keysAndValues ...interface{}) {
-	zLogger.Warnw(msg, keysAndValues)
+	zLogger.Warnw(msg, keysAndValues...)
 }
 
 // Error logs a message at ErrorLevel. The message includes any fields passed
@@ -317,7 +317,7 @@ func Errorf(template string, args ...interface{}) {
 // Errorw logs a message with some additional context. The variadic key-value
 // pairs are treated as they are in With.
 func Errorw(msg string, keysAndValues ...interface{}) {
-	zLogger.Errorw(msg, keysAndValues)
+	zLogger.Errorw(msg, keysAndValues...)
 }
 
 // Panic logs a message at PanicLevel. The message includes any fields passed
@@ -336,7 +336,7 @@ func Panicf(template string, args ...interface{}) {
 // Panicw logs a message with some additional context, then panics. The
 // variadic key-value pairs are treated as they are in With.
 func Panicw(msg string, keysAndValues ...interface{}) {
-	zLogger.Panicw(msg, keysAndValues)
+	zLogger.Panicw(msg, keysAndValues...)
 }
 
 // Fatal logs a message at FatalLevel. The message includes any fields passed
@@ -356,5 +356,5 @@ func Fatalf(template string, args ...interface{}) {
 // Fatalw logs a message with some additional context, then calls os.Exit. The
 // variadic key-value pairs are treated as they are in With.
 func Fatalw(msg string, keysAndValues ...interface{}) {
-	zLogger.Fatalw(msg, keysAndValues)
+	zLogger.Fatalw(msg, keysAndValues...)
 }